@@ -0,0 +1,59 @@
+// Command listd serves named lists of strings over TCP and/or a Unix socket, so local tools can share them.
+//
+//	go run ./cmd/listd -tcp 127.0.0.1:7070
+//	go run ./cmd/listd -unix /tmp/listd.sock
+//
+// See package listd for the protocol; package listd/client is its Go client.
+// Lists live in memory and are lost when listd stops.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gbenroscience/linkedlist/listd"
+)
+
+func main() {
+	tcpAddr := flag.String("tcp", "", "TCP address to listen on, e.g. 127.0.0.1:7070")
+	unixPath := flag.String("unix", "", "Unix socket to listen on, e.g. /tmp/listd.sock")
+	flag.Parse()
+
+	if *tcpAddr == "" && *unixPath == "" {
+		fmt.Fprintln(os.Stderr, "listd: give -tcp and/or -unix")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	srv := listd.NewServer(listd.NewStore())
+	errs := make(chan error, 2)
+	serve := func(network string, addr string) {
+		l, err := net.Listen(network, addr)
+		if err != nil {
+			log.Fatalf("listd: %v", err)
+		}
+		log.Printf("listd: listening on %s %s", network, l.Addr())
+		go func() { errs <- srv.Serve(l) }()
+	}
+	if *tcpAddr != "" {
+		serve("tcp", *tcpAddr)
+	}
+	if *unixPath != "" {
+		serve("unix", *unixPath)
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	select {
+	case <-stop:
+	case err := <-errs:
+		log.Printf("listd: %v", err)
+	}
+	// closing the listener also removes the Unix socket file
+	srv.Close()
+}
@@ -0,0 +1,241 @@
+package ds
+
+import "sync"
+
+// Node allocation strategies.
+//
+// By default every Add allocates a fresh node and every removal leaves the
+// old one to the garbage collector. Queues with a lot of churn pay for that
+// twice: in allocations and in GC work. WithAllocator lets a List or AnyList
+// recycle its nodes instead:
+//
+//   - AllocFreeList keeps removed nodes on a per-list stack and hands them out again.
+//     It is the fastest option, but the stack grows as large as the list once was.
+//   - AllocPool parks removed nodes in a sync.Pool, which the runtime trims on every GC cycle.
+//   - AllocArena carves nodes out of slabs of arenaSlabSize nodes, so a list of n
+//     elements costs about n/arenaSlabSize allocations, and recycles removed nodes
+//     the way AllocFreeList does.
+//
+// Compact hands the memory held by the allocator back to the garbage collector.
+// For an arena, slabs that still hold live nodes stay in use until their last
+// node is removed; the others are released.
+//
+// Recycled nodes are reused, so a SubList whose first or last element has
+// been removed through another list must not be used any more.
+
+// AllocMode - Selects how a list allocates its nodes
+type AllocMode int
+
+const (
+	// AllocDefault allocates every node on its own and lets the garbage collector reclaim it
+	AllocDefault AllocMode = iota
+	// AllocFreeList recycles removed nodes through a per-list free list
+	AllocFreeList
+	// AllocPool recycles removed nodes through a sync.Pool
+	AllocPool
+	// AllocArena allocates nodes in slabs and recycles removed nodes
+	AllocArena
+)
+
+// arenaSlabSize - The number of nodes allocated at once by AllocArena
+const arenaSlabSize = 256
+
+// nodeAllocator - Hands out and takes back the nodes of a list. N is the node struct type.
+type nodeAllocator[N any] interface {
+	alloc() *N
+	// free takes back a node that is no longer linked anywhere
+	free(n *N)
+	compact()
+}
+
+func newNodeAllocator[N any](mode AllocMode) nodeAllocator[N] {
+	switch mode {
+	case AllocFreeList:
+		return new(freeListAllocator[N])
+	case AllocPool:
+		return newPoolAllocator[N]()
+	case AllocArena:
+		return new(arenaAllocator[N])
+	default:
+		return nil
+	}
+}
+
+// freeListAllocator - Recycles nodes through a stack of freed nodes
+type freeListAllocator[N any] struct {
+	freed []*N
+}
+
+func (a *freeListAllocator[N]) alloc() *N {
+	if last := len(a.freed) - 1; last >= 0 {
+		n := a.freed[last]
+		a.freed[last] = nil
+		a.freed = a.freed[:last]
+		return n
+	}
+	return new(N)
+}
+
+func (a *freeListAllocator[N]) free(n *N) {
+	var zero N
+	*n = zero
+	a.freed = append(a.freed, n)
+}
+
+func (a *freeListAllocator[N]) compact() {
+	a.freed = nil
+}
+
+// poolAllocator - Recycles nodes through a sync.Pool
+type poolAllocator[N any] struct {
+	pool *sync.Pool
+}
+
+func newPoolAllocator[N any]() *poolAllocator[N] {
+	return &poolAllocator[N]{pool: newNodePool[N]()}
+}
+
+func newNodePool[N any]() *sync.Pool {
+	return &sync.Pool{
+		New: func() any {
+			return new(N)
+		},
+	}
+}
+
+func (a *poolAllocator[N]) alloc() *N {
+	return a.pool.Get().(*N)
+}
+
+func (a *poolAllocator[N]) free(n *N) {
+	var zero N
+	*n = zero
+	a.pool.Put(n)
+}
+
+func (a *poolAllocator[N]) compact() {
+	a.pool = newNodePool[N]()
+}
+
+// arenaAllocator - Allocates nodes in slabs and recycles freed nodes
+type arenaAllocator[N any] struct {
+	slab  []N
+	used  int
+	freed freeListAllocator[N]
+}
+
+func (a *arenaAllocator[N]) alloc() *N {
+	if len(a.freed.freed) > 0 {
+		return a.freed.alloc()
+	}
+	if a.used == len(a.slab) {
+		a.slab = make([]N, arenaSlabSize)
+		a.used = 0
+	}
+	n := &a.slab[a.used]
+	a.used++
+	return n
+}
+
+func (a *arenaAllocator[N]) free(n *N) {
+	a.freed.free(n)
+}
+
+// compact ...Forgets the current slab and the freed nodes. A slab is only kept
+// alive by the nodes carved out of it, so the garbage collector reclaims every
+// slab whose nodes are all gone.
+func (a *arenaAllocator[N]) compact() {
+	a.slab = nil
+	a.used = 0
+	a.freed.compact()
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// WithAllocator ...Selects how the list allocates its nodes and returns the list.
+// Call it before the list is shared between goroutines. When called on a sublist
+// it applies to the list at the top of the sublist chain.
+func (list *AnyList[T]) WithAllocator(mode AllocMode) *AnyList[T] {
+	defer list.unlock()
+	list.lock("WithAllocator")
+
+	list.root().alloc = newNodeAllocator[node[T]](mode)
+	return list
+}
+
+// Compact ...Releases the memory the allocator holds on to for recycling. See WithAllocator.
+func (list *AnyList[T]) Compact() {
+	defer list.unlock()
+	list.lock("Compact")
+
+	if root := list.root(); root.alloc != nil {
+		root.alloc.compact()
+	}
+}
+
+// newNode ...Creates a node through the root's allocator
+func (list *AnyList[T]) newNode(prev *node[T], val T, next *node[T]) *node[T] {
+	alloc := list.root().alloc
+	if alloc == nil {
+		return init_node(prev, val, next)
+	}
+	n := alloc.alloc()
+	n.prev = prev
+	n.next = next
+	n.val = val
+	return n
+}
+
+// releaseNode ...Gives an unlinked node back to the root's allocator
+func (list *AnyList[T]) releaseNode(n *node[T]) {
+	if alloc := list.root().alloc; alloc != nil {
+		alloc.free(n)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// WithAllocator ...Selects how the list allocates its nodes and returns the list.
+// Call it before the list is shared between goroutines. When called on a sublist
+// it applies to the list at the top of the sublist chain.
+func (list *List[T]) WithAllocator(mode AllocMode) *List[T] {
+	defer list.unlock()
+	list.lock("WithAllocator")
+
+	list.root().alloc = newNodeAllocator[lNode[T]](mode)
+	return list
+}
+
+// Compact ...Releases the memory the allocator holds on to for recycling. See WithAllocator.
+func (list *List[T]) Compact() {
+	defer list.unlock()
+	list.lock("Compact")
+
+	if root := list.root(); root.alloc != nil {
+		root.alloc.compact()
+	}
+}
+
+// newNode ...Creates a node through the root's allocator
+func (list *List[T]) newNode(prev *lNode[T], val T, next *lNode[T]) *lNode[T] {
+	alloc := list.root().alloc
+	if alloc == nil {
+		return initNode(prev, val, next)
+	}
+	n := alloc.alloc()
+	n.prev = prev
+	n.next = next
+	n.val = val
+	return n
+}
+
+// releaseNode ...Gives an unlinked node back to the root's allocator
+func (list *List[T]) releaseNode(n *lNode[T]) {
+	if alloc := list.root().alloc; alloc != nil {
+		alloc.free(n)
+	}
+}
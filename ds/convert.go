@@ -0,0 +1,160 @@
+package ds
+
+import (
+	stdlist "container/list"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Converting to and from the standard library.
+//
+// The From functions append values to a List, an AnyList or a CList in one
+// step, which is one undo step and one event for subscribers, and return the
+// list, so they read as constructors:
+//
+//	names := ds.FromMapKeys(ds.NewList[string](), ages, strings.Compare)
+//	jobs := ds.FromSeq(ds.NewAnyList[Job](), maps.Values(byID))
+//
+// All returns an iter.Seq over the values of a list, so a list also plugs
+// into slices.Collect, maps.Collect and range-over-func loops. All yields the
+// values the list held when iteration began, so the loop body may change the
+// list. Collect builds a List from an iter.Seq, as slices.Collect builds a
+// slice.
+//
+// FromContainerList and ToContainerList convert from and to container/list.
+// To move code off container/list without rewriting it, see the list package
+// of this module, which mirrors the API of container/list on top of AnyList.
+
+// listBuilder - The lists the From functions fill: List, AnyList and CList
+type listBuilder[T any] interface {
+	AddArray(array []T)
+}
+
+// listSource - The lists ToContainerList reads: List, AnyList and CList
+type listSource[T any] interface {
+	ToArray() []T
+}
+
+// FromSlice ...Appends the values of the slice to list and returns the list
+func FromSlice[L listBuilder[T], T any](list L, values []T) L {
+	list.AddArray(values)
+	return list
+}
+
+// FromSeq ...Appends the values seq yields to list and returns the list
+func FromSeq[L listBuilder[T], T any](list L, seq iter.Seq[T]) L {
+	list.AddArray(slices.Collect(seq))
+	return list
+}
+
+// Collect ...Returns a List of the values seq yields
+func Collect[T comparable](seq iter.Seq[T]) *List[T] {
+	return FromSeq(NewList[T](), seq)
+}
+
+// FromMapKeys ...Appends the keys of m to list, sorted by cmp, or in the map's random order if cmp is nil,
+// and returns the list
+func FromMapKeys[L listBuilder[K], K comparable, V any](list L, m map[K]V, cmp func(a, b K) int) L {
+	keys := slices.Collect(maps.Keys(m))
+	if cmp != nil {
+		slices.SortFunc(keys, cmp)
+	}
+	list.AddArray(keys)
+	return list
+}
+
+// FromMapValues ...Appends the values of m to list, sorted by cmp, or in the map's random order if cmp is nil,
+// and returns the list
+func FromMapValues[L listBuilder[V], K comparable, V any](list L, m map[K]V, cmp func(a, b V) int) L {
+	values := slices.Collect(maps.Values(m))
+	if cmp != nil {
+		slices.SortStableFunc(values, cmp)
+	}
+	list.AddArray(values)
+	return list
+}
+
+// FromContainerList ...Appends the values of src, front to back, to list and returns the list.
+// Fails, leaving list as it was, if a value of src is not a T.
+func FromContainerList[L listBuilder[T], T any](list L, src *stdlist.List) (L, error) {
+	values := make([]T, 0, src.Len())
+	i := 0
+	for e := src.Front(); e != nil; e = e.Next() {
+		val, ok := e.Value.(T)
+		if !ok {
+			return list, fmt.Errorf("value %d of the container/list is a %T, not a %T", i, e.Value, val)
+		}
+		values = append(values, val)
+		i++
+	}
+	list.AddArray(values)
+	return list, nil
+}
+
+// ToContainerList ...Returns a container/list holding the values of list
+func ToContainerList[T any](src listSource[T]) *stdlist.List {
+	dst := stdlist.New()
+	for _, val := range src.ToArray() {
+		dst.PushBack(val)
+	}
+	return dst
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// All ...Returns an iterator over the values the list holds when iteration begins, first to last
+func (list *AnyList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		list.lock("All")
+		values := list.values()
+		list.unlock()
+
+		for _, val := range values {
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// All ...Returns an iterator over the values the list holds when iteration begins, first to last
+func (list *List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		list.lock("All")
+		values := list.values()
+		list.unlock()
+
+		for _, val := range values {
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// CList
+// ---------------------------------------------------------------------------
+
+// All ...Returns an iterator over the values the list holds when iteration begins, first to last
+func (list *CList) All() iter.Seq[interface{}] {
+	return func(yield func(interface{}) bool) {
+		list.mu.Lock()
+		values := list.values()
+		list.mu.Unlock()
+
+		for _, val := range values {
+			if !yield(val) {
+				return
+			}
+		}
+	}
+}
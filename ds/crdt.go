@@ -0,0 +1,336 @@
+package ds
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// Collaborative editing.
+//
+// A CRDTList is a replicated growable array (RGA): every replica of a
+// document holds one, edits its own copy without waiting for the others,
+// and sends each CRDTOp its edits return to every other replica, which
+// merges it. Replicas that have merged the same ops hold the same values,
+// whatever order the ops arrived in.
+//
+// Every element gets a unique OpID when it is inserted: a Lamport clock and
+// the name of the replica. An insert names the element it goes after;
+// concurrent inserts after the same element are ordered by OpID, the highest
+// first. Removing an element only marks it removed, leaving a tombstone,
+// because inserts made concurrently may still name it. Merge holds back ops
+// that name an element it has not seen yet and applies them once it has.
+//
+// The ops of each replica must be merged in the order that replica made them,
+// as a stream per replica does; across replicas any order works, and an op
+// merged twice is ignored. Version tells how far a replica got. GC drops the
+// tombstones once every replica has caught up with all the others, which is
+// when no op still to come can name them.
+
+// OpID - Identifies an op, and the element an insert creates
+type OpID struct {
+	Clock   uint64 `json:"clock"`
+	Replica string `json:"replica"`
+}
+
+// after ...Reports whether id orders after other: the higher clock wins, then the greater replica name
+func (id OpID) after(other OpID) bool {
+	if id.Clock != other.Clock {
+		return id.Clock > other.Clock
+	}
+	return id.Replica > other.Replica
+}
+
+// CRDTOpKind - Whether a CRDTOp inserts or removes an element
+type CRDTOpKind int
+
+const (
+	// CRDTInsert - Inserts Value after the element Ref, or first if Ref is the zero OpID
+	CRDTInsert CRDTOpKind = iota
+	// CRDTRemove - Removes the element Ref
+	CRDTRemove
+)
+
+// CRDTOp - An edit made to a replica of a CRDTList, to be merged into the others
+type CRDTOp[T any] struct {
+	Kind CRDTOpKind `json:"kind"`
+	ID   OpID       `json:"id"`
+	Ref  OpID       `json:"ref"`
+	// The value inserted
+	Value T `json:"value"`
+}
+
+// crdtEntry - An element of a CRDTList, removed or not
+type crdtEntry[T any] struct {
+	id      OpID
+	val     T
+	removed bool
+}
+
+// CRDTList - One replica of a list that many replicas edit concurrently, see crdt.go
+type CRDTList[T any] struct {
+	mu      sync.Mutex
+	replica string
+	clock   uint64
+	// every element, tombstones included, in list order
+	seq   *AnyList[crdtEntry[T]]
+	nodes map[OpID]*node[crdtEntry[T]]
+	// number of elements that are not removed
+	size int
+	// clock of the latest op merged from each replica, this one included
+	version map[string]uint64
+	// ops merged before an element they need, oldest first
+	pending []CRDTOp[T]
+}
+
+// NewCRDTList ...Creates an empty replica. Every replica of a document must have its own name.
+func NewCRDTList[T any](replica string) *CRDTList[T] {
+	return &CRDTList[T]{
+		replica: replica,
+		seq:     NewAnyList[crdtEntry[T]](),
+		nodes:   make(map[OpID]*node[crdtEntry[T]]),
+		version: make(map[string]uint64),
+	}
+}
+
+// Replica ...Returns the name of the replica
+func (list *CRDTList[T]) Replica() string {
+	return list.replica
+}
+
+// Add ...Appends val and returns the op to send to the other replicas
+func (list *CRDTList[T]) Add(val T) CRDTOp[T] {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	op, _ := list.insert(list.size, val)
+	return op
+}
+
+// AddVal ...Inserts val at index and returns the op to send to the other replicas
+func (list *CRDTList[T]) AddVal(val T, index int) (CRDTOp[T], error) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	return list.insert(index, val)
+}
+
+func (list *CRDTList[T]) insert(index int, val T) (CRDTOp[T], error) {
+	if index < 0 || index > list.size {
+		return CRDTOp[T]{}, errors.New("index " + strconv.Itoa(index) + " is out of range for " + strconv.Itoa(list.size) + " values")
+	}
+	op := CRDTOp[T]{Kind: CRDTInsert, ID: list.tick(), Value: val}
+	if index > 0 {
+		op.Ref = list.visibleNode(index - 1).val.id
+	}
+	list.integrate(op)
+	return op, nil
+}
+
+// RemoveIndex ...Removes the value at index and returns the op to send to the other replicas
+func (list *CRDTList[T]) RemoveIndex(index int) (CRDTOp[T], error) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	if index < 0 || index >= list.size {
+		return CRDTOp[T]{}, errors.New("index " + strconv.Itoa(index) + " is out of range for " + strconv.Itoa(list.size) + " values")
+	}
+	op := CRDTOp[T]{Kind: CRDTRemove, ID: list.tick(), Ref: list.visibleNode(index).val.id}
+	list.integrate(op)
+	return op, nil
+}
+
+// Merge ...Applies ops received from other replicas. Ops already merged are skipped, and ops that need
+// an element not seen yet wait, along with the later ops of their replica, until it arrives.
+func (list *CRDTList[T]) Merge(ops ...CRDTOp[T]) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	list.pending = append(list.pending, ops...)
+	for progress := true; progress; {
+		progress = false
+		blocked := make(map[string]bool)
+		waiting := list.pending[:0]
+		for _, op := range list.pending {
+			switch {
+			case op.ID.Clock <= list.version[op.ID.Replica]:
+				// merged already
+			case blocked[op.ID.Replica] || !list.ready(op):
+				blocked[op.ID.Replica] = true
+				waiting = append(waiting, op)
+			default:
+				list.integrate(op)
+				progress = true
+			}
+		}
+		clear(list.pending[len(waiting):])
+		list.pending = waiting
+	}
+}
+
+// ready ...Reports whether the element an op names has been seen
+func (list *CRDTList[T]) ready(op CRDTOp[T]) bool {
+	if op.Kind == CRDTInsert && op.Ref == (OpID{}) {
+		return true
+	}
+	_, ok := list.nodes[op.Ref]
+	return ok
+}
+
+// tick ...Returns the ID of a new local op
+func (list *CRDTList[T]) tick() OpID {
+	list.clock++
+	return OpID{Clock: list.clock, Replica: list.replica}
+}
+
+// integrate ...Applies an op whose element, if it names one, is known
+func (list *CRDTList[T]) integrate(op CRDTOp[T]) {
+	list.clock = max(list.clock, op.ID.Clock)
+	list.version[op.ID.Replica] = op.ID.Clock
+
+	if op.Kind == CRDTRemove {
+		x := list.nodes[op.Ref]
+		if !x.val.removed {
+			x.val.removed = true
+			var nilVal T
+			x.val.val = nilVal
+			list.size--
+		}
+		return
+	}
+
+	// skip the elements inserted concurrently after the same one with a higher ID, and what follows them
+	var prev *node[crdtEntry[T]]
+	next := list.seq.firstNode
+	if op.Ref != (OpID{}) {
+		prev = list.nodes[op.Ref]
+		next = prev.next
+	}
+	for next != nil && next.val.id.after(op.ID) {
+		prev, next = next, next.next
+	}
+
+	entry := crdtEntry[T]{id: op.ID, val: op.Value}
+	if prev == nil {
+		list.seq.prepend(entry)
+		list.nodes[op.ID] = list.seq.firstNode
+	} else {
+		list.nodes[op.ID] = list.seq.insertAfter(entry, prev)
+	}
+	list.size++
+}
+
+// visibleNode ...Returns the node of the element at index, counting only the elements that are not removed
+func (list *CRDTList[T]) visibleNode(index int) *node[crdtEntry[T]] {
+	for x := list.seq.firstNode; x != nil; x = x.next {
+		if !x.val.removed {
+			if index == 0 {
+				return x
+			}
+			index--
+		}
+	}
+	return nil
+}
+
+// Get ...Returns the value at index
+func (list *CRDTList[T]) Get(index int) (T, error) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	if index < 0 || index >= list.size {
+		var nilVal T
+		return nilVal, errors.New("index " + strconv.Itoa(index) + " is out of range for " + strconv.Itoa(list.size) + " values")
+	}
+	return list.visibleNode(index).val.val, nil
+}
+
+// ForEach ...Calls function with every value in order, until it returns false
+func (list *CRDTList[T]) ForEach(function func(val T) bool) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	for x := list.seq.firstNode; x != nil; x = x.next {
+		if !x.val.removed && !function(x.val.val) {
+			return
+		}
+	}
+}
+
+// ToArray ...Returns the values as a slice
+func (list *CRDTList[T]) ToArray() []T {
+	result := make([]T, 0, list.Count())
+	list.ForEach(func(val T) bool {
+		result = append(result, val)
+		return true
+	})
+	return result
+}
+
+// Count ...Returns the number of values
+func (list *CRDTList[T]) Count() int {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	return list.size
+}
+
+// Tombstones ...Returns the number of removed elements still kept, see GC
+func (list *CRDTList[T]) Tombstones() int {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	return list.seq.size - list.size
+}
+
+// Pending ...Returns the number of merged ops waiting for an element they need
+func (list *CRDTList[T]) Pending() int {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	return len(list.pending)
+}
+
+// Version ...Returns the clock of the latest op merged from each replica, this one included
+func (list *CRDTList[T]) Version() map[string]uint64 {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	version := make(map[string]uint64, len(list.version))
+	for replica, clock := range list.version {
+		version[replica] = clock
+	}
+	return version
+}
+
+// GC ...Drops the tombstones if versions, the Version of every other replica, all match the Version of this one:
+// every replica has then seen every op, so no op still to come can name a tombstone. Returns the number dropped.
+func (list *CRDTList[T]) GC(versions ...map[string]uint64) int {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	if len(list.pending) > 0 {
+		return 0
+	}
+	for _, version := range versions {
+		for replica, clock := range version {
+			if list.version[replica] != clock {
+				return 0
+			}
+		}
+		for replica, clock := range list.version {
+			if version[replica] != clock {
+				return 0
+			}
+		}
+	}
+
+	dropped := 0
+	for x := list.seq.firstNode; x != nil; {
+		next := x.next
+		if x.val.removed {
+			delete(list.nodes, x.val.id)
+			list.seq.removeNode(x)
+			dropped++
+		}
+		x = next
+	}
+	return dropped
+}
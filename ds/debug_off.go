@@ -0,0 +1,6 @@
+//go:build !linkedlist_debug
+
+package ds
+
+// debugChecks - Set by the linkedlist_debug build tag, see debug_on.go
+const debugChecks = false
@@ -0,0 +1,7 @@
+//go:build linkedlist_debug
+
+package ds
+
+// debugChecks - Set by the linkedlist_debug build tag: every public method that changes a list
+// validates it once the change is done, and panics if the change broke an invariant.
+const debugChecks = true
@@ -0,0 +1,537 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Diffing and patching.
+//
+// Diff works out a shortest edit script that turns one sequence of values
+// into another, with the linear-space variant of Myers' algorithm: it runs in
+// O((N+M)D) time and O(N+M) space for sequences of lengths N and M that are D
+// edits apart. The script is a Patch, a list of runs of values to keep, to
+// delete and to insert, which encodes to JSON as it is, e.g.
+//
+//	[{"op":"keep","n":2},{"op":"delete","values":["c"]},{"op":"insert","values":["x","y"]}]
+//
+// Apply replays a patch on a list by walking its nodes once, so every edit is
+// an O(1) node operation, and the whole patch is a single undo step. Deleted
+// values are kept in the patch, so Apply can check that the list holds what
+// the patch was made from, and so that Unified can print the patch as a
+// unified diff.
+
+// EditKind - What an Edit does
+type EditKind int
+
+const (
+	// EditKeep - Keeps the next Count values
+	EditKeep EditKind = iota
+	// EditDelete - Deletes the next values, which are Values
+	EditDelete
+	// EditInsert - Inserts Values before the next value
+	EditInsert
+)
+
+func (kind EditKind) String() string {
+	switch kind {
+	case EditKeep:
+		return "keep"
+	case EditDelete:
+		return "delete"
+	case EditInsert:
+		return "insert"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText ...Implements encoding.TextMarshaler, so kinds encode by name
+func (kind EditKind) MarshalText() ([]byte, error) {
+	if kind < EditKeep || kind > EditInsert {
+		return nil, errors.New("unknown edit kind " + strconv.Itoa(int(kind)))
+	}
+	return []byte(kind.String()), nil
+}
+
+// UnmarshalText ...Implements encoding.TextUnmarshaler
+func (kind *EditKind) UnmarshalText(text []byte) error {
+	for k := EditKeep; k <= EditInsert; k++ {
+		if k.String() == string(text) {
+			*kind = k
+			return nil
+		}
+	}
+	return errors.New("unknown edit kind " + strconv.Quote(string(text)))
+}
+
+// Edit - A run of values kept, deleted or inserted
+type Edit[T any] struct {
+	Kind EditKind `json:"op"`
+	// The number of values kept, for EditKeep
+	Count int `json:"n,omitempty"`
+	// The values deleted or inserted
+	Values []T `json:"values,omitempty"`
+}
+
+// Patch - An edit script, see Diff and Apply
+type Patch[T any] []Edit[T]
+
+// lens ...Returns the number of values the patch applies to and the number it leaves
+func (patch Patch[T]) lens() (int, int) {
+	before, after := 0, 0
+	for _, e := range patch {
+		switch e.Kind {
+		case EditKeep:
+			before += e.Count
+			after += e.Count
+		case EditDelete:
+			before += len(e.Values)
+		case EditInsert:
+			after += len(e.Values)
+		}
+	}
+	return before, after
+}
+
+// patchBuilder - Collects edits, merging runs and putting deletions before insertions
+type patchBuilder[T any] struct {
+	patch Patch[T]
+}
+
+func (p *patchBuilder[T]) keep(n int) {
+	if n == 0 {
+		return
+	}
+	if last := len(p.patch) - 1; last >= 0 && p.patch[last].Kind == EditKeep {
+		p.patch[last].Count += n
+		return
+	}
+	p.patch = append(p.patch, Edit[T]{Kind: EditKeep, Count: n})
+}
+
+func (p *patchBuilder[T]) insert(values ...T) {
+	if len(values) == 0 {
+		return
+	}
+	if last := len(p.patch) - 1; last >= 0 && p.patch[last].Kind == EditInsert {
+		p.patch[last].Values = append(p.patch[last].Values, values...)
+		return
+	}
+	p.patch = append(p.patch, Edit[T]{Kind: EditInsert, Values: append([]T(nil), values...)})
+}
+
+func (p *patchBuilder[T]) delete(values ...T) {
+	if len(values) == 0 {
+		return
+	}
+	last := len(p.patch) - 1
+	if last >= 0 && p.patch[last].Kind == EditInsert {
+		// deletions go first within a change
+		if last > 0 && p.patch[last-1].Kind == EditDelete {
+			p.patch[last-1].Values = append(p.patch[last-1].Values, values...)
+			return
+		}
+		ins := p.patch[last]
+		p.patch[last] = Edit[T]{Kind: EditDelete, Values: append([]T(nil), values...)}
+		p.patch = append(p.patch, ins)
+		return
+	}
+	if last >= 0 && p.patch[last].Kind == EditDelete {
+		p.patch[last].Values = append(p.patch[last].Values, values...)
+		return
+	}
+	p.patch = append(p.patch, Edit[T]{Kind: EditDelete, Values: append([]T(nil), values...)})
+}
+
+// differ - The state of one Diff
+type differ[T any] struct {
+	a, b []T
+	eq   func(x T, y T) bool
+	p    patchBuilder[T]
+	// the furthest reaching paths of middleSnake, forward and backward, reused across calls
+	vf, vb []int
+}
+
+// Diff ...Returns a shortest patch that turns a into b, comparing values with eq
+func Diff[T any](a []T, b []T, eq func(x T, y T) bool) Patch[T] {
+	size := len(a) + len(b) + 4
+	d := &differ[T]{a: a, b: b, eq: eq, vf: make([]int, size), vb: make([]int, size)}
+	d.compare(0, len(a), 0, len(b))
+	if d.p.patch == nil {
+		return Patch[T]{}
+	}
+	return d.p.patch
+}
+
+// compare ...Emits the edits that turn a[aLo:aHi] into b[bLo:bHi]
+func (d *differ[T]) compare(aLo int, aHi int, bLo int, bHi int) {
+	prefix := 0
+	for aLo < aHi && bLo < bHi && d.eq(d.a[aLo], d.b[bLo]) {
+		aLo++
+		bLo++
+		prefix++
+	}
+	suffix := 0
+	for aLo < aHi && bLo < bHi && d.eq(d.a[aHi-1], d.b[bHi-1]) {
+		aHi--
+		bHi--
+		suffix++
+	}
+
+	d.p.keep(prefix)
+	switch {
+	case aLo == aHi:
+		d.p.insert(d.b[bLo:bHi]...)
+	case bLo == bHi:
+		d.p.delete(d.a[aLo:aHi]...)
+	default:
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		d.p.keep(u - x)
+		d.compare(u, aHi, v, bHi)
+	}
+	d.p.keep(suffix)
+}
+
+// middleSnake ...Returns the start and end of the snake in the middle of a shortest edit path
+// from (aLo, bLo) to (aHi, bHi), searching forward from the start and backward from the end at once.
+// The ranges must not be empty, nor start or end with equal values.
+func (d *differ[T]) middleSnake(aLo int, aHi int, bLo int, bHi int) (x int, y int, u int, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	// vf[off+k] is how far along a the furthest forward path on diagonal k = x - y got;
+	// vb[off+c] is how many values from the end of a the furthest backward path on diagonal c got
+	off := maxD + 1
+	vf, vb := d.vf[:2*maxD+3], d.vb[:2*maxD+3]
+	vf[off+1], vb[off+1] = 0, 0
+
+	for D := 0; D <= maxD; D++ {
+		for k := -D; k <= D; k += 2 {
+			var x int
+			if k == -D || (k != D && vf[off+k-1] < vf[off+k+1]) {
+				x = vf[off+k+1]
+			} else {
+				x = vf[off+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && d.eq(d.a[aLo+x], d.b[bLo+y]) {
+				x++
+				y++
+			}
+			vf[off+k] = x
+			if c := delta - k; odd && c >= -(D-1) && c <= D-1 && x+vb[off+c] >= n {
+				return aLo + x0, bLo + y0, aLo + x, bLo + y
+			}
+		}
+		for c := -D; c <= D; c += 2 {
+			var x int
+			if c == -D || (c != D && vb[off+c-1] < vb[off+c+1]) {
+				x = vb[off+c+1]
+			} else {
+				x = vb[off+c-1] + 1
+			}
+			y := x - c
+			x0, y0 := x, y
+			for x < n && y < m && d.eq(d.a[aHi-1-x], d.b[bHi-1-y]) {
+				x++
+				y++
+			}
+			vb[off+c] = x
+			if k := delta - c; !odd && k >= -D && k <= D && vf[off+k]+x >= n {
+				return aHi - x, bHi - y, aHi - x0, bHi - y0
+			}
+		}
+	}
+	panic("linkedlist: Diff found no middle snake")
+}
+
+// checkPatch ...Reports whether patch applies to values: whether it spans them all and deletes what they hold.
+// A malformed patch, e.g. a decoded one that keeps a negative number of values, is an error too.
+func checkPatch[T any](patch Patch[T], values []T, eq func(x T, y T) bool) error {
+	i := 0
+	for _, e := range patch {
+		switch e.Kind {
+		case EditKeep:
+			if e.Count < 0 {
+				return errors.New("the patch keeps a negative number of values")
+			}
+			if e.Count > len(values)-i {
+				return fmt.Errorf("the patch keeps %d values from index %d, the list holds %d", e.Count, i, len(values))
+			}
+			i += e.Count
+		case EditDelete:
+			for _, v := range e.Values {
+				if i >= len(values) {
+					return fmt.Errorf("the patch deletes %v at index %d, the list holds %d values", v, i, len(values))
+				}
+				if !eq(values[i], v) {
+					return fmt.Errorf("the patch deletes %v at index %d, the list holds %v", v, i, values[i])
+				}
+				i++
+			}
+		case EditInsert:
+		default:
+			return errors.New("the patch holds an edit of unknown kind " + strconv.Itoa(int(e.Kind)))
+		}
+	}
+	if i != len(values) {
+		return fmt.Errorf("the patch applies to %d values, the list holds %d", i, len(values))
+	}
+	return nil
+}
+
+// patchTarget - The lists Apply works on
+type patchTarget[T any] interface {
+	applyPatch(patch Patch[T]) error
+}
+
+// Apply ...Replays patch on list, as a single change, after checking that the list holds the values the patch was made from.
+// If it does not, the list is left as it is and the error says where they differ.
+func Apply[T any](list patchTarget[T], patch Patch[T]) error {
+	return list.applyPatch(patch)
+}
+
+// Unified ...Renders the patch as the hunks of a unified diff, one value per line, with context kept values around each change.
+// from is what the patch applies to, where the kept values come from.
+func (patch Patch[T]) Unified(from []T, context int) string {
+	// every line of the whole diff: ' ', '-' or '+' and the value
+	type line struct {
+		op  byte
+		val T
+	}
+	var lines []line
+	i := 0
+	for _, e := range patch {
+		switch e.Kind {
+		case EditKeep:
+			for n := 0; n < e.Count && i < len(from); n++ {
+				lines = append(lines, line{' ', from[i]})
+				i++
+			}
+		case EditDelete:
+			for _, v := range e.Values {
+				lines = append(lines, line{'-', v})
+				i++
+			}
+		case EditInsert:
+			for _, v := range e.Values {
+				lines = append(lines, line{'+', v})
+			}
+		}
+	}
+
+	var b strings.Builder
+	aLine, bLine := 0, 0
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+		// a hunk runs from context lines before the change at i to context lines after the last change
+		// that is less than 2*context kept lines from the previous one
+		start := max(0, i-context)
+		end := i
+		for end < len(lines) {
+			if lines[end].op != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(lines) && lines[next].op == ' ' {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				end = min(end+context, len(lines))
+				break
+			}
+			end = next
+		}
+
+		aStart, bStart := aLine-(i-start), bLine-(i-start)
+		aLen, bLen := 0, 0
+		for _, l := range lines[start:end] {
+			if l.op != '+' {
+				aLen++
+			}
+			if l.op != '-' {
+				bLen++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		for _, l := range lines[start:end] {
+			fmt.Fprintf(&b, "%c%v\n", l.op, l.val)
+		}
+
+		aLine, bLine = aStart+aLen, bStart+bLen
+		i = end
+	}
+	return b.String()
+}
+
+// hunkRange ...Formats the range of a hunk header: the 1-based first line and the number of lines
+func hunkRange(start int, n int) string {
+	if n == 0 {
+		// an empty range names the line before it
+		return strconv.Itoa(start) + ",0"
+	}
+	if n == 1 {
+		return strconv.Itoa(start + 1)
+	}
+	return strconv.Itoa(start+1) + "," + strconv.Itoa(n)
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+func (list *AnyList[T]) applyPatch(patch Patch[T]) error {
+	defer list.unlockAndDeliver()
+	list.lock("Apply")
+
+	if err := checkPatch(patch, list.values(), list.equals); err != nil {
+		return err
+	}
+
+	// x is the next value, left is the number of values from x to the end of this list
+	x, left := list.firstNode, list.count()
+	for i := 0; i < len(patch); i++ {
+		e := patch[i]
+		switch e.Kind {
+		case EditKeep:
+			for n := 0; n < e.Count; n++ {
+				x = x.next
+			}
+			left -= e.Count
+		case EditDelete:
+			// a change inserts its values before deleting the old ones, so a sublist it empties keeps its place
+			if i+1 < len(patch) && patch[i+1].Kind == EditInsert {
+				for _, v := range patch[i+1].Values {
+					list.insertBefore(v, x)
+				}
+				i++
+			}
+			for range e.Values {
+				next := x.next
+				list.removeNode(x)
+				x = next
+			}
+			left -= len(e.Values)
+		case EditInsert:
+			if left == 0 {
+				list.addValues(e.Values...)
+				continue
+			}
+			for _, v := range e.Values {
+				list.insertBefore(v, x)
+			}
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+func (list *List[T]) applyPatch(patch Patch[T]) error {
+	defer list.unlockAndDeliver()
+	list.lock("Apply")
+
+	if err := checkPatch(patch, list.values(), func(x T, y T) bool { return x == y }); err != nil {
+		return err
+	}
+
+	// x is the next value, left is the number of values from x to the end of this list
+	x, left := list.firstNode, list.count()
+	for i := 0; i < len(patch); i++ {
+		e := patch[i]
+		switch e.Kind {
+		case EditKeep:
+			for n := 0; n < e.Count; n++ {
+				x = x.next
+			}
+			left -= e.Count
+		case EditDelete:
+			// a change inserts its values before deleting the old ones, so a sublist it empties keeps its place
+			if i+1 < len(patch) && patch[i+1].Kind == EditInsert {
+				for _, v := range patch[i+1].Values {
+					list.insertBefore(v, x)
+				}
+				i++
+			}
+			for range e.Values {
+				next := x.next
+				list.removeNode(x)
+				x = next
+			}
+			left -= len(e.Values)
+		case EditInsert:
+			if left == 0 {
+				list.addValues(e.Values...)
+				continue
+			}
+			for _, v := range e.Values {
+				list.insertBefore(v, x)
+			}
+		}
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// CList
+// ---------------------------------------------------------------------------
+
+func (list *CList) applyPatch(patch Patch[interface{}]) error {
+	defer list.check()
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	if err := checkPatch(patch, list.values(), func(x interface{}, y interface{}) bool { return x == y }); err != nil {
+		return err
+	}
+
+	// x is the next value, left is the number of values from x to the end of this list
+	x, left := list.firstNode, list.count()
+	for i := 0; i < len(patch); i++ {
+		e := patch[i]
+		switch e.Kind {
+		case EditKeep:
+			for n := 0; n < e.Count; n++ {
+				x = x.next
+			}
+			left -= e.Count
+		case EditDelete:
+			// a change inserts its values before deleting the old ones, so a sublist it empties keeps its place
+			if i+1 < len(patch) && patch[i+1].Kind == EditInsert {
+				for _, v := range patch[i+1].Values {
+					list.insertBefore(v, x)
+				}
+				i++
+			}
+			for range e.Values {
+				next := x.next
+				list.removeNode(x)
+				x = next
+			}
+			left -= len(e.Values)
+		case EditInsert:
+			if left == 0 {
+				list.addValues(e.Values...)
+				continue
+			}
+			for _, v := range e.Values {
+				list.insertBefore(v, x)
+			}
+		}
+	}
+	return nil
+}
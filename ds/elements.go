@@ -0,0 +1,230 @@
+package ds
+
+// Element handles.
+//
+// An Element points at one node of an AnyList, for code that keeps hold of
+// places in the list instead of indexes, as code written for container/list
+// does: inserting next to an element, moving it or removing it takes O(1)
+// whatever its index. Moving an element relinks its node, so its handles stay
+// valid; subscribers, the undo history and the operation log see a move as a
+// removal followed by an insertion.
+//
+// A handle is valid until its value is removed from the list, by
+// RemoveElement or any other way, Undo included. A removed element keeps its
+// value, but has no neighbours and belongs to no list: the methods that take
+// it return nil or false and leave the list alone, even if the list has
+// reused its node for another value. Front, Next and the others return a new
+// handle every time they are called; two handles on the same element compare
+// equal with Same.
+
+// Element - A handle on a value of an AnyList, see elements.go
+type Element[T any] struct {
+	list  *AnyList[T]
+	node  *node[T]
+	state *elementState[T]
+}
+
+// elementState - What the handles on a node share, so they can tell when its value is removed
+type elementState[T any] struct {
+	removed bool
+	// the value of the element once it is removed, as the node may be wiped or reused
+	val T
+}
+
+// element ...Returns a handle on x, or nil if x is nil
+func (list *AnyList[T]) element(x *node[T]) *Element[T] {
+	if x == nil {
+		return nil
+	}
+	if x.elem == nil {
+		x.elem = new(elementState[T])
+	}
+	return &Element[T]{list: list, node: x, state: x.elem}
+}
+
+// retire ...Marks the handles on x, whose value is being removed, as removed. Must be called before x is wiped.
+func retire[T any](x *node[T]) {
+	if x.elem != nil {
+		x.elem.removed = true
+		x.elem.val = x.val
+		x.elem = nil
+	}
+}
+
+// Value ...Returns the value of the element
+func (e *Element[T]) Value() T {
+	defer e.list.unlock()
+	e.list.lock("Value")
+	if e.state.removed {
+		return e.state.val
+	}
+	return e.node.val
+}
+
+// Same ...Reports whether two handles point at the same element
+func (e *Element[T]) Same(other *Element[T]) bool {
+	return e != nil && other != nil && e.state == other.state
+}
+
+// Next ...Returns the element after this one, or nil if this one is the last of the list
+func (e *Element[T]) Next() *Element[T] {
+	list := e.list
+
+	defer list.unlock()
+	list.lock("Next")
+
+	if e.state.removed || e.node == list.lastNode {
+		return nil
+	}
+	return list.element(e.node.next)
+}
+
+// Prev ...Returns the element before this one, or nil if this one is the first of the list
+func (e *Element[T]) Prev() *Element[T] {
+	list := e.list
+
+	defer list.unlock()
+	list.lock("Prev")
+
+	if e.state.removed || e.node == list.firstNode {
+		return nil
+	}
+	return list.element(e.node.prev)
+}
+
+// Front ...Returns the first element of the list, or nil if the list is empty
+func (list *AnyList[T]) Front() *Element[T] {
+	defer list.unlock()
+	list.lock("Front")
+	return list.element(list.firstNode)
+}
+
+// Back ...Returns the last element of the list, or nil if the list is empty
+func (list *AnyList[T]) Back() *Element[T] {
+	defer list.unlock()
+	list.lock("Back")
+	return list.element(list.lastNode)
+}
+
+// PushFront ...Inserts val at the front of the list and returns its element
+func (list *AnyList[T]) PushFront(val T) *Element[T] {
+	defer list.unlockAndDeliver()
+	list.lock("PushFront")
+
+	list.prepend(val)
+	return list.element(list.firstNode)
+}
+
+// PushBack ...Appends val to the list and returns its element
+func (list *AnyList[T]) PushBack(val T) *Element[T] {
+	defer list.unlockAndDeliver()
+	list.lock("PushBack")
+
+	list.append(val)
+	return list.element(list.lastNode)
+}
+
+// InsertBefore ...Inserts val right before mark and returns its element. Returns nil if mark is not an element of this list.
+func (list *AnyList[T]) InsertBefore(val T, mark *Element[T]) *Element[T] {
+	defer list.unlockAndDeliver()
+	list.lock("InsertBefore")
+
+	if !list.owns(mark) {
+		return nil
+	}
+	return list.element(list.insertBefore(val, mark.node))
+}
+
+// InsertAfter ...Inserts val right after mark and returns its element. Returns nil if mark is not an element of this list.
+func (list *AnyList[T]) InsertAfter(val T, mark *Element[T]) *Element[T] {
+	defer list.unlockAndDeliver()
+	list.lock("InsertAfter")
+
+	if !list.owns(mark) {
+		return nil
+	}
+	return list.element(list.insertAfter(val, mark.node))
+}
+
+// RemoveElement ...Removes the element from the list and returns its value. The second result is false,
+// and nothing is removed, if e is not an element of this list.
+func (list *AnyList[T]) RemoveElement(e *Element[T]) (T, bool) {
+	defer list.unlockAndDeliver()
+	list.lock("RemoveElement")
+
+	if !list.owns(e) {
+		var nilVal T
+		return nilVal, false
+	}
+	val := e.node.val
+	list.removeNode(e.node)
+	return val, true
+}
+
+// MoveToFront ...Moves the element to the front of the list. Does nothing if e is not an element of this list.
+func (list *AnyList[T]) MoveToFront(e *Element[T]) {
+	defer list.unlockAndDeliver()
+	list.lock("MoveToFront")
+
+	if !list.owns(e) || e.node == list.firstNode {
+		return
+	}
+	list.unlinkForMove(e.node)
+	first := list.firstNode
+	list.relink(e.node, first.prev, first)
+}
+
+// MoveToBack ...Moves the element to the back of the list. Does nothing if e is not an element of this list.
+func (list *AnyList[T]) MoveToBack(e *Element[T]) {
+	defer list.unlockAndDeliver()
+	list.lock("MoveToBack")
+
+	if !list.owns(e) || e.node == list.lastNode {
+		return
+	}
+	list.unlinkForMove(e.node)
+	prev, next := list.tailGap()
+	list.relink(e.node, prev, next)
+}
+
+// MoveBefore ...Moves the element right before mark. Does nothing if either is not an element of this list, or they are the same.
+func (list *AnyList[T]) MoveBefore(e *Element[T], mark *Element[T]) {
+	defer list.unlockAndDeliver()
+	list.lock("MoveBefore")
+
+	if !list.owns(e) || !list.owns(mark) || e.node == mark.node || e.node.next == mark.node {
+		return
+	}
+	list.unlinkForMove(e.node)
+	list.relink(e.node, mark.node.prev, mark.node)
+}
+
+// MoveAfter ...Moves the element right after mark. Does nothing if either is not an element of this list, or they are the same.
+func (list *AnyList[T]) MoveAfter(e *Element[T], mark *Element[T]) {
+	defer list.unlockAndDeliver()
+	list.lock("MoveAfter")
+
+	if !list.owns(e) || !list.owns(mark) || e.node == mark.node || e.node.prev == mark.node {
+		return
+	}
+	list.unlinkForMove(e.node)
+	list.relink(e.node, mark.node, mark.node.next)
+}
+
+// owns ...Reports whether e is a handle on an element of this list that has not been removed
+func (list *AnyList[T]) owns(e *Element[T]) bool {
+	return e != nil && e.list == list && !e.state.removed
+}
+
+// unlinkForMove ...Unlinks x, which is about to be linked back elsewhere, without releasing it.
+// The list must hold another node, so that it does not become empty meanwhile.
+func (list *AnyList[T]) unlinkForMove(x *node[T]) {
+	list.unlinking(x)
+	list.unlinkNodes(x, x, 1)
+}
+
+// relink ...Links x, unlinked by unlinkForMove, back between the adjacent nodes prev and next
+func (list *AnyList[T]) relink(x *node[T], prev *node[T], next *node[T]) {
+	list.linkNodes(x, x, prev, next, 1)
+	list.linked(x)
+}
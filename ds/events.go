@@ -0,0 +1,367 @@
+package ds
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Change notifications.
+//
+// Subscribe registers a function that is told about every change made to a
+// list, including changes made through its sublists. Events describe the
+// change in terms of positions in the list at the top of the sublist chain,
+// so a subscriber can mirror that list by replaying them in order.
+//
+// Events are queued while the list is locked and delivered once the lock is
+// released, so subscribers may call back into the list. They are delivered
+// one at a time and in the order the changes happened; when several
+// goroutines change the list at once, a goroutine may end up delivering the
+// events of another one.
+//
+// Working out the position of a node in the middle of the list is O(n)
+// unless the list has a position index (see WithPositionIndex), so lists
+// that are observed and edited in the middle had better have one.
+
+// EventKind - The kind of change an Event describes
+type EventKind int
+
+const (
+	// EventInserted - Values were inserted, the first of them at Index
+	EventInserted EventKind = iota
+	// EventRemoved - Values were removed, the first of them from Index
+	EventRemoved
+	// EventSet - The value at Index went from Old to New
+	EventSet
+	// EventCleared - The list was emptied; Values holds what it contained
+	EventCleared
+)
+
+func (kind EventKind) String() string {
+	switch kind {
+	case EventInserted:
+		return "Inserted"
+	case EventRemoved:
+		return "Removed"
+	case EventSet:
+		return "Set"
+	case EventCleared:
+		return "Cleared"
+	default:
+		return "Unknown"
+	}
+}
+
+// MarshalText ...Implements encoding.TextMarshaler, so kinds encode by name, e.g. in JSON
+func (kind EventKind) MarshalText() ([]byte, error) {
+	if kind < EventInserted || kind > EventCleared {
+		return nil, errors.New("unknown event kind " + strconv.Itoa(int(kind)))
+	}
+	return []byte(kind.String()), nil
+}
+
+// UnmarshalText ...Implements encoding.TextUnmarshaler
+func (kind *EventKind) UnmarshalText(text []byte) error {
+	for k := EventInserted; k <= EventCleared; k++ {
+		if k.String() == string(text) {
+			*kind = k
+			return nil
+		}
+	}
+	return errors.New("unknown event kind " + strconv.Quote(string(text)))
+}
+
+// Event - A change made to a list
+type Event[T any] struct {
+	Kind EventKind `json:"kind"`
+	// Position in the list at the top of the sublist chain
+	Index int `json:"index"`
+	// The values inserted or removed, in list order
+	Values []T `json:"values,omitempty"`
+	// The value replaced by an EventSet, and the value that replaced it
+	Old T `json:"old"`
+	New T `json:"new"`
+}
+
+// eventHub - Queues the events of a list and hands them to its subscribers
+type eventHub[T any] struct {
+	mu          sync.Mutex
+	subscribers []subscriber[T]
+	nextID      int
+	// number of subscribers, readable without taking mu
+	active     atomic.Int32
+	pending    []Event[T]
+	delivering bool
+}
+
+type subscriber[T any] struct {
+	id       int
+	function func(ev Event[T])
+}
+
+func (hub *eventHub[T]) subscribe(function func(ev Event[T])) func() {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	id := hub.nextID
+	hub.nextID++
+	hub.subscribers = append(hub.subscribers, subscriber[T]{id: id, function: function})
+	hub.active.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			hub.unsubscribe(id)
+		})
+	}
+}
+
+func (hub *eventHub[T]) unsubscribe(id int) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	for i, s := range hub.subscribers {
+		if s.id == id {
+			// copy, so that a delivery in progress keeps its own snapshot intact
+			subscribers := make([]subscriber[T], 0, len(hub.subscribers)-1)
+			subscribers = append(subscribers, hub.subscribers[:i]...)
+			hub.subscribers = append(subscribers, hub.subscribers[i+1:]...)
+			hub.active.Add(-1)
+			return
+		}
+	}
+}
+
+// push ...Queues an event, merging it into the previous one when it simply extends it
+func (hub *eventHub[T]) push(ev Event[T]) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if n := len(hub.pending); n > 0 {
+		last := &hub.pending[n-1]
+		if last.Kind == ev.Kind {
+			switch ev.Kind {
+			case EventInserted:
+				if ev.Index == last.Index+len(last.Values) {
+					last.Values = append(last.Values, ev.Values...)
+					return
+				}
+			case EventRemoved:
+				if ev.Index == last.Index {
+					last.Values = append(last.Values, ev.Values...)
+					return
+				}
+			}
+		}
+	}
+	hub.pending = append(hub.pending, ev)
+}
+
+// deliver ...Hands the queued events to the subscribers. Must be called without holding the list's lock.
+func (hub *eventHub[T]) deliver() {
+	hub.mu.Lock()
+	if hub.delivering {
+		// whoever is delivering will pick our events up
+		hub.mu.Unlock()
+		return
+	}
+	hub.delivering = true
+	defer func() {
+		hub.mu.Lock()
+		hub.delivering = false
+		hub.mu.Unlock()
+	}()
+
+	for len(hub.pending) > 0 {
+		events := hub.pending
+		hub.pending = nil
+		subscribers := hub.subscribers
+		hub.mu.Unlock()
+
+		for _, ev := range events {
+			for _, s := range subscribers {
+				s.function(ev)
+			}
+		}
+
+		hub.mu.Lock()
+	}
+	hub.mu.Unlock()
+}
+
+// chanSubscription - Forwards events to a channel until it is cancelled
+type chanSubscription[T any] struct {
+	ch     chan Event[T]
+	done   chan struct{}
+	mu     sync.Mutex
+	closed bool
+}
+
+func newChanSubscription[T any](buffer int) *chanSubscription[T] {
+	return &chanSubscription[T]{
+		ch:   make(chan Event[T], buffer),
+		done: make(chan struct{}),
+	}
+}
+
+func (sub *chanSubscription[T]) send(ev Event[T]) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.closed {
+		return
+	}
+	select {
+	case sub.ch <- ev:
+	case <-sub.done:
+	}
+}
+
+func (sub *chanSubscription[T]) close() {
+	// unblock a pending send first, then wait for it to let go
+	close(sub.done)
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	sub.closed = true
+	close(sub.ch)
+}
+
+// subscribeChan ...Wires a channel subscription into hub and returns the channel and its cancel function
+func subscribeChan[T any](hub *eventHub[T], buffer int) (<-chan Event[T], func()) {
+	sub := newChanSubscription[T](buffer)
+	cancel := hub.subscribe(sub.send)
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			cancel()
+			sub.close()
+		})
+	}
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// Subscribe ...Calls function with every change made to the list from now on, including
+// changes made through its sublists. Subscribing through a sublist subscribes to the list at
+// the top of the sublist chain. Returns a function that cancels the subscription.
+func (list *AnyList[T]) Subscribe(function func(ev Event[T])) (cancel func()) {
+	return list.eventHub().subscribe(function)
+}
+
+// SubscribeChan ...Works like Subscribe but sends the events on a channel with the given buffer size.
+// The channel must be drained: a full channel holds up whichever goroutine delivers events.
+// Cancelling the subscription closes the channel.
+func (list *AnyList[T]) SubscribeChan(buffer int) (<-chan Event[T], func()) {
+	return subscribeChan(list.eventHub(), buffer)
+}
+
+// eventHub ...Returns the hub of the root list, creating it if needed
+func (list *AnyList[T]) eventHub() *eventHub[T] {
+	root := list.root()
+
+	defer root.unlock()
+	root.lock("Subscribe")
+
+	if root.hub == nil {
+		root.hub = new(eventHub[T])
+	}
+	return root.hub
+}
+
+// observed ...Reports whether anybody listens to the list. Only meaningful on the root list.
+func (list *AnyList[T]) observed() bool {
+	return list.hub != nil && list.hub.active.Load() > 0
+}
+
+// unlockAndDeliver ...Releases the lock taken by lock, then hands queued events to the subscribers.
+// Public methods that change the list defer it before taking the lock. The hub and the history are
+// picked up while the lock is still held, as Subscribe and WithHistory set them under it.
+// It also closes the undo step of the change that was just made, and validates the list in debug builds.
+func (list *AnyList[T]) unlockAndDeliver() {
+	root := list.root()
+	hub, h := root.hub, root.history
+	list.unlock()
+
+	if debugChecks {
+		// not through Validate, so the checks do not show up in the instrumentation
+		list.mu.Lock()
+		err := list.validate()
+		list.mu.Unlock()
+		if err != nil {
+			panic("linkedlist: invariant broken: " + err.Error())
+		}
+	}
+	if h != nil {
+		h.seal()
+	}
+	if hub != nil {
+		hub.deliver()
+	}
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// Subscribe ...Calls function with every change made to the list from now on, including
+// changes made through its sublists. Subscribing through a sublist subscribes to the list at
+// the top of the sublist chain. Returns a function that cancels the subscription.
+func (list *List[T]) Subscribe(function func(ev Event[T])) (cancel func()) {
+	return list.eventHub().subscribe(function)
+}
+
+// SubscribeChan ...Works like Subscribe but sends the events on a channel with the given buffer size.
+// The channel must be drained: a full channel holds up whichever goroutine delivers events.
+// Cancelling the subscription closes the channel.
+func (list *List[T]) SubscribeChan(buffer int) (<-chan Event[T], func()) {
+	return subscribeChan(list.eventHub(), buffer)
+}
+
+// eventHub ...Returns the hub of the root list, creating it if needed
+func (list *List[T]) eventHub() *eventHub[T] {
+	root := list.root()
+
+	defer root.unlock()
+	root.lock("Subscribe")
+
+	if root.hub == nil {
+		root.hub = new(eventHub[T])
+	}
+	return root.hub
+}
+
+// observed ...Reports whether anybody listens to the list. Only meaningful on the root list.
+func (list *List[T]) observed() bool {
+	return list.hub != nil && list.hub.active.Load() > 0
+}
+
+// unlockAndDeliver ...Releases the lock taken by lock, then hands queued events to the subscribers.
+// Public methods that change the list defer it before taking the lock. The hub and the history are
+// picked up while the lock is still held, as Subscribe and WithHistory set them under it.
+// It also closes the undo step of the change that was just made, and validates the list in debug builds.
+func (list *List[T]) unlockAndDeliver() {
+	root := list.root()
+	hub, h := root.hub, root.history
+	list.unlock()
+
+	if debugChecks {
+		// not through Validate, so the checks do not show up in the instrumentation
+		list.mu.Lock()
+		err := list.validate()
+		list.mu.Unlock()
+		if err != nil {
+			panic("linkedlist: invariant broken: " + err.Error())
+		}
+	}
+	if h != nil {
+		h.seal()
+	}
+	if hub != nil {
+		hub.deliver()
+	}
+}
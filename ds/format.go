@@ -0,0 +1,316 @@
+package ds
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Printing lists.
+//
+// Every list type implements fmt.Formatter, so lists print like slices do:
+//
+//	%v   [1 2 3]
+//	%+v  [1 2 3] (len 3, sublist of 10, 1 live sublist)
+//	%#v  []int{1, 2, 3}
+//
+// %#v prints the values as a Go slice literal, which compiles where the list
+// itself, whose fields are unexported, could not be written as a literal.
+// Any other verb, with its flags, width and precision, is applied to each
+// value in turn, e.g. %q or %6.2f.
+// Dump writes the same text to an io.Writer and can cut huge lists short,
+// and LogValue makes lists print nicely with log/slog.
+
+// maxLogValues - The number of values LogValue shows before it cuts a list short
+const maxLogValues = 32
+
+// DumpOptions - Controls what Dump writes
+type DumpOptions struct {
+	// Written on a line of its own before the values, followed by a colon, as Log does
+	Label string
+	// The number of values written at most; the rest are summed up as "... (N more)". 0 writes them all.
+	MaxValues int
+	// Adds the length and sublist information that %+v prints
+	Details bool
+}
+
+// listSnapshot - What printing a list needs to know about it, taken with the list locked
+type listSnapshot[T any] struct {
+	// the first values of the list, all of them unless the snapshot was cut short
+	values []T
+	size   int
+	// size of the parent of a sublist, -1 for a list that is not a sublist
+	parentSize int
+	// number of live sublists taken from the list
+	views int
+}
+
+// details ...Returns the part %+v adds after the values
+func (s *listSnapshot[T]) details() string {
+	var b strings.Builder
+	b.WriteString(" (len ")
+	b.WriteString(strconv.Itoa(s.size))
+	if s.parentSize >= 0 {
+		b.WriteString(", sublist of ")
+		b.WriteString(strconv.Itoa(s.parentSize))
+	}
+	if s.views == 1 {
+		b.WriteString(", 1 live sublist")
+	} else if s.views > 1 {
+		b.WriteString(", " + strconv.Itoa(s.views) + " live sublists")
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// writeValues ...Writes the values between brackets, each formatted with directive
+func (s *listSnapshot[T]) writeValues(w io.Writer, directive string) {
+	io.WriteString(w, "[")
+	for i, v := range s.values {
+		if i > 0 {
+			io.WriteString(w, " ")
+		}
+		fmt.Fprintf(w, directive, v)
+	}
+	if more := s.size - len(s.values); more > 0 {
+		fmt.Fprintf(w, " ... (%d more)", more)
+	}
+	io.WriteString(w, "]")
+}
+
+func (s *listSnapshot[T]) format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		values := s.values
+		if values == nil {
+			values = []T{}
+		}
+		fmt.Fprintf(f, "%#v", values)
+	case verb == 'v' && f.Flag('+'):
+		s.writeValues(f, fmt.FormatString(f, verb))
+		io.WriteString(f, s.details())
+	default:
+		s.writeValues(f, fmt.FormatString(f, verb))
+	}
+}
+
+func (s *listSnapshot[T]) dump(w io.Writer, opts DumpOptions) error {
+	var b strings.Builder
+	if opts.Label != "" {
+		b.WriteString(opts.Label)
+		b.WriteString(":\n")
+	}
+	s.writeValues(&b, "%v")
+	if opts.Details {
+		b.WriteString(s.details())
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (s *listSnapshot[T]) logValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Int("len", s.size),
+		slog.Any("values", s.values),
+	}
+	if s.parentSize >= 0 {
+		attrs = append(attrs, slog.Int("sublistOf", s.parentSize))
+	}
+	if s.size > len(s.values) {
+		attrs = append(attrs, slog.Bool("truncated", true))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// String ...Returns the values of the list as %v prints them, e.g. [1 2 3]
+func (list *AnyList[T]) String() string {
+	return fmt.Sprintf("%v", list)
+}
+
+// Format ...Implements fmt.Formatter, see format.go
+func (list *AnyList[T]) Format(f fmt.State, verb rune) {
+	list.snapshot(0).format(f, verb)
+}
+
+// Dump ...Writes the values of the list to w, as configured by opts
+func (list *AnyList[T]) Dump(w io.Writer, opts DumpOptions) error {
+	return list.snapshot(opts.MaxValues).dump(w, opts)
+}
+
+// LogValue ...Implements slog.LogValuer: the list logs as a group holding its length and its first values
+func (list *AnyList[T]) LogValue() slog.Value {
+	return list.snapshot(maxLogValues).logValue()
+}
+
+// snapshot ...Copies what printing needs from the list, keeping at most max values (all of them if max <= 0)
+func (list *AnyList[T]) snapshot(max int) *listSnapshot[T] {
+	defer list.unlock()
+	list.lock("Print")
+
+	s := &listSnapshot[T]{size: list.count(), parentSize: -1}
+	for x := list.firstNode; x != nil; x = x.next {
+		if max > 0 && len(s.values) == max {
+			break
+		}
+		s.values = append(s.values, x.val)
+		if x == list.lastNode {
+			break
+		}
+	}
+	if list.parent != nil {
+		s.parentSize = list.parent.size
+	}
+	var live []*AnyList[T]
+	list.views, live = liveViews(list.views)
+	s.views = len(live)
+	return s
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// String ...Returns the values of the list as %v prints them, e.g. [1 2 3]
+func (list *List[T]) String() string {
+	return fmt.Sprintf("%v", list)
+}
+
+// Format ...Implements fmt.Formatter, see format.go
+func (list *List[T]) Format(f fmt.State, verb rune) {
+	list.snapshot(0).format(f, verb)
+}
+
+// Dump ...Writes the values of the list to w, as configured by opts
+func (list *List[T]) Dump(w io.Writer, opts DumpOptions) error {
+	return list.snapshot(opts.MaxValues).dump(w, opts)
+}
+
+// LogValue ...Implements slog.LogValuer: the list logs as a group holding its length and its first values
+func (list *List[T]) LogValue() slog.Value {
+	return list.snapshot(maxLogValues).logValue()
+}
+
+// snapshot ...Copies what printing needs from the list, keeping at most max values (all of them if max <= 0)
+func (list *List[T]) snapshot(max int) *listSnapshot[T] {
+	defer list.unlock()
+	list.lock("Print")
+
+	s := &listSnapshot[T]{size: list.count(), parentSize: -1}
+	for x := list.firstNode; x != nil; x = x.next {
+		if max > 0 && len(s.values) == max {
+			break
+		}
+		s.values = append(s.values, x.val)
+		if x == list.lastNode {
+			break
+		}
+	}
+	if list.parent != nil {
+		s.parentSize = list.parent.size
+	}
+	var live []*List[T]
+	list.views, live = liveViews(list.views)
+	s.views = len(live)
+	return s
+}
+
+// ---------------------------------------------------------------------------
+// CList
+// ---------------------------------------------------------------------------
+
+// String ...Returns the values of the list as %v prints them, e.g. [1 2 3]
+func (list *CList) String() string {
+	return fmt.Sprintf("%v", list)
+}
+
+// Format ...Implements fmt.Formatter, see format.go
+func (list *CList) Format(f fmt.State, verb rune) {
+	list.snapshot(0).format(f, verb)
+}
+
+// Dump ...Writes the values of the list to w, as configured by opts
+func (list *CList) Dump(w io.Writer, opts DumpOptions) error {
+	return list.snapshot(opts.MaxValues).dump(w, opts)
+}
+
+// LogValue ...Implements slog.LogValuer: the list logs as a group holding its length and its first values
+func (list *CList) LogValue() slog.Value {
+	return list.snapshot(maxLogValues).logValue()
+}
+
+// snapshot ...Copies what printing needs from the list, keeping at most max values (all of them if max <= 0)
+func (list *CList) snapshot(max int) *listSnapshot[interface{}] {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	s := &listSnapshot[interface{}]{size: list.count(), parentSize: -1}
+	for x := list.firstNode; x != nil; x = x.next {
+		if max > 0 && len(s.values) == max {
+			break
+		}
+		s.values = append(s.values, x.val)
+		if x == list.lastNode {
+			break
+		}
+	}
+	if list.parent != nil {
+		s.parentSize = list.parent.size
+	}
+	var live []*CList
+	list.views, live = liveViews(list.views)
+	s.views = len(live)
+	return s
+}
+
+// ---------------------------------------------------------------------------
+// UnrolledList[T any]
+// ---------------------------------------------------------------------------
+
+// String ...Returns the values of the list as %v prints them, e.g. [1 2 3]
+func (list *UnrolledList[T]) String() string {
+	return fmt.Sprintf("%v", list)
+}
+
+// Format ...Implements fmt.Formatter, see format.go
+func (list *UnrolledList[T]) Format(f fmt.State, verb rune) {
+	list.snapshot(0).format(f, verb)
+}
+
+// Dump ...Writes the values of the list to w, as configured by opts
+func (list *UnrolledList[T]) Dump(w io.Writer, opts DumpOptions) error {
+	return list.snapshot(opts.MaxValues).dump(w, opts)
+}
+
+// LogValue ...Implements slog.LogValuer: the list logs as a group holding its length and its first values
+func (list *UnrolledList[T]) LogValue() slog.Value {
+	return list.snapshot(maxLogValues).logValue()
+}
+
+// snapshot ...Copies what printing needs from the list, keeping at most max values (all of them if max <= 0).
+// Views over an UnrolledList are not tracked, so the snapshot counts none.
+func (list *UnrolledList[T]) snapshot(max int) *listSnapshot[T] {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	s := &listSnapshot[T]{size: list.size, parentSize: -1}
+	list.walk(func(val T) bool {
+		if max > 0 && len(s.values) == max {
+			return false
+		}
+		s.values = append(s.values, val)
+		return true
+	})
+	if list.parent != nil {
+		s.parentSize = list.parent.size
+	}
+	return s
+}
@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"weak"
 )
 
 // abstractAnyList - An abstraction of a list
@@ -40,6 +42,8 @@ type node[T any] struct {
 	next *node[T]
 	prev *node[T]
 	val  T
+	// Shared with the Element handles on the node, nil until one is made, see elements.go
+	elem *elementState[T]
 }
 
 // AnyList - The AnyList
@@ -54,6 +58,27 @@ type AnyList[T any] struct {
 	iter *node[T]
 	//Used for rapid iteration over the list's nodes
 	nodeIter *node[T]
+	// Sublists taken from this list, held weakly so that they can still be collected, see WriteDOT
+	views []weak.Pointer[AnyList[T]]
+	// Optional hash index over the values, see WithIndex. Only the root list of a sublist chain holds one.
+	index *hashIndex[uint64, *node[T]]
+	// Optional order-statistics tree that tracks the position of every node
+	order *rankTree[*node[T]]
+	// Optional node allocator, see WithAllocator. Only the root list of a sublist chain holds one.
+	alloc nodeAllocator[node[T]]
+	// Delivers change notifications, see Subscribe. Only the root list of a sublist chain holds one.
+	hub *eventHub[T]
+	// Records changes for Undo and Redo, see WithHistory. Only the root list of a sublist chain holds one.
+	history *history[T]
+	// Collects the changes of the transaction in progress, see Update. Only the root list of a sublist chain holds one.
+	journal *txJournal[T]
+	// Numbers the changes for replicas, see WithOpLog. Only the root list of a sublist chain holds one.
+	oplog *OpLog[T]
+	// Optional instrumentation, see WithInstrumentation, and when the lock was taken, for LockHeld
+	instr    Instrumentation
+	lockedAt time.Time
+	// Hashes values for the index, see WithIndex
+	hash func(val T) uint64
 	// Every instance had better override this function after calling the NewAnyList function in order to gain speed in the Remove, IndexOf and other relevant function
 	Equals func(val1 T, val2 T) bool
 }
@@ -69,13 +94,25 @@ func NewAnyList[T any]() *AnyList[T] {
 	list.nodeIter = nil
 	list.mu = sync.Mutex{}
 
-	list.Equals = func(val1 T, val2 T) bool {
-		return fmt.Sprintf("%v", val1) == fmt.Sprintf("%v", val2)
-	}
+	list.Equals = defaultEquals[T]
 
 	return list
 }
 
+// defaultEquals ...The Equals function of new lists: two values are equal if they print the same
+func defaultEquals[T any](val1 T, val2 T) bool {
+	return fmt.Sprintf("%v", val1) == fmt.Sprintf("%v", val2)
+}
+
+// equals ...Compares two values with the Equals function of the list, or with defaultEquals if it has none,
+// as is the case for a zero AnyList such as the one encoding/json allocates for a nil *AnyList field
+func (list *AnyList[T]) equals(val1 T, val2 T) bool {
+	if list.Equals == nil {
+		return defaultEquals(val1, val2)
+	}
+	return list.Equals(val1, val2)
+}
+
 func init_node[T any](prev *node[T], val T, next *node[T]) *node[T] {
 	node := new(node[T])
 	node.prev = prev
@@ -132,14 +169,16 @@ func (list *AnyList[T]) resetIterator() {
 	if list.iter != nil {
 		list.iter = nil
 	}
+	// next() walks the list through the nodes iterator
+	list.resetNodeIterator()
 }
 
 func (list *AnyList[T]) ForEach(function func(val T) bool) {
 
 	x := new(T)
 
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("ForEach")
 	list.resetIterator()
 
 	for {
@@ -206,23 +245,16 @@ func (list *AnyList[T]) ToArray() []T {
 
 // TESTED
 func (list *AnyList[T]) addNode(elem *node[T]) {
-	oldLastNode := list.lastNode
-
-	list.lastNode = elem
-	if oldLastNode == nil {
-		list.firstNode = elem
-	} else {
-		oldLastNode.next = elem
-		elem.prev = oldLastNode
-	}
-	list.incrementSize(1)
+	prev, next := list.tailGap()
+	list.linkNodes(elem, elem, prev, next, 1)
+	list.linked(elem)
 }
 
 // TESTED
 func (list *AnyList[T]) Add(val T) {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("Add")
 	list.add(val)
 
 }
@@ -233,9 +265,9 @@ func (list *AnyList[T]) add(val T) {
 }
 
 func (list *AnyList[T]) AddVal(val T, index int) bool {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("AddVal")
 	_, _ = list.addVal(val, index)
 
 	return true
@@ -243,15 +275,15 @@ func (list *AnyList[T]) AddVal(val T, index int) bool {
 
 // TESTED
 func (list *AnyList[T]) addVal(val T, index int) (bool, error) {
-	node := init_node(nil, val, nil)
+	node := list.newNode(nil, val, nil)
 	return list.addNodeAt(node, index)
 
 }
 
 func (list *AnyList[T]) AddValues(args ...T) {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("AddValues")
 	list.addValues(args...)
 
 }
@@ -264,9 +296,9 @@ func (list *AnyList[T]) addValues(args ...T) {
 }
 
 func (list *AnyList[T]) AddArray(array []T) {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("AddArray")
 	list.addArray(array)
 }
 
@@ -276,9 +308,9 @@ func (list *AnyList[T]) addArray(array []T) {
 }
 
 func (list *AnyList[T]) AddAll(lst *AnyList[T]) bool {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("AddAll")
 	_ = list.addAll(lst)
 
 	return true
@@ -291,9 +323,9 @@ func (list *AnyList[T]) addAll(lst *AnyList[T]) error {
 }
 
 func (list *AnyList[T]) AddAllAt(index int, lst *AnyList[T]) bool {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("AddAllAt")
 	_ = list.addAllAt(index, lst)
 
 	return true
@@ -325,37 +357,19 @@ func (list *AnyList[T]) addAllAt(index int, lst *AnyList[T]) error {
 		return errors.New("empty parameter sublist found")
 	}
 
+	var prev, next *node[T]
 	if index == sz {
-
-		lastNode := list.lastNode
-
-		lastNode.next = dup.firstNode
-		dup.firstNode.prev = lastNode
-		list.lastNode = dup.lastNode
-
-	} else if index == 0 {
-		oldFirstNode := list.firstNode
-
-		dup.lastNode.next = oldFirstNode
-		oldFirstNode.prev = dup.lastNode
-		list.firstNode = dup.firstNode
+		prev, next = list.tailGap()
 	} else {
 		nodeAtIndex, err := list.getNode(index)
 		if err != nil {
 			return err
 		}
-
-		prevNode := nodeAtIndex.prev
-
-		prevNode.next = dup.firstNode
-		dup.firstNode.prev = prevNode
-
-		dup.lastNode.next = nodeAtIndex
-		nodeAtIndex.prev = dup.lastNode
-
+		prev, next = nodeAtIndex.prev, nodeAtIndex
 	}
 
-	list.incrementSize(numNew)
+	list.linkNodes(dup.firstNode, dup.lastNode, prev, next, numNew)
+	list.linkedRange(dup.firstNode, dup.lastNode)
 	return nil
 }
 
@@ -380,18 +394,8 @@ func (list *AnyList[T]) addNodeAt(elem *node[T], index int) (bool, error) {
 			}
 
 			// assert succ != nil;
-			prev := succ.prev
-
-			elem.prev = succ.prev
-			elem.next = succ
-			succ.prev = elem
-
-			if prev == nil {
-				list.firstNode = elem
-			} else {
-				prev.next = elem
-			}
-			list.incrementSize(1)
+			list.linkNodes(elem, elem, succ.prev, succ, 1)
+			list.linked(elem)
 		}
 
 		return true, nil
@@ -402,62 +406,110 @@ func (list *AnyList[T]) addNodeAt(elem *node[T], index int) (bool, error) {
 
 }
 
+// incrementSize ...Grows the list and every list above it in the sublist chain by dx.
+// Each sublist's record of its parent's length moves along, so that sync does not recount a sublist that is up to date.
 func (list *AnyList[T]) incrementSize(dx int) {
-	list.size += dx
-	if list.parent != nil {
-		list.parent.size += dx
+	for l := list; l != nil; l = l.parent {
+		l.size += dx
+		if l.parent != nil {
+			l.parenLen += dx
+		}
 	}
 }
 
 func (list *AnyList[T]) decrementSize(dx int) {
-	list.size -= dx
-	if list.parent != nil {
-		list.parent.size -= dx
+	list.incrementSize(-dx)
+}
+
+// tailGap ...Returns the nodes between which a node appended to this list goes
+func (list *AnyList[T]) tailGap() (*node[T], *node[T]) {
+	if list.lastNode == nil {
+		return nil, nil
 	}
+	return list.lastNode, list.lastNode.next
 }
 
-func (list *AnyList[T]) removeNode(elem *node[T]) bool {
+// linkNodes ...Links the chain of n nodes from first to last between the adjacent nodes prev and next
+// (either may be nil), then moves the ends of this list and of the lists above it to cover the new nodes.
+// A sublist that has been emptied has no place left in its parent, so it becomes a list of its own here.
+func (list *AnyList[T]) linkNodes(first *node[T], last *node[T], prev *node[T], next *node[T], n int) {
+	if list.firstNode == nil && list.parent != nil {
+		list.parent = nil
+		list.parenLen = 0
+	}
 
-	var nilVal T
-	next := elem.next
-	prev := elem.prev
+	first.prev = prev
+	last.next = next
+	if prev != nil {
+		prev.next = first
+	}
+	if next != nil {
+		next.prev = last
+	}
 
-	if prev == nil {
-		list.firstNode = next
-	} else {
-		prev.next = next
-		elem.prev = nil
+	for l := list; l != nil; l = l.parent {
+		if l.firstNode == next {
+			l.firstNode = first
+		}
+		if l.lastNode == prev {
+			l.lastNode = last
+		}
 	}
+	list.incrementSize(n)
+}
 
-	if next == nil {
-		list.lastNode = prev
-	} else {
+// unlinkNodes ...Cuts the chain of n nodes from first to last out of this list, then moves the ends of
+// this list and of the lists above it off the removed nodes
+func (list *AnyList[T]) unlinkNodes(first *node[T], last *node[T], n int) {
+	prev := first.prev
+	next := last.next
+	if prev != nil {
+		prev.next = next
+	}
+	if next != nil {
 		next.prev = prev
-		elem.next = nil
 	}
+	first.prev = nil
+	last.next = nil
+
+	for l := list; l != nil; l = l.parent {
+		if l.firstNode == first && l.lastNode == last {
+			l.firstNode = nil
+			l.lastNode = nil
+		} else if l.firstNode == first {
+			l.firstNode = next
+		} else if l.lastNode == last {
+			l.lastNode = prev
+		}
+	}
+	list.decrementSize(n)
+}
+
+func (list *AnyList[T]) removeNode(elem *node[T]) bool {
+
+	list.unlinking(elem)
+	list.unlinkNodes(elem, elem, 1)
 
+	retire(elem)
+	var nilVal T
 	elem.val = nilVal
-	list.decrementSize(1)
+	list.releaseNode(elem)
 	return true
 
 }
 func (list *AnyList[T]) removeIndex(index int) bool {
 
-	x := list.firstNode
-	for i := 0; i <= index; i++ {
-		if index == i {
-			succ := list.removeNode(x)
-			return succ
-		}
-		x = x.next
+	x, err := list.getNode(index)
+	if err != nil {
+		return false
 	}
-	return false
+	return list.removeNode(x)
 }
 
 func (list *AnyList[T]) Remove(val T) bool {
 
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlockAndDeliver()
+	list.lock("Remove")
 	list.remove(val)
 
 	return true
@@ -474,10 +526,18 @@ func (list *AnyList[T]) remove(val T) bool {
 		return false
 	}
 
+	if list.indexed() {
+		x, _ := list.indexedFirst(val)
+		if x == nil {
+			return false
+		}
+		return list.removeNode(x)
+	}
+
 	x := list.firstNode
 	sz := list.count()
 	for i := 0; i < sz; i++ {
-		if list.Equals(x.val, val) { // if x.val == val{
+		if list.equals(x.val, val) { // if x.val == val{
 			succ := list.removeNode(x)
 
 			return succ
@@ -491,14 +551,14 @@ func (list *AnyList[T]) remove(val T) bool {
 }
 
 func (list *AnyList[T]) RemoveIndex(index int) bool {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlockAndDeliver()
+	list.lock("RemoveIndex")
 	return list.removeIndex(index)
 }
 
 func (list *AnyList[T]) RemoveAll(lst *AnyList[T]) bool {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlockAndDeliver()
+	list.lock("RemoveAll")
 	list.removeAll(lst)
 
 	return true
@@ -530,8 +590,8 @@ func (list *AnyList[T]) IsEmpty() bool {
 // SubList ...Creates a view of the list... starting at startIndex and ending at endIndex-1.
 // In essence, the element at `endIndex` is not included
 func (list *AnyList[T]) SubList(startIndex int, endIndex int) (*AnyList[T], error) {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("SubList")
 
 	if startIndex < 0 {
 		return nil, errors.New("startIndex(" + strconv.Itoa(startIndex) + ") < 0 is not allowed")
@@ -548,12 +608,18 @@ func (list *AnyList[T]) SubList(startIndex int, endIndex int) (*AnyList[T], erro
 
 	subList := NewAnyList[T]()
 	subList.Equals = list.Equals
-	start, end := list.getBoundaryNodes(startIndex, endIndex)
+	// an empty view has no nodes to hold on to, see linkNodes
+	var start, end *node[T]
+	if startIndex < endIndex {
+		start, end = list.getBoundaryNodes(startIndex, endIndex)
+	}
 	subList.firstNode = start
 	subList.lastNode = end
 	subList.parent = list
 	subList.parenLen = sz
+	subList.instr = list.instr
 	subList.size = endIndex - startIndex
+	list.addView(subList)
 
 	return subList, nil
 
@@ -577,18 +643,24 @@ func (list *AnyList[T]) getNode(index int) (*node[T], error) {
 		return nil, errors.New("Index=(" + strconv.Itoa(index) + ") > list-size=(" + strconv.Itoa(list.count()) + ") is not allowed")
 	}
 
+	if list.root().order != nil {
+		return list.orderedNode(index), nil
+	}
+
 	// NOTE x >> y is same as x ÷ 2^y
 	if index < (sz >> 1) {
 		x := list.firstNode
 		for i := 0; i < index; i++ {
 			x = x.next
 		}
+		list.traversed(index)
 		return x, nil
 	} else {
 		x := list.lastNode
 		for i := sz - 1; i > index; i-- {
 			x = x.prev
 		}
+		list.traversed(sz - 1 - index)
 		return x, nil
 	}
 
@@ -609,18 +681,30 @@ func (list *AnyList[T]) getBoundaryNodes(start int, end int) (*node[T], *node[T]
 
 // Get - returns the element at that index in the list
 func (list *AnyList[T]) Set(index int, val T) {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlockAndDeliver()
+	list.lock("Set")
+	_ = list.set(index, val)
+}
+
+func (list *AnyList[T]) set(index int, val T) error {
 	node, err := list.getNode(index)
-	if err == nil {
-		node.val = val
+	if err != nil {
+		return err
 	}
+	old := node.val
+	node.val = val
+	list.changed(node, old)
+	return nil
 }
 
 // Get - returns the element at that index in the list
 func (list *AnyList[T]) Get(index int) (T, error) {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("Get")
+	return list.get(index)
+}
+
+func (list *AnyList[T]) get(index int) (T, error) {
 	node, err := list.getNode(index)
 	if err == nil {
 		return node.val, nil
@@ -634,28 +718,53 @@ func (list *AnyList[T]) getLastNode() *node[T] {
 }
 
 func (list *AnyList[T]) LastElement() interface{} {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("LastElement")
 	return list.getLastNode().val
 }
 
 func (list *AnyList[T]) Contains(val T) bool {
-	return list.IndexOf(val) != -1
+	defer list.unlock()
+	list.lock("Contains")
+
+	return list.contains(val)
+}
+
+func (list *AnyList[T]) contains(val T) bool {
+	if list.indexed() {
+		return list.indexedContains(val)
+	}
+	return list.indexOf(val) != -1
 
 }
 
 func (list *AnyList[T]) IndexOf(val T) int {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("IndexOf")
+
+	return list.indexOf(val)
+}
+
+func (list *AnyList[T]) indexOf(val T) int {
+
+	if list.indexed() {
+		_, i := list.indexedFirst(val)
+		return i
+	}
 
 	x := list.firstNode
 
-	if list.Equals(x.val, val) { //if x.val == val
+	//empty list
+	if x == nil {
+		return -1
+	}
+
+	if list.equals(x.val, val) { //if x.val == val
 		return 0
 	}
 	sz := list.count()
 	for i := 0; i < sz; i++ {
-		if list.Equals(val, x.val) { //if val == x.val
+		if list.equals(val, x.val) { //if val == x.val
 			return i
 		}
 
@@ -695,14 +804,13 @@ func (list *AnyList[T]) containsNode(node *node[T]) bool {
  */
 func (list *AnyList[T]) prepend(val T) {
 	f := list.firstNode
-	newNode := init_node(nil, val, f)
-	list.firstNode = newNode
+	newNode := list.newNode(nil, val, nil)
 	if f == nil {
-		list.lastNode = newNode
+		list.linkNodes(newNode, newNode, nil, nil, 1)
 	} else {
-		f.prev = newNode
+		list.linkNodes(newNode, newNode, f.prev, f, 1)
 	}
-	list.incrementSize(1)
+	list.linked(newNode)
 }
 
 /**
@@ -710,15 +818,10 @@ func (list *AnyList[T]) prepend(val T) {
  */
 func (list *AnyList[T]) append(val T) {
 
-	l := list.lastNode
-	newNode := init_node(l, val, nil)
-	list.lastNode = newNode
-	if l == nil {
-		list.firstNode = newNode
-	} else {
-		l.next = newNode
-	}
-	list.incrementSize(1)
+	prev, next := list.tailGap()
+	newNode := list.newNode(nil, val, nil)
+	list.linkNodes(newNode, newNode, prev, next, 1)
+	list.linked(newNode)
 }
 
 /**
@@ -729,17 +832,9 @@ func (list *AnyList[T]) append(val T) {
  */
 func (list *AnyList[T]) insertBefore(e T, succ *node[T]) *node[T] {
 
-	prev := succ.prev
-
-	newNode := init_node(prev, e, succ)
-
-	succ.prev = newNode
-	if prev == nil {
-		list.firstNode = newNode
-	} else {
-		prev.next = newNode
-	}
-	list.incrementSize(1)
+	newNode := list.newNode(nil, e, nil)
+	list.linkNodes(newNode, newNode, succ.prev, succ, 1)
+	list.linked(newNode)
 	return newNode
 }
 
@@ -752,25 +847,17 @@ func (list *AnyList[T]) insertBefore(e T, succ *node[T]) *node[T] {
  */
 func (list *AnyList[T]) insertAfter(e T, succ *node[T]) *node[T] {
 
-	next := succ.next
-
-	newNode := init_node(succ, e, succ.next)
-
-	succ.next = newNode
-	if next == nil {
-		list.lastNode = newNode
-	} else {
-		next.prev = newNode
-	}
-	list.incrementSize(1)
+	newNode := list.newNode(nil, e, nil)
+	list.linkNodes(newNode, newNode, succ, succ.next, 1)
+	list.linked(newNode)
 
 	return newNode
 }
 
 func (list *AnyList[T]) Clear() bool {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("Clear")
 	list.clear()
 
 	return true
@@ -783,107 +870,63 @@ func (list *AnyList[T]) clear() {
 	last := list.lastNode
 
 	if first != nil && last != nil {
-		/**
-		Must be a sublist embedded inside a list
-		[23,9,10,12,34,28,99,55,32]--parent
-		     [10,12,34,28]--sublist
-		*/
-		if first.prev != nil && last.next != nil {
-
-			checkForNodeBeforeFirst := first.prev
-			checkForNodeBeyondLast := last.next
-
-			checkForNodeBeforeFirst.next = checkForNodeBeyondLast
-			checkForNodeBeyondLast.prev = checkForNodeBeforeFirst
-
-		}
-
-		/**
-		Must be a sublist starting from start of parent but parent extends beyond end of sublist e.g.
-		[23,9,10,12,34,28,99]--parent
-		[23,9,10,12]--sublist
-		*/
-		if first.prev == nil && last.next != nil {
-			if list.parent != nil {
-				list.parent.firstNode = last.next
-			}
-		}
-		/**
-		Must be a sublist ending on the tail of the parent, but starting somewhere within the parent e.g.
-		[23,9,10,12,34,28,99,43,215,28]--parent
-		                 [99,43,215,28]--sublist
-		*/
-		if first.prev != nil && last.next == nil {
-			if list.parent != nil {
-				list.parent.lastNode = first.prev
-			}
-		}
+		list.unlinkingRange(first, last)
+		list.unlinkNodes(first, last, sz)
+		list.releaseRange(first, last)
 	}
 
-	var nilVal T
+	list.iter = nil
+	list.nodeIter = nil
+}
 
-	list.forEachNode(func(x *node[T]) bool {
+// releaseRange ...Wipes and releases a chain of nodes that has been cut out of the list
+func (list *AnyList[T]) releaseRange(first *node[T], last *node[T]) {
+	var nilVal T
+	x := first
+	for x != nil {
 		next := x.next
+		done := x == last
+		retire(x)
 		x.val = nilVal
 		x.next = nil
 		x.prev = nil
+		list.releaseNode(x)
+		if done {
+			break
+		}
 		x = next
-		return true
-	})
-
-	list.firstNode = nil
-	list.lastNode = nil
-	list.size = 0
-	list.iter = nil
-	list.nodeIter = nil
-
-	if list.parent != nil {
-		list.parent.decrementSize(sz)
 	}
-
 }
 
 // Not tested yet
 func (list *AnyList[T]) removeLinkedRange(startNode *node[T], stopNode *node[T]) {
 
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlockAndDeliver()
+	list.lock("removeLinkedRange")
 
 	if startNode != nil && stopNode != nil {
-		prev := startNode.prev
-		next := stopNode.next
-
-		if prev != nil {
-			prev.next = next
-		}
-		if next != nil {
-			next.prev = prev
-		}
-
-		x := startNode
-
-		var nilVal T
-		i := 0
-		for {
-			x.val = nilVal
-			x = x.next
-			i++
+		n := 0
+		for x := startNode; x != nil; x = x.next {
+			n++
 			if x == stopNode {
-				x.val = nilVal
-				i++
 				break
 			}
 		}
-		list.decrementSize(i)
 
+		list.unlinkingRange(startNode, stopNode)
+		list.unlinkNodes(startNode, stopNode, n)
+		list.releaseRange(startNode, stopNode)
 	}
 
 }
 
+// Log ...Prints the values and length of the list to stdout.
+//
+// Deprecated: use Dump, which writes to any io.Writer, or print the list with the fmt verbs.
 func (list *AnyList[T]) Log(optionalLabel string) {
-	defer list.mu.Unlock()
+	defer list.unlock()
 
-	list.mu.Lock()
+	list.lock("Log")
 	list.log(optionalLabel)
 }
 
@@ -953,7 +996,14 @@ func (list *AnyList[T]) sync() {
 		parenLen := list.parent.count()
 		sizeChanged := parenLen != list.parenLen
 
-		if sizeChanged {
+		counted := false
+		if sizeChanged && list.root().order != nil {
+			list.size, counted = list.orderedCount()
+		}
+
+		if counted {
+			list.parenLen = parenLen
+		} else if sizeChanged {
 
 			i := 0
 			list.forEachNode(func(x *node[T]) bool {
@@ -972,7 +1022,7 @@ func (list *AnyList[T]) count() int {
 }
 
 func (list *AnyList[T]) Count() int {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("Count")
 	return list.size
 }
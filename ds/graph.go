@@ -0,0 +1,420 @@
+package ds
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"weak"
+)
+
+// Diagrams of the node graph.
+//
+// WriteDOT and WriteMermaid draw the nodes of a list the way they are
+// actually linked, not the way they should be: every node gets a solid edge
+// to its next node and a dashed one back to its prev node, so a broken link
+// shows up as a lone or crossed arrow. Neighbours outside the list (the
+// parent's nodes around a sublist) are drawn as small dots.
+//
+// The head and tail markers point at the list's firstNode and lastNode, and
+// every SubList taken from the list that is still in use gets a box with
+// edges to its own first and last node. Sublists of sublists are numbered
+// after their parent, e.g. "SubList 1.2".
+//
+// The diagram is a snapshot taken with the list locked. Each sublist is
+// locked in turn, after its parent, and brought up to date with the changes
+// made through its parent before its ends are read.
+//
+// To find its sublists, a list keeps a weak pointer to every SubList taken
+// from it: one small allocation per SubList call, and the reason the module
+// needs Go 1.24 for the weak package. The pointers do not keep the sublists
+// alive, and those that have been collected are dropped as new ones come.
+
+// maxGraphLabel - Values with more runes are cut short in diagrams
+const maxGraphLabel = 32
+
+// listGraph - A snapshot of the nodes of a list, independent of the list type
+type listGraph struct {
+	kind  string
+	nodes []graphNode
+	// ids of the first and last node, -1 for none
+	head, tail int
+	views      []graphView
+}
+
+type graphNode struct {
+	label string
+	// ids of the linked nodes, -1 for nil
+	next, prev int
+	// drawn as a dot: the node is linked to but does not belong to the list
+	outside bool
+}
+
+type graphView struct {
+	name        string
+	size        int
+	first, last int
+}
+
+// graphViewSource - A sublist's ends, read from the list, before they are turned into node ids
+type graphViewSource[P comparable] struct {
+	first, last P
+	size        int
+	children    []graphViewSource[P]
+}
+
+// buildGraph ...Snapshots the chain of nodes from first to last
+func buildGraph[P comparable](kind string, first P, last P, next func(P) P, prev func(P) P, label func(P) string, views []graphViewSource[P]) *listGraph {
+	var none P
+	g := &listGraph{kind: kind, head: -1, tail: -1}
+	ids := make(map[P]int)
+
+	var walked []P
+	for x := first; x != none; x = next(x) {
+		if _, ok := ids[x]; ok {
+			// a cycle: every node of it has been drawn already
+			break
+		}
+		ids[x] = len(g.nodes)
+		g.nodes = append(g.nodes, graphNode{label: label(x), next: -1, prev: -1})
+		walked = append(walked, x)
+		if x == last {
+			break
+		}
+	}
+
+	ref := func(x P) int {
+		if x == none {
+			return -1
+		}
+		if id, ok := ids[x]; ok {
+			return id
+		}
+		ids[x] = len(g.nodes)
+		g.nodes = append(g.nodes, graphNode{next: -1, prev: -1, outside: true})
+		return ids[x]
+	}
+
+	for i, x := range walked {
+		g.nodes[i].next = ref(next(x))
+		g.nodes[i].prev = ref(prev(x))
+	}
+	g.head = ref(first)
+	g.tail = ref(last)
+
+	var addViews func(prefix string, sources []graphViewSource[P])
+	addViews = func(prefix string, sources []graphViewSource[P]) {
+		for i, v := range sources {
+			name := prefix + strconv.Itoa(i+1)
+			g.views = append(g.views, graphView{name: "SubList " + name, size: v.size, first: ref(v.first), last: ref(v.last)})
+			addViews(name+".", v.children)
+		}
+	}
+	addViews("", views)
+	return g
+}
+
+// graphLabel ...Formats a value for a diagram
+func graphLabel(val interface{}) string {
+	s := []rune(fmt.Sprintf("%v", val))
+	if len(s) > maxGraphLabel {
+		s = append(s[:maxGraphLabel-3], []rune("...")...)
+	}
+	return string(s)
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (g *listGraph) writeDOT(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph " + g.kind + " {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	b.WriteString("\thead [shape=plaintext];\n")
+	b.WriteString("\ttail [shape=plaintext];\n")
+
+	for i, n := range g.nodes {
+		if n.outside {
+			fmt.Fprintf(&b, "\tn%d [shape=point];\n", i)
+		} else {
+			fmt.Fprintf(&b, "\tn%d [label=\"%s\"];\n", i, dotEscaper.Replace(n.label))
+		}
+	}
+	for i, n := range g.nodes {
+		if n.next >= 0 {
+			fmt.Fprintf(&b, "\tn%d -> n%d;\n", i, n.next)
+		}
+		if n.prev >= 0 {
+			fmt.Fprintf(&b, "\tn%d -> n%d [style=dashed, color=gray];\n", i, n.prev)
+		}
+	}
+	if g.head >= 0 {
+		fmt.Fprintf(&b, "\thead -> n%d;\n", g.head)
+	}
+	if g.tail >= 0 {
+		fmt.Fprintf(&b, "\ttail -> n%d;\n", g.tail)
+	}
+
+	for i, v := range g.views {
+		fmt.Fprintf(&b, "\tv%d [label=\"%s\\nsize %d\", style=dashed];\n", i, v.name, v.size)
+		if v.first >= 0 {
+			fmt.Fprintf(&b, "\tv%d -> n%d [style=dotted, label=\"first\"];\n", i, v.first)
+		}
+		if v.last >= 0 {
+			fmt.Fprintf(&b, "\tv%d -> n%d [style=dotted, label=\"last\"];\n", i, v.last)
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "\n", " ")
+
+func (g *listGraph) writeMermaid(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+	b.WriteString("\thead((head))\n")
+	b.WriteString("\ttail((tail))\n")
+
+	for i, n := range g.nodes {
+		if n.outside {
+			fmt.Fprintf(&b, "\tn%d(( ))\n", i)
+		} else {
+			fmt.Fprintf(&b, "\tn%d[\"%s\"]\n", i, mermaidEscaper.Replace(n.label))
+		}
+	}
+	for i, n := range g.nodes {
+		if n.next >= 0 {
+			fmt.Fprintf(&b, "\tn%d --> n%d\n", i, n.next)
+		}
+		if n.prev >= 0 {
+			fmt.Fprintf(&b, "\tn%d -.-> n%d\n", i, n.prev)
+		}
+	}
+	if g.head >= 0 {
+		fmt.Fprintf(&b, "\thead --> n%d\n", g.head)
+	}
+	if g.tail >= 0 {
+		fmt.Fprintf(&b, "\ttail --> n%d\n", g.tail)
+	}
+
+	for i, v := range g.views {
+		fmt.Fprintf(&b, "\tv%d[/\"%s, size %d\"/]\n", i, v.name, v.size)
+		if v.first >= 0 {
+			fmt.Fprintf(&b, "\tv%d -. first .-> n%d\n", i, v.first)
+		}
+		if v.last >= 0 {
+			fmt.Fprintf(&b, "\tv%d -. last .-> n%d\n", i, v.last)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// pruneViews ...Drops the sublists that have been collected from views
+func pruneViews[L any](views []weak.Pointer[L]) []weak.Pointer[L] {
+	kept := views[:0]
+	for _, v := range views {
+		if v.Value() != nil {
+			kept = append(kept, v)
+		}
+	}
+	clear(views[len(kept):])
+	return kept
+}
+
+// liveViews ...Drops the sublists that have been collected from views and returns the others
+func liveViews[L any](views []weak.Pointer[L]) ([]weak.Pointer[L], []*L) {
+	kept := views[:0]
+	var live []*L
+	for _, v := range views {
+		if l := v.Value(); l != nil {
+			kept = append(kept, v)
+			live = append(live, l)
+		}
+	}
+	clear(views[len(kept):])
+	return kept, live
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// WriteDOT ...Writes the node graph of the list and of its live sublists as a Graphviz digraph, see graph.go
+func (list *AnyList[T]) WriteDOT(w io.Writer) error {
+	return list.graph().writeDOT(w)
+}
+
+// WriteMermaid ...Writes the node graph of the list and of its live sublists as a Mermaid flowchart, see graph.go
+func (list *AnyList[T]) WriteMermaid(w io.Writer) error {
+	return list.graph().writeMermaid(w)
+}
+
+func (list *AnyList[T]) graph() *listGraph {
+	defer list.unlock()
+	list.lock("Draw")
+
+	return buildGraph("AnyList", list.firstNode, list.lastNode,
+		func(x *node[T]) *node[T] { return x.next },
+		func(x *node[T]) *node[T] { return x.prev },
+		func(x *node[T]) string { return graphLabel(x.val) },
+		list.viewSources())
+}
+
+// addView ...Registers a sublist taken from this list
+func (list *AnyList[T]) addView(sub *AnyList[T]) {
+	// pruning only when the slice is full keeps taking many sublists linear
+	if len(list.views) == cap(list.views) {
+		list.views = pruneViews(list.views)
+	}
+	list.views = append(list.views, weak.Make(sub))
+}
+
+func (list *AnyList[T]) viewSources() []graphViewSource[*node[T]] {
+	var live []*AnyList[T]
+	list.views, live = liveViews(list.views)
+
+	var sources []graphViewSource[*node[T]]
+	for _, sub := range live {
+		sources = append(sources, sub.viewSource())
+	}
+	return sources
+}
+
+// viewSource ...Reads the ends of a sublist, and those of its own sublists, with it locked and in step with its parent
+func (list *AnyList[T]) viewSource() graphViewSource[*node[T]] {
+	defer list.unlock()
+	list.lock("Draw")
+	list.sync()
+
+	return graphViewSource[*node[T]]{
+		first:    list.firstNode,
+		last:     list.lastNode,
+		size:     list.size,
+		children: list.viewSources(),
+	}
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// WriteDOT ...Writes the node graph of the list and of its live sublists as a Graphviz digraph, see graph.go
+func (list *List[T]) WriteDOT(w io.Writer) error {
+	return list.graph().writeDOT(w)
+}
+
+// WriteMermaid ...Writes the node graph of the list and of its live sublists as a Mermaid flowchart, see graph.go
+func (list *List[T]) WriteMermaid(w io.Writer) error {
+	return list.graph().writeMermaid(w)
+}
+
+func (list *List[T]) graph() *listGraph {
+	defer list.unlock()
+	list.lock("Draw")
+
+	return buildGraph("List", list.firstNode, list.lastNode,
+		func(x *lNode[T]) *lNode[T] { return x.next },
+		func(x *lNode[T]) *lNode[T] { return x.prev },
+		func(x *lNode[T]) string { return graphLabel(x.val) },
+		list.viewSources())
+}
+
+// addView ...Registers a sublist taken from this list
+func (list *List[T]) addView(sub *List[T]) {
+	// pruning only when the slice is full keeps taking many sublists linear
+	if len(list.views) == cap(list.views) {
+		list.views = pruneViews(list.views)
+	}
+	list.views = append(list.views, weak.Make(sub))
+}
+
+func (list *List[T]) viewSources() []graphViewSource[*lNode[T]] {
+	var live []*List[T]
+	list.views, live = liveViews(list.views)
+
+	var sources []graphViewSource[*lNode[T]]
+	for _, sub := range live {
+		sources = append(sources, sub.viewSource())
+	}
+	return sources
+}
+
+// viewSource ...Reads the ends of a sublist, and those of its own sublists, with it locked and in step with its parent
+func (list *List[T]) viewSource() graphViewSource[*lNode[T]] {
+	defer list.unlock()
+	list.lock("Draw")
+	list.sync()
+
+	return graphViewSource[*lNode[T]]{
+		first:    list.firstNode,
+		last:     list.lastNode,
+		size:     list.size,
+		children: list.viewSources(),
+	}
+}
+
+// ---------------------------------------------------------------------------
+// CList
+// ---------------------------------------------------------------------------
+
+// WriteDOT ...Writes the node graph of the list and of its live sublists as a Graphviz digraph, see graph.go
+func (list *CList) WriteDOT(w io.Writer) error {
+	return list.graph().writeDOT(w)
+}
+
+// WriteMermaid ...Writes the node graph of the list and of its live sublists as a Mermaid flowchart, see graph.go
+func (list *CList) WriteMermaid(w io.Writer) error {
+	return list.graph().writeMermaid(w)
+}
+
+func (list *CList) graph() *listGraph {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	return buildGraph("CList", list.firstNode, list.lastNode,
+		func(x *cNode) *cNode { return x.next },
+		func(x *cNode) *cNode { return x.prev },
+		func(x *cNode) string { return graphLabel(x.val) },
+		list.viewSources())
+}
+
+// addView ...Registers a sublist taken from this list
+func (list *CList) addView(sub *CList) {
+	// pruning only when the slice is full keeps taking many sublists linear
+	if len(list.views) == cap(list.views) {
+		list.views = pruneViews(list.views)
+	}
+	list.views = append(list.views, weak.Make(sub))
+}
+
+func (list *CList) viewSources() []graphViewSource[*cNode] {
+	var live []*CList
+	list.views, live = liveViews(list.views)
+
+	var sources []graphViewSource[*cNode]
+	for _, sub := range live {
+		sources = append(sources, sub.viewSource())
+	}
+	return sources
+}
+
+// viewSource ...Reads the ends of a sublist, and those of its own sublists, with it locked and in step with its parent
+func (list *CList) viewSource() graphViewSource[*cNode] {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	return graphViewSource[*cNode]{
+		first:    list.firstNode,
+		last:     list.lastNode,
+		size:     list.size,
+		children: list.viewSources(),
+	}
+}
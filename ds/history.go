@@ -0,0 +1,558 @@
+package ds
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Undo and redo.
+//
+// A list built WithHistory records every change made to it, including
+// changes made through its sublists, as the events described in events.go.
+// Undo replays the inverse of the events of the latest step, Redo replays
+// the events themselves.
+//
+// Every call to a method that changes the list is one step. BeginGroup and
+// EndGroup merge all the steps in between into a single named step, so that
+// e.g. "paste" or "move line" can be undone at once. Groups may be nested;
+// only the outermost one counts.
+//
+// Undo and Redo rebuild the nodes they bring back, so sublists taken before
+// an Undo or Redo must not be used afterwards.
+//
+// The history is meant for lists that have a single writer, as an editor's
+// document does: changes made by another goroutine while a step is being
+// recorded would end up in that step. Undo and Redo pick their step with the
+// list locked, so concurrent calls revert or reapply one step each, in order.
+
+// historyStep - The events of one undoable step
+type historyStep[T any] struct {
+	name   string
+	events []Event[T]
+}
+
+// history - The undo and redo stacks of a list
+type history[T any] struct {
+	mu     sync.Mutex
+	limit  int
+	done   []historyStep[T]
+	undone []historyStep[T]
+	// events of the change in progress
+	current []Event[T]
+	// the group being recorded, if any
+	group *historyStep[T]
+	depth int
+	// set while Undo or Redo change the list
+	replaying bool
+}
+
+func newHistory[T any](limit int) *history[T] {
+	return &history[T]{limit: limit}
+}
+
+// record ...Adds an event to the change in progress
+func (h *history[T]) record(ev Event[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.replaying {
+		return
+	}
+	ev.Values = append([]T(nil), ev.Values...)
+	h.current = append(h.current, ev)
+}
+
+// seal ...Turns the change in progress into a step, or adds it to the open group
+func (h *history[T]) seal() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sealLocked()
+}
+
+func (h *history[T]) sealLocked() {
+	if len(h.current) == 0 {
+		return
+	}
+	if h.group != nil {
+		h.group.events = append(h.group.events, h.current...)
+	} else {
+		h.pushLocked(historyStep[T]{name: stepName(h.current), events: h.current})
+	}
+	h.current = nil
+}
+
+// pushLocked ...Adds a new step to the undo stack. A new step makes the redo stack obsolete.
+func (h *history[T]) pushLocked(step historyStep[T]) {
+	h.done = append(h.done, step)
+	if h.limit > 0 && len(h.done) > h.limit {
+		h.done[0] = historyStep[T]{}
+		h.done = h.done[1:]
+	}
+	h.undone = nil
+}
+
+func (h *history[T]) begin(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sealLocked()
+	h.depth++
+	if h.depth == 1 {
+		h.group = &historyStep[T]{name: name}
+	}
+}
+
+func (h *history[T]) end() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.depth == 0 {
+		return errors.New("EndGroup called without a matching BeginGroup")
+	}
+	h.sealLocked()
+	h.depth--
+	if h.depth == 0 {
+		group := h.group
+		h.group = nil
+		if len(group.events) > 0 {
+			h.pushLocked(*group)
+		}
+	}
+	return nil
+}
+
+// takeUndo ...Pops the latest step off the undo stack
+func (h *history[T]) takeUndo() (historyStep[T], bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sealLocked()
+	if len(h.done) == 0 || h.depth > 0 {
+		return historyStep[T]{}, false
+	}
+	step := h.done[len(h.done)-1]
+	h.done = h.done[:len(h.done)-1]
+	return step, true
+}
+
+// takeRedo ...Pops the latest undone step off the redo stack
+func (h *history[T]) takeRedo() (historyStep[T], bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sealLocked()
+	if len(h.undone) == 0 || h.depth > 0 {
+		return historyStep[T]{}, false
+	}
+	step := h.undone[len(h.undone)-1]
+	h.undone = h.undone[:len(h.undone)-1]
+	return step, true
+}
+
+func (h *history[T]) setReplaying(replaying bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.replaying = replaying
+}
+
+// finishUndo ...Moves an undone step onto the redo stack
+func (h *history[T]) finishUndo(step historyStep[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.undone = append(h.undone, step)
+}
+
+// finishRedo ...Moves a redone step back onto the undo stack without dropping the rest of the redo stack
+func (h *history[T]) finishRedo(step historyStep[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.done = append(h.done, step)
+}
+
+// putBackUndo ...Pushes a step that could not be undone back onto the undo stack
+func (h *history[T]) putBackUndo(step historyStep[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.done = append(h.done, step)
+}
+
+// putBackRedo ...Pushes a step that could not be redone back onto the redo stack
+func (h *history[T]) putBackRedo(step historyStep[T]) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.undone = append(h.undone, step)
+}
+
+// undoEvents ...Returns the events that revert step, in the order they must be replayed
+func undoEvents[T any](step historyStep[T]) []Event[T] {
+	events := make([]Event[T], len(step.events))
+	for i, ev := range step.events {
+		events[len(events)-1-i] = inverseEvent(ev)
+	}
+	return events
+}
+
+// names ...Returns the name of the step Undo and Redo would take next
+func (h *history[T]) names() (string, string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sealLocked()
+	undo, redo := "", ""
+	if len(h.done) > 0 {
+		undo = h.done[len(h.done)-1].name
+	}
+	if len(h.undone) > 0 {
+		redo = h.undone[len(h.undone)-1].name
+	}
+	return undo, redo
+}
+
+// stepName ...Names an ungrouped step after its first event, e.g. "insert" or "set"
+func stepName[T any](events []Event[T]) string {
+	return strings.ToLower(events[0].Kind.String())
+}
+
+// inverseEvent ...Returns the event that undoes ev
+func inverseEvent[T any](ev Event[T]) Event[T] {
+	switch ev.Kind {
+	case EventInserted:
+		ev.Kind = EventRemoved
+	case EventRemoved:
+		ev.Kind = EventInserted
+	case EventSet:
+		ev.Old, ev.New = ev.New, ev.Old
+	case EventCleared:
+		ev.Kind = EventInserted
+		ev.Index = 0
+	}
+	return ev
+}
+
+func errReplay[T any](ev Event[T]) error {
+	return errors.New("cannot replay " + ev.Kind.String() + " at index " + strconv.Itoa(ev.Index) + ": the list no longer matches the history")
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// WithHistory ...Starts recording changes so they can be undone, and returns the list.
+// At most limit steps are kept; a limit of 0 or less keeps them all.
+// When called on a sublist the history is kept by the list at the top of the sublist chain.
+func (list *AnyList[T]) WithHistory(limit int) *AnyList[T] {
+	root := list.root()
+
+	defer root.unlock()
+	root.lock("WithHistory")
+
+	root.history = newHistory[T](limit)
+	return list
+}
+
+// BeginGroup ...Starts a named group: every change made until the matching EndGroup is undone and redone at once
+func (list *AnyList[T]) BeginGroup(name string) {
+	if h := list.root().history; h != nil {
+		h.begin(name)
+	}
+}
+
+// EndGroup ...Closes the group opened by the matching BeginGroup
+func (list *AnyList[T]) EndGroup() error {
+	h := list.root().history
+	if h == nil {
+		return errors.New("the list has no history")
+	}
+	return h.end()
+}
+
+// Undo ...Reverts the latest step. Returns false if there is nothing to undo, or while a group is open.
+// If the list no longer matches the step, the list is left as it was, the step stays on the undo stack and false is returned.
+func (list *AnyList[T]) Undo() bool {
+	root := list.root()
+
+	defer root.unlockAndDeliver()
+	root.lock("Undo")
+
+	h := root.history
+	if h == nil {
+		return false
+	}
+	step, ok := h.takeUndo()
+	if !ok {
+		return false
+	}
+	if !root.replayAll(h, undoEvents(step)) {
+		h.putBackUndo(step)
+		return false
+	}
+	h.finishUndo(step)
+	return true
+}
+
+// Redo ...Reapplies the latest undone step. Returns false if there is nothing to redo, or while a group is open.
+// If the list no longer matches the step, the list is left as it was, the step stays on the redo stack and false is returned.
+func (list *AnyList[T]) Redo() bool {
+	root := list.root()
+
+	defer root.unlockAndDeliver()
+	root.lock("Redo")
+
+	h := root.history
+	if h == nil {
+		return false
+	}
+	step, ok := h.takeRedo()
+	if !ok {
+		return false
+	}
+	if !root.replayAll(h, step.events) {
+		h.putBackRedo(step)
+		return false
+	}
+	h.finishRedo(step)
+	return true
+}
+
+// replayAll ...Replays events in order without recording them. If one of them fails, the ones already
+// replayed are reverted, so the list is left as it was, and false is returned. Only to be called with the lock held.
+func (list *AnyList[T]) replayAll(h *history[T], events []Event[T]) bool {
+	h.setReplaying(true)
+	defer h.setReplaying(false)
+
+	for i, ev := range events {
+		if err := list.replay(ev); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				list.replay(inverseEvent(events[j]))
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// UndoName ...Returns the name of the step Undo would revert, or "" if there is none
+func (list *AnyList[T]) UndoName() string {
+	if h := list.root().history; h != nil {
+		undo, _ := h.names()
+		return undo
+	}
+	return ""
+}
+
+// RedoName ...Returns the name of the step Redo would reapply, or "" if there is none
+func (list *AnyList[T]) RedoName() string {
+	if h := list.root().history; h != nil {
+		_, redo := h.names()
+		return redo
+	}
+	return ""
+}
+
+// replay ...Applies an event whose Index is a position in this list. Only to be called with the lock held.
+func (list *AnyList[T]) replay(ev Event[T]) error {
+	switch ev.Kind {
+	case EventInserted:
+		if ev.Index < 0 || ev.Index > list.count() {
+			return errReplay(ev)
+		}
+		if ev.Index == list.count() {
+			list.addValues(ev.Values...)
+			return nil
+		}
+		succ, err := list.getNode(ev.Index)
+		if err != nil {
+			return errReplay(ev)
+		}
+		for _, v := range ev.Values {
+			list.insertBefore(v, succ)
+		}
+	case EventRemoved:
+		x, err := list.getNode(ev.Index)
+		if err != nil || ev.Index+len(ev.Values) > list.count() {
+			return errReplay(ev)
+		}
+		for range ev.Values {
+			next := x.next
+			list.removeNode(x)
+			x = next
+		}
+	case EventSet:
+		x, err := list.getNode(ev.Index)
+		if err != nil {
+			return errReplay(ev)
+		}
+		old := x.val
+		x.val = ev.New
+		list.changed(x, old)
+	case EventCleared:
+		list.clear()
+	}
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// WithHistory ...Starts recording changes so they can be undone, and returns the list.
+// At most limit steps are kept; a limit of 0 or less keeps them all.
+// When called on a sublist the history is kept by the list at the top of the sublist chain.
+func (list *List[T]) WithHistory(limit int) *List[T] {
+	root := list.root()
+
+	defer root.unlock()
+	root.lock("WithHistory")
+
+	root.history = newHistory[T](limit)
+	return list
+}
+
+// BeginGroup ...Starts a named group: every change made until the matching EndGroup is undone and redone at once
+func (list *List[T]) BeginGroup(name string) {
+	if h := list.root().history; h != nil {
+		h.begin(name)
+	}
+}
+
+// EndGroup ...Closes the group opened by the matching BeginGroup
+func (list *List[T]) EndGroup() error {
+	h := list.root().history
+	if h == nil {
+		return errors.New("the list has no history")
+	}
+	return h.end()
+}
+
+// Undo ...Reverts the latest step. Returns false if there is nothing to undo, or while a group is open.
+// If the list no longer matches the step, the list is left as it was, the step stays on the undo stack and false is returned.
+func (list *List[T]) Undo() bool {
+	root := list.root()
+
+	defer root.unlockAndDeliver()
+	root.lock("Undo")
+
+	h := root.history
+	if h == nil {
+		return false
+	}
+	step, ok := h.takeUndo()
+	if !ok {
+		return false
+	}
+	if !root.replayAll(h, undoEvents(step)) {
+		h.putBackUndo(step)
+		return false
+	}
+	h.finishUndo(step)
+	return true
+}
+
+// Redo ...Reapplies the latest undone step. Returns false if there is nothing to redo, or while a group is open.
+// If the list no longer matches the step, the list is left as it was, the step stays on the redo stack and false is returned.
+func (list *List[T]) Redo() bool {
+	root := list.root()
+
+	defer root.unlockAndDeliver()
+	root.lock("Redo")
+
+	h := root.history
+	if h == nil {
+		return false
+	}
+	step, ok := h.takeRedo()
+	if !ok {
+		return false
+	}
+	if !root.replayAll(h, step.events) {
+		h.putBackRedo(step)
+		return false
+	}
+	h.finishRedo(step)
+	return true
+}
+
+// replayAll ...Replays events in order without recording them. If one of them fails, the ones already
+// replayed are reverted, so the list is left as it was, and false is returned. Only to be called with the lock held.
+func (list *List[T]) replayAll(h *history[T], events []Event[T]) bool {
+	h.setReplaying(true)
+	defer h.setReplaying(false)
+
+	for i, ev := range events {
+		if err := list.replay(ev); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				list.replay(inverseEvent(events[j]))
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// UndoName ...Returns the name of the step Undo would revert, or "" if there is none
+func (list *List[T]) UndoName() string {
+	if h := list.root().history; h != nil {
+		undo, _ := h.names()
+		return undo
+	}
+	return ""
+}
+
+// RedoName ...Returns the name of the step Redo would reapply, or "" if there is none
+func (list *List[T]) RedoName() string {
+	if h := list.root().history; h != nil {
+		_, redo := h.names()
+		return redo
+	}
+	return ""
+}
+
+// replay ...Applies an event whose Index is a position in this list. Only to be called with the lock held.
+func (list *List[T]) replay(ev Event[T]) error {
+	switch ev.Kind {
+	case EventInserted:
+		if ev.Index < 0 || ev.Index > list.count() {
+			return errReplay(ev)
+		}
+		if ev.Index == list.count() {
+			list.addValues(ev.Values...)
+			return nil
+		}
+		succ, err := list.getNode(ev.Index)
+		if err != nil {
+			return errReplay(ev)
+		}
+		for _, v := range ev.Values {
+			list.insertBefore(v, succ)
+		}
+	case EventRemoved:
+		x, err := list.getNode(ev.Index)
+		if err != nil || ev.Index+len(ev.Values) > list.count() {
+			return errReplay(ev)
+		}
+		for range ev.Values {
+			next := x.next
+			list.removeNode(x)
+			x = next
+		}
+	case EventSet:
+		x, err := list.getNode(ev.Index)
+		if err != nil {
+			return errReplay(ev)
+		}
+		old := x.val
+		x.val = ev.New
+		list.changed(x, old)
+	case EventCleared:
+		list.clear()
+	}
+	return nil
+}
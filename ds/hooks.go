@@ -0,0 +1,387 @@
+package ds
+
+// Mutation hooks.
+//
+// Every primitive that links, unlinks or changes a node calls one of the
+// hooks below, whichever list (parent or sublist) it runs on. The hooks
+// report to the list at the top of the sublist chain, which owns everything
+// that has to follow the nodes around: the hash index, the position index,
+// the transaction journal, the undo history, the operation log and the event hub.
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// root ...Returns the list at the top of the sublist chain
+func (list *AnyList[T]) root() *AnyList[T] {
+	r := list
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
+}
+
+// walkNodes ...Visits the nodes of the list from first to last without touching the shared iterators
+func (list *AnyList[T]) walkNodes(function func(x *node[T])) {
+	x := list.firstNode
+	last := list.lastNode
+	for x != nil {
+		next := x.next
+		function(x)
+		if x == last {
+			break
+		}
+		x = next
+	}
+}
+
+// remember ...Adds elem to the root's indexes
+func (list *AnyList[T]) remember(elem *node[T]) {
+	if list.order != nil {
+		list.order.insertAfter(elem.prev, elem)
+	}
+	if list.index != nil {
+		list.index.add(list.hash(elem.val), elem)
+	}
+}
+
+// forget ...Drops elem from the root's indexes
+func (list *AnyList[T]) forget(elem *node[T]) {
+	// the index finds elem in its bucket by its position, so elem leaves the index first
+	if list.index != nil {
+		list.index.remove(list.hash(elem.val), elem)
+	}
+	if list.order != nil {
+		list.order.remove(elem)
+	}
+}
+
+// linked ...Must be called right after elem has been linked into the chain of nodes
+func (list *AnyList[T]) linked(elem *node[T]) {
+	root := list.root()
+	root.remember(elem)
+	if root.watched() {
+		root.emit(Event[T]{Kind: EventInserted, Index: root.positionOf(elem), Values: []T{elem.val}})
+	}
+}
+
+// linkedRange ...Must be called right after the nodes from first to last have been linked into the chain of nodes
+func (list *AnyList[T]) linkedRange(first *node[T], last *node[T]) {
+	root := list.root()
+	var values []T
+	watched := root.watched()
+	for x := first; x != nil; x = x.next {
+		root.remember(x)
+		if watched {
+			values = append(values, x.val)
+		}
+		if x == last {
+			break
+		}
+	}
+	if watched && first != nil {
+		root.emit(Event[T]{Kind: EventInserted, Index: root.positionOf(first), Values: values})
+	}
+}
+
+// unlinking ...Must be called right before elem is unlinked from the chain of nodes
+func (list *AnyList[T]) unlinking(elem *node[T]) {
+	root := list.root()
+	if root.watched() {
+		root.emit(Event[T]{Kind: EventRemoved, Index: root.positionOf(elem), Values: []T{elem.val}})
+	}
+	root.forget(elem)
+}
+
+// unlinkingRange ...Must be called right before the nodes from first to last are unlinked from the chain of nodes
+func (list *AnyList[T]) unlinkingRange(first *node[T], last *node[T]) {
+	root := list.root()
+	if first == nil {
+		return
+	}
+	watched := root.watched()
+
+	// the whole root list is going away
+	if first.prev == nil && last.next == nil {
+		if watched {
+			var values []T
+			root.walkNodes(func(x *node[T]) {
+				values = append(values, x.val)
+			})
+			root.emit(Event[T]{Kind: EventCleared, Index: 0, Values: values})
+		}
+		if root.order != nil {
+			root.order.reset()
+		}
+		if root.index != nil {
+			root.index.reset()
+		}
+		return
+	}
+
+	var values []T
+	index := 0
+	if watched {
+		index = root.positionOf(first)
+	}
+	for x := first; x != nil; x = x.next {
+		if watched {
+			values = append(values, x.val)
+		}
+		root.forget(x)
+		if x == last {
+			break
+		}
+	}
+	if watched {
+		root.emit(Event[T]{Kind: EventRemoved, Index: index, Values: values})
+	}
+}
+
+// changed ...Must be called right after the value on elem went from old to elem.val
+func (list *AnyList[T]) changed(elem *node[T], old T) {
+	root := list.root()
+	if root.index != nil {
+		root.index.remove(root.hash(old), elem)
+		root.index.add(root.hash(elem.val), elem)
+	}
+	if root.watched() {
+		root.emit(Event[T]{Kind: EventSet, Index: root.positionOf(elem), Old: old, New: elem.val})
+	}
+}
+
+// watched ...Reports whether changes have to be turned into events. Only meaningful on the root list.
+func (list *AnyList[T]) watched() bool {
+	return list.journal != nil || list.history != nil || list.oplog != nil || list.observed()
+}
+
+// emit ...Passes an event on to the history, the operation log and the subscribers of the root list.
+// During a transaction the event is held back until the transaction commits.
+func (list *AnyList[T]) emit(ev Event[T]) {
+	if list.journal != nil {
+		list.journal.record(ev)
+		return
+	}
+	if list.history != nil {
+		list.history.record(ev)
+	}
+	if list.oplog != nil {
+		list.oplog.append(ev)
+	}
+	if list.observed() {
+		list.hub.push(ev)
+	}
+}
+
+// positionOf ...Returns the position of a linked node in the root list.
+// It is O(log n) with a position index, O(1) at either end and O(n) otherwise.
+func (list *AnyList[T]) positionOf(elem *node[T]) int {
+	if list.order != nil {
+		if r := list.order.rank(elem); r >= 0 {
+			return r
+		}
+	}
+	if elem.prev == nil {
+		return 0
+	}
+	if elem.next == nil {
+		return list.size - 1
+	}
+	i := 0
+	for x := list.firstNode; x != nil; x = x.next {
+		if x == elem {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// root ...Returns the list at the top of the sublist chain
+func (list *List[T]) root() *List[T] {
+	r := list
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
+}
+
+// walkNodes ...Visits the nodes of the list from first to last without touching the shared iterators
+func (list *List[T]) walkNodes(function func(x *lNode[T])) {
+	x := list.firstNode
+	last := list.lastNode
+	for x != nil {
+		next := x.next
+		function(x)
+		if x == last {
+			break
+		}
+		x = next
+	}
+}
+
+// remember ...Adds elem to the root's indexes
+func (list *List[T]) remember(elem *lNode[T]) {
+	if list.order != nil {
+		list.order.insertAfter(elem.prev, elem)
+	}
+	if list.index != nil {
+		list.index.add(elem.val, elem)
+	}
+}
+
+// forget ...Drops elem from the root's indexes
+func (list *List[T]) forget(elem *lNode[T]) {
+	// the index finds elem in its bucket by its position, so elem leaves the index first
+	if list.index != nil {
+		list.index.remove(elem.val, elem)
+	}
+	if list.order != nil {
+		list.order.remove(elem)
+	}
+}
+
+// linked ...Must be called right after elem has been linked into the chain of nodes
+func (list *List[T]) linked(elem *lNode[T]) {
+	root := list.root()
+	root.remember(elem)
+	if root.watched() {
+		root.emit(Event[T]{Kind: EventInserted, Index: root.positionOf(elem), Values: []T{elem.val}})
+	}
+}
+
+// linkedRange ...Must be called right after the nodes from first to last have been linked into the chain of nodes
+func (list *List[T]) linkedRange(first *lNode[T], last *lNode[T]) {
+	root := list.root()
+	var values []T
+	watched := root.watched()
+	for x := first; x != nil; x = x.next {
+		root.remember(x)
+		if watched {
+			values = append(values, x.val)
+		}
+		if x == last {
+			break
+		}
+	}
+	if watched && first != nil {
+		root.emit(Event[T]{Kind: EventInserted, Index: root.positionOf(first), Values: values})
+	}
+}
+
+// unlinking ...Must be called right before elem is unlinked from the chain of nodes
+func (list *List[T]) unlinking(elem *lNode[T]) {
+	root := list.root()
+	if root.watched() {
+		root.emit(Event[T]{Kind: EventRemoved, Index: root.positionOf(elem), Values: []T{elem.val}})
+	}
+	root.forget(elem)
+}
+
+// unlinkingRange ...Must be called right before the nodes from first to last are unlinked from the chain of nodes
+func (list *List[T]) unlinkingRange(first *lNode[T], last *lNode[T]) {
+	root := list.root()
+	if first == nil {
+		return
+	}
+	watched := root.watched()
+
+	// the whole root list is going away
+	if first.prev == nil && last.next == nil {
+		if watched {
+			var values []T
+			root.walkNodes(func(x *lNode[T]) {
+				values = append(values, x.val)
+			})
+			root.emit(Event[T]{Kind: EventCleared, Index: 0, Values: values})
+		}
+		if root.order != nil {
+			root.order.reset()
+		}
+		if root.index != nil {
+			root.index.reset()
+		}
+		return
+	}
+
+	var values []T
+	index := 0
+	if watched {
+		index = root.positionOf(first)
+	}
+	for x := first; x != nil; x = x.next {
+		if watched {
+			values = append(values, x.val)
+		}
+		root.forget(x)
+		if x == last {
+			break
+		}
+	}
+	if watched {
+		root.emit(Event[T]{Kind: EventRemoved, Index: index, Values: values})
+	}
+}
+
+// changed ...Must be called right after the value on elem went from old to elem.val
+func (list *List[T]) changed(elem *lNode[T], old T) {
+	root := list.root()
+	if root.index != nil {
+		root.index.remove(old, elem)
+		root.index.add(elem.val, elem)
+	}
+	if root.watched() {
+		root.emit(Event[T]{Kind: EventSet, Index: root.positionOf(elem), Old: old, New: elem.val})
+	}
+}
+
+// watched ...Reports whether changes have to be turned into events. Only meaningful on the root list.
+func (list *List[T]) watched() bool {
+	return list.journal != nil || list.history != nil || list.oplog != nil || list.observed()
+}
+
+// emit ...Passes an event on to the history, the operation log and the subscribers of the root list.
+// During a transaction the event is held back until the transaction commits.
+func (list *List[T]) emit(ev Event[T]) {
+	if list.journal != nil {
+		list.journal.record(ev)
+		return
+	}
+	if list.history != nil {
+		list.history.record(ev)
+	}
+	if list.oplog != nil {
+		list.oplog.append(ev)
+	}
+	if list.observed() {
+		list.hub.push(ev)
+	}
+}
+
+// positionOf ...Returns the position of a linked node in the root list.
+// It is O(log n) with a position index, O(1) at either end and O(n) otherwise.
+func (list *List[T]) positionOf(elem *lNode[T]) int {
+	if list.order != nil {
+		if r := list.order.rank(elem); r >= 0 {
+			return r
+		}
+	}
+	if elem.prev == nil {
+		return 0
+	}
+	if elem.next == nil {
+		return list.size - 1
+	}
+	i := 0
+	for x := list.firstNode; x != nil; x = x.next {
+		if x == elem {
+			return i
+		}
+		i++
+	}
+	return -1
+}
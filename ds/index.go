@@ -0,0 +1,223 @@
+package ds
+
+import (
+	"slices"
+	"sort"
+)
+
+// Optional hash index over the values of a list.
+//
+// A list built with WithIndex keeps every node in a hash bucket keyed by its
+// value (List) or by a user supplied hash of its value (AnyList), and in a
+// rankTree that knows the position of every node. The structures are kept in
+// step with the list on every mutation, including mutations made through
+// SubList views, which report to the list at the top of the sublist chain.
+//
+// Every bucket is kept ordered by the position of its nodes, so the first
+// occurrence of a value in a list or a sublist is found by a binary search
+// over the bucket instead of a scan. With the index in place:
+//   - Contains is O(1) (plus the cost of comparing values that share a bucket)
+//   - Remove and IndexOf of a value are O(log k·log n) instead of O(n),
+//     k being the number of nodes holding the value
+//
+// The price is memory and a slower write path. Each indexed node costs one
+// rankNode (48 bytes on 64 bit platforms), one entry in the rankTree lookup
+// map and one slot in its hash bucket. For a List[int] that is about 130
+// bytes per element on top of the 24 bytes of the list node itself;
+// BenchmarkIndexMemory in the tests package measures it.
+
+// hashIndex - Buckets of list nodes keyed by (a hash of) their values, each ordered by position
+type hashIndex[K comparable, N comparable] struct {
+	buckets map[K][]N
+	// position of a node in the list; a node must be in the rankTree while it is added or removed
+	rank func(n N) int
+}
+
+func newHashIndex[K comparable, N comparable](rank func(n N) int) *hashIndex[K, N] {
+	return &hashIndex[K, N]{
+		buckets: make(map[K][]N),
+		rank:    rank,
+	}
+}
+
+// search ...Returns the index in bucket of the first node at position r or after it
+func (idx *hashIndex[K, N]) search(bucket []N, r int) int {
+	return sort.Search(len(bucket), func(i int) bool {
+		return idx.rank(bucket[i]) >= r
+	})
+}
+
+func (idx *hashIndex[K, N]) add(key K, n N) {
+	bucket := idx.buckets[key]
+	r := idx.rank(n)
+	if len(bucket) == 0 || idx.rank(bucket[len(bucket)-1]) < r {
+		// appending, the common case
+		idx.buckets[key] = append(bucket, n)
+		return
+	}
+	idx.buckets[key] = slices.Insert(bucket, idx.search(bucket, r), n)
+}
+
+func (idx *hashIndex[K, N]) remove(key K, n N) {
+	bucket := idx.buckets[key]
+	if i := idx.search(bucket, idx.rank(n)); i < len(bucket) && bucket[i] == n {
+		bucket = slices.Delete(bucket, i, i+1)
+	}
+	if len(bucket) == 0 {
+		delete(idx.buckets, key)
+	} else {
+		idx.buckets[key] = bucket
+	}
+}
+
+func (idx *hashIndex[K, N]) candidates(key K) []N {
+	return idx.buckets[key]
+}
+
+// candidatesFrom ...Returns the nodes of the bucket at position from or after it, in order
+func (idx *hashIndex[K, N]) candidatesFrom(key K, from int) []N {
+	bucket := idx.buckets[key]
+	if from == 0 {
+		return bucket
+	}
+	return bucket[idx.search(bucket, from):]
+}
+
+func (idx *hashIndex[K, N]) reset() {
+	idx.buckets = make(map[K][]N)
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// WithIndex ...Turns on the hash index for this list and returns the list.
+// hash must return equal hashes for values that the list's Equals function considers equal.
+// When called on a sublist the index is built for the list at the top of the sublist chain.
+//
+// Build the index before sharing the list between goroutines; building it takes O(n·log n).
+func (list *AnyList[T]) WithIndex(hash func(val T) uint64) *AnyList[T] {
+	defer list.unlock()
+	list.lock("WithIndex")
+
+	root := list.root()
+	root.hash = hash
+	root.order = newRankTree[*node[T]]()
+	root.index = newHashIndex[uint64](root.order.rank)
+
+	root.walkNodes(func(x *node[T]) {
+		root.order.insertAt(root.order.len(), x)
+		root.index.add(hash(x.val), x)
+	})
+
+	return list
+}
+
+func (list *AnyList[T]) indexed() bool {
+	return list.root().index != nil
+}
+
+// indexedFirst ...Finds the first node of this list holding val and its index in this list.
+// Returns nil and -1 if there is none.
+// Only to be called when the list is indexed.
+func (list *AnyList[T]) indexedFirst(val T) (*node[T], int) {
+	root := list.root()
+	if list.firstNode == nil {
+		return nil, -1
+	}
+	lo, hi := 0, root.order.len()-1
+	if list != root {
+		lo = root.order.rank(list.firstNode)
+		hi = root.order.rank(list.lastNode)
+	}
+
+	// values that only share the hash are skipped
+	for _, x := range root.index.candidatesFrom(root.hash(val), lo) {
+		r := root.order.rank(x)
+		if r > hi {
+			break
+		}
+		if list.equals(x.val, val) {
+			return x, r - lo
+		}
+	}
+	return nil, -1
+}
+
+// indexedContains ...Only to be called when the list is indexed
+func (list *AnyList[T]) indexedContains(val T) bool {
+	root := list.root()
+	if list != root {
+		x, _ := list.indexedFirst(val)
+		return x != nil
+	}
+	for _, x := range root.index.candidates(root.hash(val)) {
+		if list.equals(x.val, val) {
+			return true
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// WithIndex ...Turns on the hash index for this list and returns the list.
+// When called on a sublist the index is built for the list at the top of the sublist chain.
+//
+// Build the index before sharing the list between goroutines; building it takes O(n·log n).
+func (list *List[T]) WithIndex() *List[T] {
+	defer list.unlock()
+	list.lock("WithIndex")
+
+	root := list.root()
+	root.order = newRankTree[*lNode[T]]()
+	root.index = newHashIndex[T](root.order.rank)
+
+	root.walkNodes(func(x *lNode[T]) {
+		root.order.insertAt(root.order.len(), x)
+		root.index.add(x.val, x)
+	})
+
+	return list
+}
+
+func (list *List[T]) indexed() bool {
+	return list.root().index != nil
+}
+
+// indexedFirst ...Finds the first node of this list holding val and its index in this list.
+// Returns nil and -1 if there is none.
+// Only to be called when the list is indexed.
+func (list *List[T]) indexedFirst(val T) (*lNode[T], int) {
+	root := list.root()
+	if list.firstNode == nil {
+		return nil, -1
+	}
+	lo, hi := 0, root.order.len()-1
+	if list != root {
+		lo = root.order.rank(list.firstNode)
+		hi = root.order.rank(list.lastNode)
+	}
+
+	candidates := root.index.candidatesFrom(val, lo)
+	if len(candidates) == 0 {
+		return nil, -1
+	}
+	x := candidates[0]
+	if r := root.order.rank(x); r <= hi {
+		return x, r - lo
+	}
+	return nil, -1
+}
+
+// indexedContains ...Only to be called when the list is indexed
+func (list *List[T]) indexedContains(val T) bool {
+	root := list.root()
+	if list != root {
+		x, _ := list.indexedFirst(val)
+		return x != nil
+	}
+	return len(root.index.candidates(val)) > 0
+}
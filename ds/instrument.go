@@ -0,0 +1,126 @@
+package ds
+
+import (
+	"time"
+)
+
+// Instrumentation.
+//
+// A List or AnyList built WithInstrumentation reports to an Instrumentation
+// every time one of its public methods runs, how long the method waited for
+// the list's lock and how long it held it, and how many nodes each
+// positional lookup stepped over. The expvarlist package publishes these
+// measurements through expvar.
+//
+// Lists without instrumentation pay for a nil check per method call and
+// nothing else: no clock is read and nothing is allocated.
+//
+// Sublists report to the instrumentation of the list they were taken from.
+// The Instrumentation is called from whichever goroutine uses the list, so
+// it must be safe for concurrent use, and it may be called with the list's
+// lock held, so it must not call back into the list.
+
+// Instrumentation - Receives measurements from a list, see WithInstrumentation
+type Instrumentation interface {
+	// Op is called once for every call of a public method, with the method's name
+	Op(name string)
+	// LockWaited is called once the lock is acquired, with the time spent waiting for it
+	LockWaited(d time.Duration)
+	// LockHeld is called once the lock is released, with the time it was held
+	LockHeld(d time.Duration)
+	// Traversed is called after every walk to a position, with the number of nodes stepped over.
+	// Lookups through a position index (see WithPositionIndex) do not walk and are not reported.
+	Traversed(hops int)
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// WithInstrumentation ...Reports the list's operations, lock timings and traversals to instr, and returns the list.
+// Set it before sharing the list between goroutines; nil turns instrumentation off.
+func (list *AnyList[T]) WithInstrumentation(instr Instrumentation) *AnyList[T] {
+	list.mu.Lock()
+	list.instr = instr
+	list.mu.Unlock()
+	return list
+}
+
+// lock ...Takes the list's lock on behalf of the public method op
+func (list *AnyList[T]) lock(op string) {
+	instr := list.instr
+	if instr == nil {
+		list.mu.Lock()
+		return
+	}
+	instr.Op(op)
+	start := time.Now()
+	list.mu.Lock()
+	list.lockedAt = time.Now()
+	instr.LockWaited(list.lockedAt.Sub(start))
+}
+
+// unlock ...Releases the lock taken by lock
+func (list *AnyList[T]) unlock() {
+	instr := list.instr
+	if instr == nil {
+		list.mu.Unlock()
+		return
+	}
+	held := time.Since(list.lockedAt)
+	list.mu.Unlock()
+	instr.LockHeld(held)
+}
+
+// traversed ...Reports a walk of hops nodes
+func (list *AnyList[T]) traversed(hops int) {
+	if list.instr != nil {
+		list.instr.Traversed(hops)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// WithInstrumentation ...Reports the list's operations, lock timings and traversals to instr, and returns the list.
+// Set it before sharing the list between goroutines; nil turns instrumentation off.
+func (list *List[T]) WithInstrumentation(instr Instrumentation) *List[T] {
+	list.mu.Lock()
+	list.instr = instr
+	list.mu.Unlock()
+	return list
+}
+
+// lock ...Takes the list's lock on behalf of the public method op
+func (list *List[T]) lock(op string) {
+	instr := list.instr
+	if instr == nil {
+		list.mu.Lock()
+		return
+	}
+	instr.Op(op)
+	start := time.Now()
+	list.mu.Lock()
+	list.lockedAt = time.Now()
+	instr.LockWaited(list.lockedAt.Sub(start))
+}
+
+// unlock ...Releases the lock taken by lock
+func (list *List[T]) unlock() {
+	instr := list.instr
+	if instr == nil {
+		list.mu.Unlock()
+		return
+	}
+	held := time.Since(list.lockedAt)
+	list.mu.Unlock()
+	instr.LockHeld(held)
+}
+
+// traversed ...Reports a walk of hops nodes
+func (list *List[T]) traversed(hops int) {
+	if list.instr != nil {
+		list.instr.Traversed(hops)
+	}
+}
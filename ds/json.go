@@ -0,0 +1,143 @@
+package ds
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// JSON encoding.
+//
+// Every list type implements json.Marshaler and json.Unmarshaler and is
+// encoded as a JSON array of its values, so a List[int] holding 1, 2 and 3
+// encodes exactly as the slice []int{1, 2, 3} does. Values are encoded with
+// encoding/json, so a value type may implement json.Marshaler itself.
+//
+// Unmarshaling replaces the values of the list in a single step, which is
+// one undo step and notifies subscribers of a clear followed by an insert.
+// Like Clear, unmarshaling into a sublist takes its values out of the parent
+// and detaches it. A CList decodes its values as encoding/json decodes into
+// interface{}: numbers become float64, objects map[string]interface{}.
+// A nil *AnyList or *UnrolledList field is decoded into a zero list, which
+// has no Equals function; such a list compares values as NewAnyList's default
+// Equals does, by their printed form, until Equals is set.
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// MarshalJSON ...Implements json.Marshaler: the list encodes as a JSON array of its values
+func (list *AnyList[T]) MarshalJSON() ([]byte, error) {
+	defer list.unlock()
+	list.lock("MarshalJSON")
+
+	return json.Marshal(list.values())
+}
+
+// UnmarshalJSON ...Implements json.Unmarshaler: the values of the JSON array replace those of the list
+func (list *AnyList[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	defer list.unlockAndDeliver()
+	list.lock("UnmarshalJSON")
+
+	list.clear()
+	list.addArray(values)
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// MarshalJSON ...Implements json.Marshaler: the list encodes as a JSON array of its values
+func (list *List[T]) MarshalJSON() ([]byte, error) {
+	defer list.unlock()
+	list.lock("MarshalJSON")
+
+	return json.Marshal(list.values())
+}
+
+// UnmarshalJSON ...Implements json.Unmarshaler: the values of the JSON array replace those of the list
+func (list *List[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	defer list.unlockAndDeliver()
+	list.lock("UnmarshalJSON")
+
+	list.clear()
+	list.addArray(values)
+	return nil
+}
+
+// ---------------------------------------------------------------------------
+// CList
+// ---------------------------------------------------------------------------
+
+// MarshalJSON ...Implements json.Marshaler: the list encodes as a JSON array of its values
+func (list *CList) MarshalJSON() ([]byte, error) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	return json.Marshal(list.values())
+}
+
+// UnmarshalJSON ...Implements json.Unmarshaler: the values of the JSON array replace those of the list
+func (list *CList) UnmarshalJSON(data []byte) error {
+	var values []interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	defer list.check()
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	list.clear()
+	list.addArray(values)
+	return nil
+}
+
+// values ...Returns the values of the list as a slice, without touching the shared iterators
+func (list *CList) values() []interface{} {
+	values := make([]interface{}, 0, list.count())
+	for x := list.firstNode; x != nil && len(values) < list.size; x = x.next {
+		values = append(values, x.val)
+	}
+	return values
+}
+
+// ---------------------------------------------------------------------------
+// UnrolledList[T any]
+// ---------------------------------------------------------------------------
+
+// MarshalJSON ...Implements json.Marshaler: the list encodes as a JSON array of its values
+func (list *UnrolledList[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(list.ToArray())
+}
+
+// UnmarshalJSON ...Implements json.Unmarshaler: the values of the JSON array replace those of the list.
+// Unlike Clear, it leaves a sublist attached to its parent, since an UnrolledList sublist stays a window on it.
+func (list *UnrolledList[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if list.mu == nil {
+		// A zero list, such as the one encoding/json allocates for a nil *UnrolledList field, that no one shares yet
+		list.mu = new(sync.Mutex)
+	}
+
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	list.removeRange(0, list.size)
+	list.addValuesAt(0, values)
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"weak"
 )
 
 // abstractList - An abstraction of a list
@@ -54,6 +56,25 @@ type List[T comparable] struct {
 	iter *lNode[T]
 	//Used for rapid iteration over the list's nodes
 	nodeIter *lNode[T]
+	// Sublists taken from this list, held weakly so that they can still be collected, see WriteDOT
+	views []weak.Pointer[List[T]]
+	// Optional hash index over the values, see WithIndex. Only the root list of a sublist chain holds one.
+	index *hashIndex[T, *lNode[T]]
+	// Optional order-statistics tree that tracks the position of every node
+	order *rankTree[*lNode[T]]
+	// Optional node allocator, see WithAllocator. Only the root list of a sublist chain holds one.
+	alloc nodeAllocator[lNode[T]]
+	// Delivers change notifications, see Subscribe. Only the root list of a sublist chain holds one.
+	hub *eventHub[T]
+	// Records changes for Undo and Redo, see WithHistory. Only the root list of a sublist chain holds one.
+	history *history[T]
+	// Collects the changes of the transaction in progress, see Update. Only the root list of a sublist chain holds one.
+	journal *txJournal[T]
+	// Numbers the changes for replicas, see WithOpLog. Only the root list of a sublist chain holds one.
+	oplog *OpLog[T]
+	// Optional instrumentation, see WithInstrumentation, and when the lock was taken, for LockHeld
+	instr    Instrumentation
+	lockedAt time.Time
 }
 
 func NewList[T comparable]() *List[T] {
@@ -126,14 +147,16 @@ func (list *List[T]) resetIterator() {
 	if list.iter != nil {
 		list.iter = nil
 	}
+	// next() walks the list through the nodes iterator
+	list.resetNodeIterator()
 }
 
 func (list *List[T]) ForEach(function func(val T) bool) {
 
 	x := new(T)
 
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("ForEach")
 	list.resetIterator()
 
 	for {
@@ -200,23 +223,16 @@ func (list *List[T]) ToArray() []T {
 
 // TESTED
 func (list *List[T]) addNode(elem *lNode[T]) {
-	oldLastNode := list.lastNode
-
-	list.lastNode = elem
-	if oldLastNode == nil {
-		list.firstNode = elem
-	} else {
-		oldLastNode.next = elem
-		elem.prev = oldLastNode
-	}
-	list.incrementSize(1)
+	prev, next := list.tailGap()
+	list.linkNodes(elem, elem, prev, next, 1)
+	list.linked(elem)
 }
 
 // TESTED
 func (list *List[T]) Add(val T) {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("Add")
 	list.add(val)
 
 }
@@ -227,9 +243,9 @@ func (list *List[T]) add(val T) {
 }
 
 func (list *List[T]) AddVal(val T, index int) bool {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("AddVal")
 	_, _ = list.addVal(val, index)
 
 	return true
@@ -237,15 +253,15 @@ func (list *List[T]) AddVal(val T, index int) bool {
 
 // TESTED
 func (list *List[T]) addVal(val T, index int) (bool, error) {
-	node := initNode(nil, val, nil)
+	node := list.newNode(nil, val, nil)
 	return list.addNodeAt(node, index)
 
 }
 
 func (list *List[T]) AddValues(args ...T) {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("AddValues")
 	list.addValues(args...)
 
 }
@@ -258,9 +274,9 @@ func (list *List[T]) addValues(args ...T) {
 }
 
 func (list *List[T]) AddArray(array []T) {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("AddArray")
 	list.addArray(array)
 }
 
@@ -270,9 +286,9 @@ func (list *List[T]) addArray(array []T) {
 }
 
 func (list *List[T]) AddAll(lst *List[T]) bool {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("AddAll")
 	_ = list.addAll(lst)
 
 	return true
@@ -285,9 +301,9 @@ func (list *List[T]) addAll(lst *List[T]) error {
 }
 
 func (list *List[T]) AddAllAt(index int, lst *List[T]) bool {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("AddAllAt")
 	_ = list.addAllAt(index, lst)
 
 	return true
@@ -319,37 +335,19 @@ func (list *List[T]) addAllAt(index int, lst *List[T]) error {
 		return errors.New("empty parameter sublist found")
 	}
 
+	var prev, next *lNode[T]
 	if index == sz {
-
-		lastNode := list.lastNode
-
-		lastNode.next = dup.firstNode
-		dup.firstNode.prev = lastNode
-		list.lastNode = dup.lastNode
-
-	} else if index == 0 {
-		oldFirstNode := list.firstNode
-
-		dup.lastNode.next = oldFirstNode
-		oldFirstNode.prev = dup.lastNode
-		list.firstNode = dup.firstNode
+		prev, next = list.tailGap()
 	} else {
 		nodeAtIndex, err := list.getNode(index)
 		if err != nil {
 			return err
 		}
-
-		prevNode := nodeAtIndex.prev
-
-		prevNode.next = dup.firstNode
-		dup.firstNode.prev = prevNode
-
-		dup.lastNode.next = nodeAtIndex
-		nodeAtIndex.prev = dup.lastNode
-
+		prev, next = nodeAtIndex.prev, nodeAtIndex
 	}
 
-	list.incrementSize(numNew)
+	list.linkNodes(dup.firstNode, dup.lastNode, prev, next, numNew)
+	list.linkedRange(dup.firstNode, dup.lastNode)
 	return nil
 }
 
@@ -374,18 +372,8 @@ func (list *List[T]) addNodeAt(elem *lNode[T], index int) (bool, error) {
 			}
 
 			// assert succ != nil;
-			prev := succ.prev
-
-			elem.prev = succ.prev
-			elem.next = succ
-			succ.prev = elem
-
-			if prev == nil {
-				list.firstNode = elem
-			} else {
-				prev.next = elem
-			}
-			list.incrementSize(1)
+			list.linkNodes(elem, elem, succ.prev, succ, 1)
+			list.linked(elem)
 		}
 
 		return true, nil
@@ -396,61 +384,110 @@ func (list *List[T]) addNodeAt(elem *lNode[T], index int) (bool, error) {
 
 }
 
+// incrementSize ...Grows the list and every list above it in the sublist chain by dx.
+// Each sublist's record of its parent's length moves along, so that sync does not recount a sublist that is up to date.
 func (list *List[T]) incrementSize(dx int) {
-	list.size += dx
-	if list.parent != nil {
-		list.parent.size += dx
+	for l := list; l != nil; l = l.parent {
+		l.size += dx
+		if l.parent != nil {
+			l.parenLen += dx
+		}
 	}
 }
 
 func (list *List[T]) decrementSize(dx int) {
-	list.size -= dx
-	if list.parent != nil {
-		list.parent.size -= dx
+	list.incrementSize(-dx)
+}
+
+// tailGap ...Returns the nodes between which a node appended to this list goes
+func (list *List[T]) tailGap() (*lNode[T], *lNode[T]) {
+	if list.lastNode == nil {
+		return nil, nil
 	}
+	return list.lastNode, list.lastNode.next
 }
 
-func (list *List[T]) removeNode(elem *lNode[T]) bool {
-	var nilVal T
-	next := elem.next
-	prev := elem.prev
+// linkNodes ...Links the chain of n nodes from first to last between the adjacent nodes prev and next
+// (either may be nil), then moves the ends of this list and of the lists above it to cover the new nodes.
+// A sublist that has been emptied has no place left in its parent, so it becomes a list of its own here.
+func (list *List[T]) linkNodes(first *lNode[T], last *lNode[T], prev *lNode[T], next *lNode[T], n int) {
+	if list.firstNode == nil && list.parent != nil {
+		list.parent = nil
+		list.parenLen = 0
+	}
 
-	if prev == nil {
-		list.firstNode = next
-	} else {
-		prev.next = next
-		elem.prev = nil
+	first.prev = prev
+	last.next = next
+	if prev != nil {
+		prev.next = first
+	}
+	if next != nil {
+		next.prev = last
 	}
 
-	if next == nil {
-		list.lastNode = prev
-	} else {
+	for l := list; l != nil; l = l.parent {
+		if l.firstNode == next {
+			l.firstNode = first
+		}
+		if l.lastNode == prev {
+			l.lastNode = last
+		}
+	}
+	list.incrementSize(n)
+}
+
+// unlinkNodes ...Cuts the chain of n nodes from first to last out of this list, then moves the ends of
+// this list and of the lists above it off the removed nodes
+func (list *List[T]) unlinkNodes(first *lNode[T], last *lNode[T], n int) {
+	prev := first.prev
+	next := last.next
+	if prev != nil {
+		prev.next = next
+	}
+	if next != nil {
 		next.prev = prev
-		elem.next = nil
 	}
+	first.prev = nil
+	last.next = nil
+
+	for l := list; l != nil; l = l.parent {
+		if l.firstNode == first && l.lastNode == last {
+			l.firstNode = nil
+			l.lastNode = nil
+		} else if l.firstNode == first {
+			l.firstNode = next
+		} else if l.lastNode == last {
+			l.lastNode = prev
+		}
+	}
+	list.decrementSize(n)
+}
 
+func (list *List[T]) removeNode(elem *lNode[T]) bool {
+
+	list.unlinking(elem)
+	list.unlinkNodes(elem, elem, 1)
+
+	var nilVal T
 	elem.val = nilVal
-	list.decrementSize(1)
+	list.releaseNode(elem)
 	return true
+
 }
 
 func (list *List[T]) removeIndex(index int) bool {
 
-	x := list.firstNode
-	for i := 0; i <= index; i++ {
-		if index == i {
-			succ := list.removeNode(x)
-			return succ
-		}
-		x = x.next
+	x, err := list.getNode(index)
+	if err != nil {
+		return false
 	}
-	return false
+	return list.removeNode(x)
 }
 
 func (list *List[T]) Remove(val T) bool {
 
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlockAndDeliver()
+	list.lock("Remove")
 	list.remove(val)
 
 	return true
@@ -467,6 +504,14 @@ func (list *List[T]) remove(val T) bool {
 		return false
 	}
 
+	if list.indexed() {
+		x, _ := list.indexedFirst(val)
+		if x == nil {
+			return false
+		}
+		return list.removeNode(x)
+	}
+
 	x := list.firstNode
 	sz := list.count()
 	for i := 0; i < sz; i++ {
@@ -484,14 +529,14 @@ func (list *List[T]) remove(val T) bool {
 }
 
 func (list *List[T]) RemoveIndex(index int) bool {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlockAndDeliver()
+	list.lock("RemoveIndex")
 	return list.removeIndex(index)
 }
 
 func (list *List[T]) RemoveAll(lst *List[T]) bool {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlockAndDeliver()
+	list.lock("RemoveAll")
 	list.removeAll(lst)
 
 	return true
@@ -505,15 +550,26 @@ func (list *List[T]) removeAll(lst *List[T]) {
 		return
 	}
 
+	// Count how many times each value must go, then drop the earliest
+	// matches in a single pass instead of calling remove once per element.
+	counts := make(map[T]int)
 	x := lst.firstNode
 
 	sz := lst.count()
 
 	for i := 0; i < sz; i++ {
-		list.remove(x.val)
+		counts[x.val]++
 		x = x.next
 	}
 
+	list.removeIf(func(val T) bool {
+		if counts[val] == 0 {
+			return false
+		}
+		counts[val]--
+		return true
+	})
+
 }
 
 func (list *List[T]) IsEmpty() bool {
@@ -523,8 +579,8 @@ func (list *List[T]) IsEmpty() bool {
 // SubList ...Creates a view of the list... starting at startIndex and ending at endIndex-1.
 // In essence, the element at `endIndex` is not included
 func (list *List[T]) SubList(startIndex int, endIndex int) (*List[T], error) {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("SubList")
 
 	if startIndex < 0 {
 		return nil, errors.New("startIndex(" + strconv.Itoa(startIndex) + ") < 0 is not allowed")
@@ -540,12 +596,18 @@ func (list *List[T]) SubList(startIndex int, endIndex int) (*List[T], error) {
 	}
 
 	subList := NewList[T]()
-	start, end := list.getBoundaryNodes(startIndex, endIndex)
+	// an empty view has no nodes to hold on to, see linkNodes
+	var start, end *lNode[T]
+	if startIndex < endIndex {
+		start, end = list.getBoundaryNodes(startIndex, endIndex)
+	}
 	subList.firstNode = start
 	subList.lastNode = end
 	subList.parent = list
 	subList.parenLen = sz
+	subList.instr = list.instr
 	subList.size = endIndex - startIndex
+	list.addView(subList)
 
 	return subList, nil
 
@@ -569,18 +631,24 @@ func (list *List[T]) getNode(index int) (*lNode[T], error) {
 		return nil, errors.New("Index=(" + strconv.Itoa(index) + ") > list-size=(" + strconv.Itoa(list.count()) + ") is not allowed")
 	}
 
+	if list.root().order != nil {
+		return list.orderedNode(index), nil
+	}
+
 	// NOTE x >> y is same as x ÷ 2^y
 	if index < (sz >> 1) {
 		x := list.firstNode
 		for i := 0; i < index; i++ {
 			x = x.next
 		}
+		list.traversed(index)
 		return x, nil
 	} else {
 		x := list.lastNode
 		for i := sz - 1; i > index; i-- {
 			x = x.prev
 		}
+		list.traversed(sz - 1 - index)
 		return x, nil
 	}
 
@@ -601,18 +669,30 @@ func (list *List[T]) getBoundaryNodes(start int, end int) (*lNode[T], *lNode[T])
 
 // Get - returns the element at that index in the list
 func (list *List[T]) Set(index int, val T) {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlockAndDeliver()
+	list.lock("Set")
+	_ = list.set(index, val)
+}
+
+func (list *List[T]) set(index int, val T) error {
 	node, err := list.getNode(index)
-	if err == nil {
-		node.val = val
+	if err != nil {
+		return err
 	}
+	old := node.val
+	node.val = val
+	list.changed(node, old)
+	return nil
 }
 
 // Get - returns the element at that index in the list
 func (list *List[T]) Get(index int) (T, error) {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("Get")
+	return list.get(index)
+}
+
+func (list *List[T]) get(index int) (T, error) {
 	node, err := list.getNode(index)
 	if err == nil {
 		return node.val, nil
@@ -626,22 +706,47 @@ func (list *List[T]) getLastNode() *lNode[T] {
 }
 
 func (list *List[T]) LastElement() interface{} {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("LastElement")
 	return list.getLastNode().val
 }
 
 func (list *List[T]) Contains(val T) bool {
-	return list.IndexOf(val) != -1
+	defer list.unlock()
+	list.lock("Contains")
+
+	return list.contains(val)
+}
+
+func (list *List[T]) contains(val T) bool {
+	if list.indexed() {
+		return list.indexedContains(val)
+	}
+	return list.indexOf(val) != -1
 
 }
 
 func (list *List[T]) IndexOf(val T) int {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("IndexOf")
+
+	return list.indexOf(val)
+}
+
+func (list *List[T]) indexOf(val T) int {
+
+	if list.indexed() {
+		_, i := list.indexedFirst(val)
+		return i
+	}
 
 	x := list.firstNode
 
+	//empty list
+	if x == nil {
+		return -1
+	}
+
 	if x.val == val {
 		return 0
 	}
@@ -687,14 +792,13 @@ func (list *List[T]) containsNode(node *lNode[T]) bool {
  */
 func (list *List[T]) prepend(val T) {
 	f := list.firstNode
-	newNode := initNode(nil, val, f)
-	list.firstNode = newNode
+	newNode := list.newNode(nil, val, nil)
 	if f == nil {
-		list.lastNode = newNode
+		list.linkNodes(newNode, newNode, nil, nil, 1)
 	} else {
-		f.prev = newNode
+		list.linkNodes(newNode, newNode, f.prev, f, 1)
 	}
-	list.incrementSize(1)
+	list.linked(newNode)
 }
 
 /**
@@ -702,15 +806,10 @@ func (list *List[T]) prepend(val T) {
  */
 func (list *List[T]) append(val T) {
 
-	l := list.lastNode
-	newNode := initNode(l, val, nil)
-	list.lastNode = newNode
-	if l == nil {
-		list.firstNode = newNode
-	} else {
-		l.next = newNode
-	}
-	list.incrementSize(1)
+	prev, next := list.tailGap()
+	newNode := list.newNode(nil, val, nil)
+	list.linkNodes(newNode, newNode, prev, next, 1)
+	list.linked(newNode)
 }
 
 /**
@@ -721,17 +820,9 @@ func (list *List[T]) append(val T) {
  */
 func (list *List[T]) insertBefore(e T, succ *lNode[T]) *lNode[T] {
 
-	prev := succ.prev
-
-	newNode := initNode(prev, e, succ)
-
-	succ.prev = newNode
-	if prev == nil {
-		list.firstNode = newNode
-	} else {
-		prev.next = newNode
-	}
-	list.incrementSize(1)
+	newNode := list.newNode(nil, e, nil)
+	list.linkNodes(newNode, newNode, succ.prev, succ, 1)
+	list.linked(newNode)
 	return newNode
 }
 
@@ -744,25 +835,17 @@ func (list *List[T]) insertBefore(e T, succ *lNode[T]) *lNode[T] {
  */
 func (list *List[T]) insertAfter(e T, succ *lNode[T]) *lNode[T] {
 
-	next := succ.next
-
-	newNode := initNode(succ, e, succ.next)
-
-	succ.next = newNode
-	if next == nil {
-		list.lastNode = newNode
-	} else {
-		next.prev = newNode
-	}
-	list.incrementSize(1)
+	newNode := list.newNode(nil, e, nil)
+	list.linkNodes(newNode, newNode, succ, succ.next, 1)
+	list.linked(newNode)
 
 	return newNode
 }
 
 func (list *List[T]) Clear() bool {
-	defer list.mu.Unlock()
+	defer list.unlockAndDeliver()
 
-	list.mu.Lock()
+	list.lock("Clear")
 	list.clear()
 
 	return true
@@ -775,107 +858,62 @@ func (list *List[T]) clear() {
 	last := list.lastNode
 
 	if first != nil && last != nil {
-		/**
-		Must be a sublist embedded inside a list
-		[23,9,10,12,34,28,99,55,32]--parent
-		     [10,12,34,28]--sublist
-		*/
-		if first.prev != nil && last.next != nil {
-
-			checkForNodeBeforeFirst := first.prev
-			checkForNodeBeyondLast := last.next
-
-			checkForNodeBeforeFirst.next = checkForNodeBeyondLast
-			checkForNodeBeyondLast.prev = checkForNodeBeforeFirst
-
-		}
-
-		/**
-		Must be a sublist starting from start of parent but parent extends beyond end of sublist e.g.
-		[23,9,10,12,34,28,99]--parent
-		[23,9,10,12]--sublist
-		*/
-		if first.prev == nil && last.next != nil {
-			if list.parent != nil {
-				list.parent.firstNode = last.next
-			}
-		}
-		/**
-		Must be a sublist ending on the tail of the parent, but starting somewhere within the parent e.g.
-		[23,9,10,12,34,28,99,43,215,28]--parent
-		                 [99,43,215,28]--sublist
-		*/
-		if first.prev != nil && last.next == nil {
-			if list.parent != nil {
-				list.parent.lastNode = first.prev
-			}
-		}
+		list.unlinkingRange(first, last)
+		list.unlinkNodes(first, last, sz)
+		list.releaseRange(first, last)
 	}
 
-	var nilVal T
+	list.iter = nil
+	list.nodeIter = nil
+}
 
-	list.forEachNode(func(x *lNode[T]) bool {
+// releaseRange ...Wipes and releases a chain of nodes that has been cut out of the list
+func (list *List[T]) releaseRange(first *lNode[T], last *lNode[T]) {
+	var nilVal T
+	x := first
+	for x != nil {
 		next := x.next
+		done := x == last
 		x.val = nilVal
 		x.next = nil
 		x.prev = nil
+		list.releaseNode(x)
+		if done {
+			break
+		}
 		x = next
-		return true
-	})
-
-	list.firstNode = nil
-	list.lastNode = nil
-	list.size = 0
-	list.iter = nil
-	list.nodeIter = nil
-
-	if list.parent != nil {
-		list.parent.decrementSize(sz)
 	}
-
 }
 
 // Not tested yet
 func (list *List[T]) removeLinkedRange(startNode *lNode[T], stopNode *lNode[T]) {
 
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlockAndDeliver()
+	list.lock("removeLinkedRange")
 
 	if startNode != nil && stopNode != nil {
-		prev := startNode.prev
-		next := stopNode.next
-
-		if prev != nil {
-			prev.next = next
-		}
-		if next != nil {
-			next.prev = prev
-		}
-
-		x := startNode
-
-		var nilVal T
-		i := 0
-		for {
-			x.val = nilVal
-			x = x.next
-			i++
+		n := 0
+		for x := startNode; x != nil; x = x.next {
+			n++
 			if x == stopNode {
-				x.val = nilVal
-				i++
 				break
 			}
 		}
-		list.decrementSize(i)
 
+		list.unlinkingRange(startNode, stopNode)
+		list.unlinkNodes(startNode, stopNode, n)
+		list.releaseRange(startNode, stopNode)
 	}
 
 }
 
+// Log ...Prints the values and length of the list to stdout.
+//
+// Deprecated: use Dump, which writes to any io.Writer, or print the list with the fmt verbs.
 func (list *List[T]) Log(optionalLabel string) {
-	defer list.mu.Unlock()
+	defer list.unlock()
 
-	list.mu.Lock()
+	list.lock("Log")
 	list.log(optionalLabel)
 }
 
@@ -945,7 +983,14 @@ func (list *List[T]) sync() {
 		parenLen := list.parent.count()
 		sizeChanged := parenLen != list.parenLen
 
-		if sizeChanged {
+		counted := false
+		if sizeChanged && list.root().order != nil {
+			list.size, counted = list.orderedCount()
+		}
+
+		if counted {
+			list.parenLen = parenLen
+		} else if sizeChanged {
 
 			i := 0
 			list.forEachNode(func(x *lNode[T]) bool {
@@ -964,7 +1009,7 @@ func (list *List[T]) count() int {
 }
 
 func (list *List[T]) Count() int {
-	defer list.mu.Unlock()
-	list.mu.Lock()
+	defer list.unlock()
+	list.lock("Count")
 	return list.size
 }
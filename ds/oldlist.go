@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"weak"
 )
 
 // AbstractList - An abstraction of a list
@@ -54,6 +55,8 @@ type CList struct {
 	iter *cNode
 	//Used for rapid iteration over the list's nodes
 	nodeIter *cNode
+	// Sublists taken from this list, held weakly so that they can still be collected, see WriteDOT
+	views []weak.Pointer[CList]
 }
 
 func NewCList() *CList {
@@ -205,20 +208,13 @@ func (list *CList) ToArray() []interface{} {
 
 // TESTED
 func (list *CList) addNode(elem *cNode) {
-	oldLastNode := list.lastNode
-
-	list.lastNode = elem
-	if oldLastNode == nil {
-		list.firstNode = elem
-	} else {
-		oldLastNode.next = elem
-		elem.prev = oldLastNode
-	}
-	list.incrementSize(1)
+	prev, next := list.tailGap()
+	list.linkNodes(elem, elem, prev, next, 1)
 }
 
 // TESTED
 func (list *CList) Add(val interface{}) {
+	defer list.check()
 	defer list.mu.Unlock()
 
 	list.mu.Lock()
@@ -232,6 +228,7 @@ func (list *CList) add(val interface{}) {
 }
 
 func (list *CList) AddVal(val interface{}, index int) bool {
+	defer list.check()
 	defer list.mu.Unlock()
 
 	list.mu.Lock()
@@ -248,6 +245,7 @@ func (list *CList) addVal(val interface{}, index int) (bool, error) {
 }
 
 func (list *CList) AddValues(args ...interface{}) {
+	defer list.check()
 	defer list.mu.Unlock()
 
 	list.mu.Lock()
@@ -263,6 +261,7 @@ func (list *CList) addValues(args ...interface{}) {
 }
 
 func (list *CList) AddArray(array []interface{}) {
+	defer list.check()
 	defer list.mu.Unlock()
 
 	list.mu.Lock()
@@ -275,6 +274,7 @@ func (list *CList) addArray(array []interface{}) {
 }
 
 func (list *CList) AddAll(lst *CList) bool {
+	defer list.check()
 	defer list.mu.Unlock()
 
 	list.mu.Lock()
@@ -290,6 +290,7 @@ func (list *CList) addAll(lst *CList) error {
 }
 
 func (list *CList) AddAllAt(index int, lst *CList) bool {
+	defer list.check()
 	defer list.mu.Unlock()
 
 	list.mu.Lock()
@@ -324,37 +325,18 @@ func (list *CList) addAllAt(index int, lst *CList) error {
 		return errors.New("empty parameter sublist found")
 	}
 
+	var prev, next *cNode
 	if index == sz {
-
-		lastNode := list.lastNode
-
-		lastNode.next = dup.firstNode
-		dup.firstNode.prev = lastNode
-		list.lastNode = dup.lastNode
-
-	} else if index == 0 {
-		oldFirstNode := list.firstNode
-
-		dup.lastNode.next = oldFirstNode
-		oldFirstNode.prev = dup.lastNode
-		list.firstNode = dup.firstNode
+		prev, next = list.tailGap()
 	} else {
 		nodeAtIndex, err := list.getNode(index)
 		if err != nil {
 			return err
 		}
-
-		prevNode := nodeAtIndex.prev
-
-		prevNode.next = dup.firstNode
-		dup.firstNode.prev = prevNode
-
-		dup.lastNode.next = nodeAtIndex
-		nodeAtIndex.prev = dup.lastNode
-
+		prev, next = nodeAtIndex.prev, nodeAtIndex
 	}
 
-	list.incrementSize(numNew)
+	list.linkNodes(dup.firstNode, dup.lastNode, prev, next, numNew)
 	return nil
 }
 
@@ -379,18 +361,7 @@ func (list *CList) addNodeAt(elem *cNode, index int) (bool, error) {
 			}
 
 			// assert succ != nil;
-			prev := succ.prev
-
-			elem.prev = succ.prev
-			elem.next = succ
-			succ.prev = elem
-
-			if prev == nil {
-				list.firstNode = elem
-			} else {
-				prev.next = elem
-			}
-			list.incrementSize(1)
+			list.linkNodes(elem, elem, succ.prev, succ, 1)
 		}
 
 		return true, nil
@@ -401,59 +372,104 @@ func (list *CList) addNodeAt(elem *cNode, index int) (bool, error) {
 
 }
 
+// incrementSize ...Grows the list and every list above it in the sublist chain by dx.
+// Each sublist's record of its parent's length moves along, so that sync does not recount a sublist that is up to date.
 func (list *CList) incrementSize(dx int) {
-	list.size += dx
-	if list.parent != nil {
-		list.parent.size += dx
+	for l := list; l != nil; l = l.parent {
+		l.size += dx
+		if l.parent != nil {
+			l.parenLen += dx
+		}
 	}
 }
 
 func (list *CList) decrementSize(dx int) {
-	list.size -= dx
-	if list.parent != nil {
-		list.parent.size -= dx
+	list.incrementSize(-dx)
+}
+
+// tailGap ...Returns the nodes between which a node appended to this list goes
+func (list *CList) tailGap() (*cNode, *cNode) {
+	if list.lastNode == nil {
+		return nil, nil
 	}
+	return list.lastNode, list.lastNode.next
 }
 
-func (list *CList) removeNode(elem *cNode) bool {
+// linkNodes ...Links the chain of n nodes from first to last between the adjacent nodes prev and next
+// (either may be nil), then moves the ends of this list and of the lists above it to cover the new nodes.
+// A sublist that has been emptied has no place left in its parent, so it becomes a list of its own here.
+func (list *CList) linkNodes(first *cNode, last *cNode, prev *cNode, next *cNode, n int) {
+	if list.firstNode == nil && list.parent != nil {
+		list.parent = nil
+		list.parenLen = 0
+	}
 
-	next := elem.next
-	prev := elem.prev
+	first.prev = prev
+	last.next = next
+	if prev != nil {
+		prev.next = first
+	}
+	if next != nil {
+		next.prev = last
+	}
 
-	if prev == nil {
-		list.firstNode = next
-	} else {
-		prev.next = next
-		elem.prev = nil
+	for l := list; l != nil; l = l.parent {
+		if l.firstNode == next {
+			l.firstNode = first
+		}
+		if l.lastNode == prev {
+			l.lastNode = last
+		}
 	}
+	list.incrementSize(n)
+}
 
-	if next == nil {
-		list.lastNode = prev
-	} else {
+// unlinkNodes ...Cuts the chain of n nodes from first to last out of this list, then moves the ends of
+// this list and of the lists above it off the removed nodes
+func (list *CList) unlinkNodes(first *cNode, last *cNode, n int) {
+	prev := first.prev
+	next := last.next
+	if prev != nil {
+		prev.next = next
+	}
+	if next != nil {
 		next.prev = prev
-		elem.next = nil
 	}
+	first.prev = nil
+	last.next = nil
+
+	for l := list; l != nil; l = l.parent {
+		if l.firstNode == first && l.lastNode == last {
+			l.firstNode = nil
+			l.lastNode = nil
+		} else if l.firstNode == first {
+			l.firstNode = next
+		} else if l.lastNode == last {
+			l.lastNode = prev
+		}
+	}
+	list.decrementSize(n)
+}
+
+func (list *CList) removeNode(elem *cNode) bool {
 
+	list.unlinkNodes(elem, elem, 1)
 	elem.val = nil
-	list.decrementSize(1)
 	return true
 
 }
 func (list *CList) removeIndex(index int) bool {
 
-	x := list.firstNode
-	for i := 0; i <= index; i++ {
-		if index == i {
-			succ := list.removeNode(x)
-			return succ
-		}
-		x = x.next
+	x, err := list.getNode(index)
+	if err != nil {
+		return false
 	}
-	return false
+	return list.removeNode(x)
 }
 
 func (list *CList) Remove(val interface{}) bool {
 
+	defer list.check()
 	defer list.mu.Unlock()
 	list.mu.Lock()
 	list.remove(val)
@@ -489,12 +505,14 @@ func (list *CList) remove(val interface{}) bool {
 }
 
 func (list *CList) RemoveIndex(index int) bool {
+	defer list.check()
 	defer list.mu.Unlock()
 	list.mu.Lock()
 	return list.removeIndex(index)
 }
 
 func (list *CList) RemoveAll(lst *CList) bool {
+	defer list.check()
 	defer list.mu.Unlock()
 	list.mu.Lock()
 	list.removeAll(lst)
@@ -545,12 +563,17 @@ func (list *CList) SubList(startIndex int, endIndex int) (*CList, error) {
 	}
 
 	subList := NewCList()
-	start, end := list.getBoundaryNodes(startIndex, endIndex)
+	// an empty view has no nodes to hold on to, see linkNodes
+	var start, end *cNode
+	if startIndex < endIndex {
+		start, end = list.getBoundaryNodes(startIndex, endIndex)
+	}
 	subList.firstNode = start
 	subList.lastNode = end
 	subList.parent = list
 	subList.parenLen = sz
 	subList.size = endIndex - startIndex
+	list.addView(subList)
 
 	return subList, nil
 
@@ -606,6 +629,7 @@ func (list *CList) getBoundaryNodes(start int, end int) (*cNode, *cNode) {
 
 // Get - returns the element at that index in the list
 func (list *CList) Set(index int, val interface{}) {
+	defer list.check()
 	defer list.mu.Unlock()
 	list.mu.Lock()
 	node, err := list.getNode(index)
@@ -646,6 +670,11 @@ func (list *CList) IndexOf(val interface{}) int {
 
 	x := list.firstNode
 
+	//empty list
+	if x == nil {
+		return -1
+	}
+
 	if x.val == val {
 		return 0
 	}
@@ -692,14 +721,12 @@ func (list *CList) containsNode(node *cNode) bool {
  */
 func (list *CList) prepend(val interface{}) {
 	f := list.firstNode
-	newNode := initCNode(nil, val, f)
-	list.firstNode = newNode
+	newNode := initCNode(nil, val, nil)
 	if f == nil {
-		list.lastNode = newNode
+		list.linkNodes(newNode, newNode, nil, nil, 1)
 	} else {
-		f.prev = newNode
+		list.linkNodes(newNode, newNode, f.prev, f, 1)
 	}
-	list.incrementSize(1)
 }
 
 /**
@@ -707,15 +734,9 @@ func (list *CList) prepend(val interface{}) {
  */
 func (list *CList) append(val interface{}) {
 
-	l := list.lastNode
-	newNode := initCNode(l, val, nil)
-	list.lastNode = newNode
-	if l == nil {
-		list.firstNode = newNode
-	} else {
-		l.next = newNode
-	}
-	list.incrementSize(1)
+	prev, next := list.tailGap()
+	newNode := initCNode(nil, val, nil)
+	list.linkNodes(newNode, newNode, prev, next, 1)
 }
 
 /**
@@ -726,17 +747,8 @@ func (list *CList) append(val interface{}) {
  */
 func (list *CList) insertBefore(e interface{}, succ *cNode) *cNode {
 
-	prev := succ.prev
-
-	newNode := initCNode(prev, e, succ)
-
-	succ.prev = newNode
-	if prev == nil {
-		list.firstNode = newNode
-	} else {
-		prev.next = newNode
-	}
-	list.incrementSize(1)
+	newNode := initCNode(nil, e, nil)
+	list.linkNodes(newNode, newNode, succ.prev, succ, 1)
 	return newNode
 }
 
@@ -749,22 +761,14 @@ func (list *CList) insertBefore(e interface{}, succ *cNode) *cNode {
  */
 func (list *CList) insertAfter(e interface{}, succ *cNode) *cNode {
 
-	next := succ.next
-
-	newNode := initCNode(succ, e, succ.next)
-
-	succ.next = newNode
-	if next == nil {
-		list.lastNode = newNode
-	} else {
-		next.prev = newNode
-	}
-	list.incrementSize(1)
+	newNode := initCNode(nil, e, nil)
+	list.linkNodes(newNode, newNode, succ, succ.next, 1)
 
 	return newNode
 }
 
 func (list *CList) Clear() bool {
+	defer list.check()
 	defer list.mu.Unlock()
 
 	list.mu.Lock()
@@ -780,100 +784,55 @@ func (list *CList) clear() {
 	last := list.lastNode
 
 	if first != nil && last != nil {
-		/**
-		Must be a sublist embedded inside a list
-		[23,9,10,12,34,28,99,55,32]--parent
-		     [10,12,34,28]--sublist
-		*/
-		if first.prev != nil && last.next != nil {
-
-			checkForNodeBeforeFirst := first.prev
-			checkForNodeBeyondLast := last.next
-
-			checkForNodeBeforeFirst.next = checkForNodeBeyondLast
-			checkForNodeBeyondLast.prev = checkForNodeBeforeFirst
-
-		}
-
-		/**
-		Must be a sublist starting from start of parent but parent extends beyond end of sublist e.g.
-		[23,9,10,12,34,28,99]--parent
-		[23,9,10,12]--sublist
-		*/
-		if first.prev == nil && last.next != nil {
-			if list.parent != nil {
-				list.parent.firstNode = last.next
-			}
-		}
-		/**
-		Must be a sublist ending on the tail of the parent, but starting somewhere within the parent e.g.
-		[23,9,10,12,34,28,99,43,215,28]--parent
-		                 [99,43,215,28]--sublist
-		*/
-		if first.prev != nil && last.next == nil {
-			if list.parent != nil {
-				list.parent.lastNode = first.prev
-			}
-		}
+		list.unlinkNodes(first, last, sz)
+		wipeRange(first, last)
 	}
 
-	list.forEachNode(func(x *cNode) bool {
+	list.iter = nil
+	list.nodeIter = nil
+}
+
+// wipeRange ...Clears a chain of nodes that has been cut out of the list
+func wipeRange(first *cNode, last *cNode) {
+	x := first
+	for x != nil {
 		next := x.next
+		done := x == last
 		x.val = nil
 		x.next = nil
 		x.prev = nil
+		if done {
+			break
+		}
 		x = next
-		return true
-	})
-
-	list.firstNode = nil
-	list.lastNode = nil
-	list.size = 0
-	list.iter = nil
-	list.nodeIter = nil
-
-	if list.parent != nil {
-		list.parent.decrementSize(sz)
 	}
-
 }
 
 // Not tested yet
 func (list *CList) removeLinkedRange(startNode *cNode, stopNode *cNode) {
 
+	defer list.check()
 	defer list.mu.Unlock()
 	list.mu.Lock()
 
 	if startNode != nil && stopNode != nil {
-		prev := startNode.prev
-		next := stopNode.next
-
-		if prev != nil {
-			prev.next = next
-		}
-		if next != nil {
-			next.prev = prev
-		}
-
-		x := startNode
-
-		i := 0
-		for {
-			x.val = nil
-			x = x.next
-			i++
+		n := 0
+		for x := startNode; x != nil; x = x.next {
+			n++
 			if x == stopNode {
-				x.val = nil
-				i++
 				break
 			}
 		}
-		list.decrementSize(i)
 
+		list.unlinkNodes(startNode, stopNode, n)
+		wipeRange(startNode, stopNode)
 	}
 
 }
 
+// Log ...Prints the values and length of the list to stdout.
+//
+// Deprecated: use Dump, which writes to any io.Writer, or print the list with the fmt verbs.
 func (list *CList) Log(optionalLabel string) {
 	defer list.mu.Unlock()
 
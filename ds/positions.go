@@ -0,0 +1,116 @@
+package ds
+
+// Optional position index.
+//
+// getNode walks from the nearer end of the list, so reaching a random
+// position of a list holding n elements takes n/4 hops on average. A list
+// built with WithPositionIndex keeps a rankTree (see ranktree.go) over its
+// nodes instead, and every positional lookup - Get, Set, AddVal, AddAllAt,
+// RemoveIndex and the boundaries of a SubList - takes O(log n).
+// The doubly linked nodes stay as they are, so ForEach and the other
+// traversals run exactly as fast as before.
+//
+// Lists built WithIndex get the position index for free, since the hash
+// index already depends on it.
+//
+// The tree costs about 100 bytes per element and makes every insertion and
+// removal O(log n) instead of O(1), so it only pays off for lists that are
+// mostly accessed by position.
+
+// WithPositionIndex ...Turns on the position index for this list and returns the list.
+// When called on a sublist the index is built for the list at the top of the sublist chain.
+//
+// Build the index before sharing the list between goroutines; building it takes O(n·log n).
+func (list *AnyList[T]) WithPositionIndex() *AnyList[T] {
+	defer list.unlock()
+	list.lock("WithPositionIndex")
+
+	root := list.root()
+	if root.order != nil {
+		return list
+	}
+	root.order = newRankTree[*node[T]]()
+	root.walkNodes(func(x *node[T]) {
+		root.order.insertAt(root.order.len(), x)
+	})
+
+	return list
+}
+
+// orderedNode ...Looks up the node at index in this list through the root's position index.
+// Only to be called when the root has one and index is in range.
+func (list *AnyList[T]) orderedNode(index int) *node[T] {
+	root := list.root()
+	base := 0
+	if list != root {
+		base = root.order.rank(list.firstNode)
+	}
+	x, _ := root.order.at(base + index)
+	return x
+}
+
+// orderedCount ...Counts the nodes of a sublist through the root's position index.
+// The second result is false if the boundaries of the sublist are no longer in the root list.
+// Only to be called when the root has one.
+func (list *AnyList[T]) orderedCount() (int, bool) {
+	root := list.root()
+	if list.firstNode == nil {
+		return 0, true
+	}
+	first := root.order.rank(list.firstNode)
+	last := root.order.rank(list.lastNode)
+	// a boundary node was removed behind the sublist's back
+	if first < 0 || last < first {
+		return 0, false
+	}
+	return last - first + 1, true
+}
+
+// WithPositionIndex ...Turns on the position index for this list and returns the list.
+// When called on a sublist the index is built for the list at the top of the sublist chain.
+//
+// Build the index before sharing the list between goroutines; building it takes O(n·log n).
+func (list *List[T]) WithPositionIndex() *List[T] {
+	defer list.unlock()
+	list.lock("WithPositionIndex")
+
+	root := list.root()
+	if root.order != nil {
+		return list
+	}
+	root.order = newRankTree[*lNode[T]]()
+	root.walkNodes(func(x *lNode[T]) {
+		root.order.insertAt(root.order.len(), x)
+	})
+
+	return list
+}
+
+// orderedNode ...Looks up the node at index in this list through the root's position index.
+// Only to be called when the root has one and index is in range.
+func (list *List[T]) orderedNode(index int) *lNode[T] {
+	root := list.root()
+	base := 0
+	if list != root {
+		base = root.order.rank(list.firstNode)
+	}
+	x, _ := root.order.at(base + index)
+	return x
+}
+
+// orderedCount ...Counts the nodes of a sublist through the root's position index.
+// The second result is false if the boundaries of the sublist are no longer in the root list.
+// Only to be called when the root has one.
+func (list *List[T]) orderedCount() (int, bool) {
+	root := list.root()
+	if list.firstNode == nil {
+		return 0, true
+	}
+	first := root.order.rank(list.firstNode)
+	last := root.order.rank(list.lastNode)
+	// a boundary node was removed behind the sublist's back
+	if first < 0 || last < first {
+		return 0, false
+	}
+	return last - first + 1, true
+}
@@ -0,0 +1,321 @@
+package ds
+
+import (
+	"errors"
+)
+
+// Priority queues.
+//
+// A PriorityQueue hands its values out in order of priority, the lowest
+// first or the highest first, and values of equal priority in the order they
+// were pushed. Like a Stack, it keeps its values in the nodes of an AnyList,
+// sorted in the order they come out, so it shares the list's node code and
+// its instrumentation. Pop and Peek take O(1), and so does Remove, through
+// the handle Push returns. Push and Update walk the list from the back to
+// find the place of the value, which takes O(n) at worst but O(1) when values
+// come in order of priority, as deadlines and timestamps mostly do.
+//
+// ListHeap goes the other way: it lets an AnyList be ordered with the
+// container/heap functions, each index it is given being looked up in the
+// list, in O(log n) with WithPositionIndex.
+
+// ErrEmptyQueue - Returned by Pop and Peek when the queue is empty
+var ErrEmptyQueue = errors.New("the queue is empty")
+
+// ErrNotQueued - Returned by Update and Remove for a handle whose value is no longer, or never was, in the queue
+var ErrNotQueued = errors.New("the value is not in the queue")
+
+// PriorityOrder - Which values a PriorityQueue hands out first
+type PriorityOrder int
+
+const (
+	// MinFirst - The lowest priority comes out first
+	MinFirst PriorityOrder = iota
+	// MaxFirst - The highest priority comes out first
+	MaxFirst
+)
+
+// PQHandle - A value pushed on a PriorityQueue, see Push
+type PQHandle[T any] struct {
+	queue    *PriorityQueue[T]
+	val      T
+	priority float64
+	// the node of the value in the queue's list, nil once the value is out of the queue
+	node *node[*PQHandle[T]]
+	// order of the push, breaks ties between equal priorities
+	seq uint64
+}
+
+// Value ...Returns the value pushed
+func (h *PQHandle[T]) Value() T {
+	return h.val
+}
+
+// Priority ...Returns the current priority of the value
+func (h *PQHandle[T]) Priority() float64 {
+	list := h.queue.list
+
+	defer list.unlock()
+	list.lock("Priority")
+	return h.priority
+}
+
+// Queued ...Reports whether the value is still in the queue
+func (h *PQHandle[T]) Queued() bool {
+	list := h.queue.list
+
+	defer list.unlock()
+	list.lock("Queued")
+	return h.node != nil
+}
+
+// PriorityQueue - A thread safe queue that hands out its values in order of priority, see pqueue.go
+type PriorityQueue[T any] struct {
+	// the handles of the queued values, the one that comes out first at the front
+	list  *AnyList[*PQHandle[T]]
+	order PriorityOrder
+	seq   uint64
+}
+
+// NewPriorityQueue ...Creates an empty queue that hands out the lowest or the highest priority first
+func NewPriorityQueue[T any](order PriorityOrder) *PriorityQueue[T] {
+	return &PriorityQueue[T]{list: NewAnyList[*PQHandle[T]](), order: order}
+}
+
+// WithInstrumentation ...Reports the queue's operations, lock timings and traversals to instr, and returns the queue
+func (pq *PriorityQueue[T]) WithInstrumentation(instr Instrumentation) *PriorityQueue[T] {
+	pq.list.WithInstrumentation(instr)
+	return pq
+}
+
+// Order ...Returns whether the queue hands out the lowest or the highest priority first
+func (pq *PriorityQueue[T]) Order() PriorityOrder {
+	return pq.order
+}
+
+// before ...Reports whether a comes out of the queue before b
+func (pq *PriorityQueue[T]) before(a *PQHandle[T], b *PQHandle[T]) bool {
+	if a.priority != b.priority {
+		if pq.order == MaxFirst {
+			return a.priority > b.priority
+		}
+		return a.priority < b.priority
+	}
+	return a.seq < b.seq
+}
+
+// insert ...Links item into the list at its place, walking from the back
+func (pq *PriorityQueue[T]) insert(item *PQHandle[T]) {
+	list := pq.list
+	x := list.lastNode
+	for x != nil && pq.before(item, x.val) {
+		x = x.prev
+	}
+	if x == nil {
+		list.prepend(item)
+		item.node = list.firstNode
+		return
+	}
+	item.node = list.insertAfter(item, x)
+}
+
+// unlink ...Takes item out of the list
+func (pq *PriorityQueue[T]) unlink(item *PQHandle[T]) {
+	pq.list.removeNode(item.node)
+	item.node = nil
+}
+
+// Push ...Queues val with the given priority and returns its handle
+func (pq *PriorityQueue[T]) Push(val T, priority float64) *PQHandle[T] {
+	list := pq.list
+
+	defer list.unlockAndDeliver()
+	list.lock("Push")
+
+	pq.seq++
+	item := &PQHandle[T]{queue: pq, val: val, priority: priority, seq: pq.seq}
+	pq.insert(item)
+	return item
+}
+
+// Pop ...Removes the value that comes first and returns it with its priority, or ErrEmptyQueue
+func (pq *PriorityQueue[T]) Pop() (T, float64, error) {
+	list := pq.list
+
+	defer list.unlockAndDeliver()
+	list.lock("Pop")
+
+	if list.firstNode == nil {
+		var nilVal T
+		return nilVal, 0, ErrEmptyQueue
+	}
+	item := list.firstNode.val
+	pq.unlink(item)
+	return item.val, item.priority, nil
+}
+
+// Peek ...Returns the value that comes first and its priority, without removing it, or ErrEmptyQueue
+func (pq *PriorityQueue[T]) Peek() (T, float64, error) {
+	list := pq.list
+
+	defer list.unlock()
+	list.lock("Peek")
+
+	if list.firstNode == nil {
+		var nilVal T
+		return nilVal, 0, ErrEmptyQueue
+	}
+	item := list.firstNode.val
+	return item.val, item.priority, nil
+}
+
+// Update ...Changes the priority of a queued value. A value whose priority does not change keeps its place among equals.
+func (pq *PriorityQueue[T]) Update(handle *PQHandle[T], priority float64) error {
+	list := pq.list
+
+	defer list.unlockAndDeliver()
+	list.lock("Update")
+
+	if !pq.queued(handle) {
+		return ErrNotQueued
+	}
+	if handle.priority != priority {
+		pq.unlink(handle)
+		handle.priority = priority
+		pq.insert(handle)
+	}
+	return nil
+}
+
+// Remove ...Takes a queued value out of the queue
+func (pq *PriorityQueue[T]) Remove(handle *PQHandle[T]) error {
+	list := pq.list
+
+	defer list.unlockAndDeliver()
+	list.lock("Remove")
+
+	if !pq.queued(handle) {
+		return ErrNotQueued
+	}
+	pq.unlink(handle)
+	return nil
+}
+
+// queued ...Reports whether handle belongs to a value in this queue
+func (pq *PriorityQueue[T]) queued(handle *PQHandle[T]) bool {
+	return handle != nil && handle.queue == pq && handle.node != nil
+}
+
+// Count ...Returns the number of values queued
+func (pq *PriorityQueue[T]) Count() int {
+	return pq.list.Count()
+}
+
+// IsEmpty ...Reports whether the queue is empty
+func (pq *PriorityQueue[T]) IsEmpty() bool {
+	return pq.Count() == 0
+}
+
+// Clear ...Removes every value from the queue. Their handles are no longer queued.
+func (pq *PriorityQueue[T]) Clear() {
+	list := pq.list
+
+	defer list.unlockAndDeliver()
+	list.lock("Clear")
+
+	for x := list.firstNode; x != nil; x = x.next {
+		x.val.node = nil
+	}
+	list.clear()
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// ListHeap - Implements heap.Interface over an AnyList, so the container/heap functions can order the list
+// in place: heap.Init(h), heap.Push(h, val), heap.Pop(h).(T) and so on. Every method takes the list's lock,
+// so other goroutines may use the list meanwhile, but a heap operation as a whole is not atomic.
+// Swap exchanges values, which subscribers see as two EventSet and undo as one step per swap.
+type ListHeap[T any] struct {
+	list *AnyList[T]
+	less func(a, b T) bool
+}
+
+// NewListHeap ...Creates a ListHeap over list, with less ordering its values: the least value is at index 0
+func NewListHeap[T any](list *AnyList[T], less func(a, b T) bool) *ListHeap[T] {
+	return &ListHeap[T]{list: list, less: less}
+}
+
+// List ...Returns the list the heap orders
+func (h *ListHeap[T]) List() *AnyList[T] {
+	return h.list
+}
+
+// Len ...Implements sort.Interface
+func (h *ListHeap[T]) Len() int {
+	return h.list.Count()
+}
+
+// Less ...Implements sort.Interface
+func (h *ListHeap[T]) Less(i, j int) bool {
+	list := h.list
+
+	defer list.unlock()
+	list.lock("Less")
+
+	a, err := list.getNode(i)
+	if err != nil {
+		panic(err)
+	}
+	b, err := list.getNode(j)
+	if err != nil {
+		panic(err)
+	}
+	return h.less(a.val, b.val)
+}
+
+// Swap ...Implements sort.Interface
+func (h *ListHeap[T]) Swap(i, j int) {
+	list := h.list
+
+	defer list.unlockAndDeliver()
+	list.lock("Swap")
+
+	a, err := list.getNode(i)
+	if err != nil {
+		panic(err)
+	}
+	b, err := list.getNode(j)
+	if err != nil {
+		panic(err)
+	}
+	if a == b {
+		return
+	}
+	oldA, oldB := a.val, b.val
+	a.val, b.val = oldB, oldA
+	list.changed(a, oldA)
+	list.changed(b, oldB)
+}
+
+// Push ...Implements heap.Interface: appends x, which must be a T
+func (h *ListHeap[T]) Push(x any) {
+	h.list.Add(x.(T))
+}
+
+// Pop ...Implements heap.Interface: removes and returns the last value
+func (h *ListHeap[T]) Pop() any {
+	list := h.list
+
+	defer list.unlockAndDeliver()
+	list.lock("Pop")
+
+	last, err := list.getNode(list.count() - 1)
+	if err != nil {
+		panic(err)
+	}
+	val := last.val
+	list.removeNode(last)
+	return val
+}
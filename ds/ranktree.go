@@ -0,0 +1,202 @@
+package ds
+
+// rankTree - An order-statistics tree over the nodes of a list.
+//
+// It is an implicit treap: elements are not ordered by key but by their
+// position, and every tree node carries the size of its subtree. That is
+// enough to answer "what is the position of this list node?" (rank) and
+// "which list node sits at this position?" (at) in O(log n) expected time,
+// and to insert or delete a list node in O(log n) expected time as well.
+//
+// Tree nodes keep a parent pointer so a rank can be computed by walking up
+// from the list node, which is found through the lookup map.
+type rankTree[N comparable] struct {
+	root   *rankNode[N]
+	lookup map[N]*rankNode[N]
+	// xorshift state used to draw treap priorities
+	seed uint32
+}
+
+// rankNode - A node of the rankTree
+type rankNode[N comparable] struct {
+	left     *rankNode[N]
+	right    *rankNode[N]
+	parent   *rankNode[N]
+	priority uint32
+	size     int
+	elem     N
+}
+
+func newRankTree[N comparable]() *rankTree[N] {
+	return &rankTree[N]{
+		lookup: make(map[N]*rankNode[N]),
+		seed:   2463534242,
+	}
+}
+
+func (tree *rankTree[N]) nextPriority() uint32 {
+	x := tree.seed
+	x ^= x << 13
+	x ^= x >> 17
+	x ^= x << 5
+	tree.seed = x
+	return x
+}
+
+func subtreeSize[N comparable](n *rankNode[N]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// update ...Recomputes the subtree size of n and re-parents its children
+func (n *rankNode[N]) update() {
+	n.size = 1 + subtreeSize(n.left) + subtreeSize(n.right)
+	if n.left != nil {
+		n.left.parent = n
+	}
+	if n.right != nil {
+		n.right.parent = n
+	}
+}
+
+// splitRank ...Splits the tree rooted at n into the first k elements and the rest
+func splitRank[N comparable](n *rankNode[N], k int) (*rankNode[N], *rankNode[N]) {
+	if n == nil {
+		return nil, nil
+	}
+	var l, r *rankNode[N]
+	if subtreeSize(n.left) >= k {
+		l, n.left = splitRank(n.left, k)
+		n.update()
+		r = n
+	} else {
+		n.right, r = splitRank(n.right, k-subtreeSize(n.left)-1)
+		n.update()
+		l = n
+	}
+	if l != nil {
+		l.parent = nil
+	}
+	if r != nil {
+		r.parent = nil
+	}
+	return l, r
+}
+
+// mergeRank ...Joins two trees, every element of a coming before every element of b
+func mergeRank[N comparable](a *rankNode[N], b *rankNode[N]) *rankNode[N] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a.right = mergeRank(a.right, b)
+		a.update()
+		return a
+	}
+	b.left = mergeRank(a, b.left)
+	b.update()
+	return b
+}
+
+func (tree *rankTree[N]) len() int {
+	return subtreeSize(tree.root)
+}
+
+func (tree *rankTree[N]) contains(elem N) bool {
+	_, ok := tree.lookup[elem]
+	return ok
+}
+
+// insertAt ...Places elem at position pos, shifting the elements from pos onward one place up
+func (tree *rankTree[N]) insertAt(pos int, elem N) {
+	n := &rankNode[N]{priority: tree.nextPriority(), size: 1, elem: elem}
+	tree.lookup[elem] = n
+
+	l, r := splitRank(tree.root, pos)
+	tree.root = mergeRank(mergeRank(l, n), r)
+	tree.root.parent = nil
+}
+
+// insertAfter ...Places elem right after prev. A zero prev means elem becomes the first element.
+func (tree *rankTree[N]) insertAfter(prev N, elem N) {
+	var zero N
+	pos := 0
+	if prev != zero {
+		pos = tree.rank(prev) + 1
+	}
+	tree.insertAt(pos, elem)
+}
+
+// remove ...Deletes elem from the tree. Returns false if it was not there.
+func (tree *rankTree[N]) remove(elem N) bool {
+	n, ok := tree.lookup[elem]
+	if !ok {
+		return false
+	}
+	delete(tree.lookup, elem)
+
+	m := mergeRank(n.left, n.right)
+	p := n.parent
+	if m != nil {
+		m.parent = p
+	}
+	if p == nil {
+		tree.root = m
+	} else if p.left == n {
+		p.left = m
+	} else {
+		p.right = m
+	}
+	for ; p != nil; p = p.parent {
+		p.size--
+	}
+
+	n.left, n.right, n.parent = nil, nil, nil
+	return true
+}
+
+// rank ...Returns the position of elem, or -1 if it is not in the tree
+func (tree *rankTree[N]) rank(elem N) int {
+	n, ok := tree.lookup[elem]
+	if !ok {
+		return -1
+	}
+	r := subtreeSize(n.left)
+	for ; n.parent != nil; n = n.parent {
+		if n == n.parent.right {
+			r += subtreeSize(n.parent.left) + 1
+		}
+	}
+	return r
+}
+
+// at ...Returns the element at position pos. The second result is false if pos is out of range.
+func (tree *rankTree[N]) at(pos int) (N, bool) {
+	var zero N
+	if pos < 0 || pos >= tree.len() {
+		return zero, false
+	}
+	n := tree.root
+	for n != nil {
+		ls := subtreeSize(n.left)
+		if pos < ls {
+			n = n.left
+		} else if pos == ls {
+			return n.elem, true
+		} else {
+			pos -= ls + 1
+			n = n.right
+		}
+	}
+	return zero, false
+}
+
+func (tree *rankTree[N]) reset() {
+	tree.root = nil
+	tree.lookup = make(map[N]*rankNode[N])
+}
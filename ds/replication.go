@@ -0,0 +1,343 @@
+package ds
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Replication.
+//
+// WithOpLog makes a list number every change made to it, including changes
+// made through its sublists and by Undo and Redo, and keep the latest of them
+// in an OpLog. A change is an Op: the Event subscribers see (see events.go)
+// and its sequence number, starting at 1. A committed transaction is logged
+// once it commits; one that rolls back is not logged at all.
+//
+// A Replica keeps an AnyList identical to the logged list by applying the ops
+// in order. It catches up from a Snapshot, the values of the list and the
+// sequence number of the latest op they include, followed by the ops after
+// it. Stream and Follow carry snapshots and ops as JSON lines over any
+// io.Writer and io.Reader; Serve and Sync add a handshake for a connection, in
+// which the replica says how far it got so that only the log tail is sent.
+// Every OpLog has a random ID, so a replica that followed another log, e.g.
+// the one of a primary before it restarted, is sent a snapshot.
+
+// ErrOpLogTruncated - Returned when the log no longer holds the ops asked for
+var ErrOpLogTruncated = errors.New("the operation log no longer holds the ops asked for")
+
+// Op - A change made to a list, numbered by its OpLog
+type Op[T any] struct {
+	Seq uint64 `json:"seq"`
+	Event[T]
+}
+
+// Snapshot - The values of a list, and the sequence number of the latest op they include
+type Snapshot[T any] struct {
+	// ID of the OpLog the sequence number belongs to
+	Log    string `json:"log"`
+	Seq    uint64 `json:"seq"`
+	Values []T    `json:"values"`
+}
+
+// OpLog - Numbers the changes made to a list and keeps the latest of them, see WithOpLog
+type OpLog[T any] struct {
+	id   string
+	keep int
+	// copies the values of the list and reads seq, with the list locked
+	read func() ([]T, uint64)
+
+	mu sync.Mutex
+	// the latest ops, oldest first
+	ops []Op[T]
+	seq uint64
+	// closed and replaced whenever an op is added
+	appended chan struct{}
+}
+
+func newOpLog[T any](keep int) *OpLog[T] {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return &OpLog[T]{id: hex.EncodeToString(id), keep: keep, appended: make(chan struct{})}
+}
+
+// append ...Numbers and logs an event. Only called by the list, with the list locked.
+func (log *OpLog[T]) append(ev Event[T]) {
+	ev.Values = append([]T(nil), ev.Values...)
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	log.seq++
+	log.ops = append(log.ops, Op[T]{Seq: log.seq, Event: ev})
+	if log.keep > 0 && len(log.ops) > log.keep {
+		log.ops = log.ops[len(log.ops)-log.keep:]
+	}
+	close(log.appended)
+	log.appended = make(chan struct{})
+}
+
+// ID ...Returns the random ID of the log
+func (log *OpLog[T]) ID() string {
+	return log.id
+}
+
+// Seq ...Returns the sequence number of the latest op, 0 if there is none yet
+func (log *OpLog[T]) Seq() uint64 {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	return log.seq
+}
+
+// Since ...Returns the ops after seq, oldest first, or ErrOpLogTruncated if the log no longer holds all of them.
+// The ops returned must not be changed.
+func (log *OpLog[T]) Since(seq uint64) ([]Op[T], error) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	first := log.seq - uint64(len(log.ops)) + 1
+	if seq > log.seq || seq+1 < first {
+		return nil, ErrOpLogTruncated
+	}
+	n := len(log.ops)
+	return log.ops[int(seq+1-first):n:n], nil
+}
+
+// Snapshot ...Returns the values of the list and the sequence number of the latest op they include
+func (log *OpLog[T]) Snapshot() Snapshot[T] {
+	values, seq := log.read()
+	return Snapshot[T]{Log: log.id, Seq: seq, Values: values}
+}
+
+// Wait ...Waits until the log holds ops after seq, or ctx is done
+func (log *OpLog[T]) Wait(ctx context.Context, seq uint64) error {
+	log.mu.Lock()
+	if log.seq > seq {
+		log.mu.Unlock()
+		return nil
+	}
+	appended := log.appended
+	log.mu.Unlock()
+
+	select {
+	case <-appended:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// replicationFrame - One line of a replication stream: a snapshot or an op
+type replicationFrame[T any] struct {
+	Snapshot *Snapshot[T] `json:"snapshot,omitempty"`
+	Op       *Op[T]       `json:"op,omitempty"`
+}
+
+// replicaHello - What a replica tells Serve: the log it followed and the latest op it applied
+type replicaHello struct {
+	Log string `json:"log"`
+	Seq uint64 `json:"seq"`
+}
+
+// Stream ...Writes the ops after op seq of log logID to w as JSON lines, then the ops that follow as they come,
+// until ctx is done or a write fails. A snapshot comes first when the ops are not in the log.
+func (log *OpLog[T]) Stream(ctx context.Context, w io.Writer, logID string, seq uint64) error {
+	enc := json.NewEncoder(w)
+	snapshot := logID != log.id
+	for {
+		if snapshot {
+			snap := log.Snapshot()
+			if err := enc.Encode(replicationFrame[T]{Snapshot: &snap}); err != nil {
+				return err
+			}
+			seq = snap.Seq
+		}
+
+		ops, err := log.Since(seq)
+		if err != nil {
+			snapshot = true
+			continue
+		}
+		snapshot = false
+		for i := range ops {
+			if err := enc.Encode(replicationFrame[T]{Op: &ops[i]}); err != nil {
+				return err
+			}
+			seq = ops[i].Seq
+		}
+
+		if err := log.Wait(ctx, seq); err != nil {
+			return err
+		}
+	}
+}
+
+// Serve ...Reads the handshake a replica's Sync sends over conn, then streams to it until ctx is done or conn fails
+func (log *OpLog[T]) Serve(ctx context.Context, conn io.ReadWriter) error {
+	var hello replicaHello
+	if err := json.NewDecoder(conn).Decode(&hello); err != nil {
+		return fmt.Errorf("reading the replica handshake: %w", err)
+	}
+	return log.Stream(ctx, conn, hello.Log, hello.Seq)
+}
+
+// Replica - Keeps an AnyList identical to a list with an OpLog, by applying its ops in order.
+// The list must not be changed in any other way, but it can be read, subscribed to, and so on.
+type Replica[T any] struct {
+	list *AnyList[T]
+
+	mu    sync.Mutex
+	logID string
+	seq   uint64
+}
+
+// NewReplica ...Creates a Replica that keeps list up to date. The list should be empty and not a sublist.
+func NewReplica[T any](list *AnyList[T]) *Replica[T] {
+	return &Replica[T]{list: list}
+}
+
+// List ...Returns the list the replica keeps up to date
+func (r *Replica[T]) List() *AnyList[T] {
+	return r.list
+}
+
+// Position ...Returns the ID of the log followed and the sequence number of the latest op applied
+func (r *Replica[T]) Position() (logID string, seq uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.logID, r.seq
+}
+
+// Restore ...Replaces the values of the list with those of a snapshot
+func (r *Replica[T]) Restore(snap Snapshot[T]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.list.restore(snap.Values)
+	r.logID, r.seq = snap.Log, snap.Seq
+}
+
+// Apply ...Applies an op. Ops already applied are skipped; an op that skips some is an error and is not applied.
+func (r *Replica[T]) Apply(op Op[T]) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if op.Seq <= r.seq {
+		return nil
+	}
+	if op.Seq != r.seq+1 {
+		return fmt.Errorf("op %d does not follow op %d, ops are missing", op.Seq, r.seq)
+	}
+	if err := r.list.apply(op.Event); err != nil {
+		return err
+	}
+	r.seq = op.Seq
+	return nil
+}
+
+// Follow ...Restores the snapshots and applies the ops read from rd, as Stream writes them, until rd ends or fails
+func (r *Replica[T]) Follow(rd io.Reader) error {
+	dec := json.NewDecoder(rd)
+	for {
+		var frame replicationFrame[T]
+		if err := dec.Decode(&frame); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		switch {
+		case frame.Snapshot != nil:
+			r.Restore(*frame.Snapshot)
+		case frame.Op != nil:
+			if err := r.Apply(*frame.Op); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Sync ...Tells the OpLog serving conn how far the replica got, then follows what it streams. Closing conn stops it.
+func (r *Replica[T]) Sync(conn io.ReadWriter) error {
+	logID, seq := r.Position()
+	if err := json.NewEncoder(conn).Encode(replicaHello{Log: logID, Seq: seq}); err != nil {
+		return err
+	}
+	return r.Follow(conn)
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// WithOpLog ...Starts numbering the changes made to the list, keeping the latest keep of them, and returns the list.
+// A keep of 0 or less keeps them all. When called on a sublist the log is kept by the list at the top of the sublist chain.
+func (list *AnyList[T]) WithOpLog(keep int) *AnyList[T] {
+	root := list.root()
+
+	defer root.unlock()
+	root.lock("WithOpLog")
+
+	log := newOpLog[T](keep)
+	log.read = func() ([]T, uint64) {
+		defer root.unlock()
+		root.lock("Snapshot")
+		return root.values(), log.Seq()
+	}
+	root.oplog = log
+	return list
+}
+
+// OpLog ...Returns the operation log of the list, or nil if it has none, see WithOpLog
+func (list *AnyList[T]) OpLog() *OpLog[T] {
+	return list.root().oplog
+}
+
+// apply ...Replays an event received by a replica
+func (list *AnyList[T]) apply(ev Event[T]) error {
+	defer list.unlockAndDeliver()
+	list.lock("Apply")
+
+	return list.replay(ev)
+}
+
+// restore ...Replaces the values of the list with those of a snapshot
+func (list *AnyList[T]) restore(values []T) {
+	defer list.unlockAndDeliver()
+	list.lock("Restore")
+
+	list.clear()
+	list.addArray(values)
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// WithOpLog ...Starts numbering the changes made to the list, keeping the latest keep of them, and returns the list.
+// A keep of 0 or less keeps them all. When called on a sublist the log is kept by the list at the top of the sublist chain.
+func (list *List[T]) WithOpLog(keep int) *List[T] {
+	root := list.root()
+
+	defer root.unlock()
+	root.lock("WithOpLog")
+
+	log := newOpLog[T](keep)
+	log.read = func() ([]T, uint64) {
+		defer root.unlock()
+		root.lock("Snapshot")
+		return root.values(), log.Seq()
+	}
+	root.oplog = log
+	return list
+}
+
+// OpLog ...Returns the operation log of the list, or nil if it has none, see WithOpLog
+func (list *List[T]) OpLog() *OpLog[T] {
+	return list.root().oplog
+}
@@ -0,0 +1,308 @@
+package ds
+
+import (
+	"errors"
+
+	"github.com/gbenroscience/linkedlist/utils"
+)
+
+// Random sampling.
+//
+// Shuffle puts the nodes of a list in a random order by relinking them, so no
+// value is copied, and Sample picks k values in a single walk. RandomElement
+// walks to a random position, which is O(log n) on a list built
+// WithPositionIndex. A Reservoir keeps a sample of a stream of values, e.g.
+// the values a ForEach visits, without knowing its length up front.
+//
+// All of them draw from a utils.RandomLife, so a sample can be replayed from
+// the seed it was drawn with.
+
+// errEmptySample - Returned by RandomElement on an empty list
+var errEmptySample = errors.New("the list is empty")
+
+// Reservoir - Keeps a uniformly random sample of at most k of the values offered to it (Algorithm R).
+// It is not safe for concurrent use.
+type Reservoir[T any] struct {
+	rnd    *utils.RandomLife
+	k      int
+	seen   int
+	values []T
+}
+
+// NewReservoir ...Creates a Reservoir that keeps k values
+func NewReservoir[T any](rnd *utils.RandomLife, k int) *Reservoir[T] {
+	return &Reservoir[T]{rnd: rnd, k: k, values: make([]T, 0, max(k, 0))}
+}
+
+// Offer ...Offers val to the sample, and returns true so that it can be passed to ForEach as it is:
+//
+//	list.ForEach(reservoir.Offer)
+func (r *Reservoir[T]) Offer(val T) bool {
+	r.seen++
+	if len(r.values) < r.k {
+		r.values = append(r.values, val)
+	} else if j := r.rnd.NextInt(r.seen); j < r.k {
+		r.values[j] = val
+	}
+	return true
+}
+
+// Values ...Returns the sample: every value offered so far if there were no more than k of them
+func (r *Reservoir[T]) Values() []T {
+	return append([]T(nil), r.values...)
+}
+
+// Seen ...Returns the number of values offered so far
+func (r *Reservoir[T]) Seen() int {
+	return r.seen
+}
+
+// selectSample ...Decides whether the next of remaining values is picked, given that wanted of them are still to be picked (Algorithm S)
+func selectSample(rnd *utils.RandomLife, wanted int, remaining int) bool {
+	return rnd.NextInt(remaining) < wanted
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// Shuffle ...Puts the values of the list in a random order (Fisher–Yates) by relinking its nodes.
+// Subscribers see the values removed and inserted again; sublists taken from the list are no longer valid.
+func (list *AnyList[T]) Shuffle(rnd *utils.RandomLife) {
+	defer list.unlockAndDeliver()
+	list.lock("Shuffle")
+	list.shuffle(rnd)
+}
+
+func (list *AnyList[T]) shuffle(rnd *utils.RandomLife) {
+	first, last := list.firstNode, list.lastNode
+	if first == last {
+		return
+	}
+
+	var nodes []*node[T]
+	list.walkNodes(func(x *node[T]) {
+		nodes = append(nodes, x)
+	})
+	rnd.Shuffle(len(nodes), func(i, j int) {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	})
+
+	list.unlinkingRange(first, last)
+	prev, next := first.prev, last.next
+	for _, x := range nodes {
+		x.prev = prev
+		if prev != nil {
+			prev.next = x
+		}
+		prev = x
+	}
+	prev.next = next
+	if next != nil {
+		next.prev = prev
+	}
+
+	// the ends of this list, and of the lists above it that shared them, moved
+	for l := list; l != nil; l = l.parent {
+		if l.firstNode == first {
+			l.firstNode = nodes[0]
+		}
+		if l.lastNode == last {
+			l.lastNode = nodes[len(nodes)-1]
+		}
+	}
+	list.linkedRange(nodes[0], nodes[len(nodes)-1])
+}
+
+// Sample ...Returns k values picked at random, each value at most once, in the order they appear in the list.
+// It returns all the values if the list holds no more than k.
+func (list *AnyList[T]) Sample(rnd *utils.RandomLife, k int) []T {
+	defer list.unlock()
+	list.lock("Sample")
+
+	remaining := list.count()
+	var values []T
+	list.walkNodes(func(x *node[T]) {
+		if selectSample(rnd, k-len(values), remaining) {
+			values = append(values, x.val)
+		}
+		remaining--
+	})
+	return values
+}
+
+// RandomElement ...Returns a value picked at random, or an error if the list is empty
+func (list *AnyList[T]) RandomElement(rnd *utils.RandomLife) (T, error) {
+	defer list.unlock()
+	list.lock("RandomElement")
+
+	if sz := list.count(); sz > 0 {
+		return list.get(rnd.NextInt(sz))
+	}
+	var zero T
+	return zero, errEmptySample
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// Shuffle ...Puts the values of the list in a random order (Fisher–Yates) by relinking its nodes.
+// Subscribers see the values removed and inserted again; sublists taken from the list are no longer valid.
+func (list *List[T]) Shuffle(rnd *utils.RandomLife) {
+	defer list.unlockAndDeliver()
+	list.lock("Shuffle")
+	list.shuffle(rnd)
+}
+
+func (list *List[T]) shuffle(rnd *utils.RandomLife) {
+	first, last := list.firstNode, list.lastNode
+	if first == last {
+		return
+	}
+
+	var nodes []*lNode[T]
+	list.walkNodes(func(x *lNode[T]) {
+		nodes = append(nodes, x)
+	})
+	rnd.Shuffle(len(nodes), func(i, j int) {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	})
+
+	list.unlinkingRange(first, last)
+	prev, next := first.prev, last.next
+	for _, x := range nodes {
+		x.prev = prev
+		if prev != nil {
+			prev.next = x
+		}
+		prev = x
+	}
+	prev.next = next
+	if next != nil {
+		next.prev = prev
+	}
+
+	// the ends of this list, and of the lists above it that shared them, moved
+	for l := list; l != nil; l = l.parent {
+		if l.firstNode == first {
+			l.firstNode = nodes[0]
+		}
+		if l.lastNode == last {
+			l.lastNode = nodes[len(nodes)-1]
+		}
+	}
+	list.linkedRange(nodes[0], nodes[len(nodes)-1])
+}
+
+// Sample ...Returns k values picked at random, each value at most once, in the order they appear in the list.
+// It returns all the values if the list holds no more than k.
+func (list *List[T]) Sample(rnd *utils.RandomLife, k int) []T {
+	defer list.unlock()
+	list.lock("Sample")
+
+	remaining := list.count()
+	var values []T
+	list.walkNodes(func(x *lNode[T]) {
+		if selectSample(rnd, k-len(values), remaining) {
+			values = append(values, x.val)
+		}
+		remaining--
+	})
+	return values
+}
+
+// RandomElement ...Returns a value picked at random, or an error if the list is empty
+func (list *List[T]) RandomElement(rnd *utils.RandomLife) (T, error) {
+	defer list.unlock()
+	list.lock("RandomElement")
+
+	if sz := list.count(); sz > 0 {
+		return list.get(rnd.NextInt(sz))
+	}
+	var zero T
+	return zero, errEmptySample
+}
+
+// ---------------------------------------------------------------------------
+// CList
+// ---------------------------------------------------------------------------
+
+// Shuffle ...Puts the values of the list in a random order (Fisher–Yates) by relinking its nodes.
+// Sublists taken from the list are no longer valid.
+func (list *CList) Shuffle(rnd *utils.RandomLife) {
+	defer list.check()
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	first, last := list.firstNode, list.lastNode
+	if first == last {
+		return
+	}
+
+	var nodes []*cNode
+	for x := first; x != nil; x = x.next {
+		nodes = append(nodes, x)
+		if x == last {
+			break
+		}
+	}
+	rnd.Shuffle(len(nodes), func(i, j int) {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	})
+
+	prev, next := first.prev, last.next
+	for _, x := range nodes {
+		x.prev = prev
+		if prev != nil {
+			prev.next = x
+		}
+		prev = x
+	}
+	prev.next = next
+	if next != nil {
+		next.prev = prev
+	}
+
+	// the ends of this list, and of the lists above it that shared them, moved
+	for l := list; l != nil; l = l.parent {
+		if l.firstNode == first {
+			l.firstNode = nodes[0]
+		}
+		if l.lastNode == last {
+			l.lastNode = nodes[len(nodes)-1]
+		}
+	}
+}
+
+// Sample ...Returns k values picked at random, each value at most once, in the order they appear in the list.
+// It returns all the values if the list holds no more than k.
+func (list *CList) Sample(rnd *utils.RandomLife, k int) []interface{} {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	remaining := list.count()
+	var values []interface{}
+	for x := list.firstNode; x != nil && remaining > 0; x = x.next {
+		if selectSample(rnd, k-len(values), remaining) {
+			values = append(values, x.val)
+		}
+		remaining--
+	}
+	return values
+}
+
+// RandomElement ...Returns a value picked at random, or an error if the list is empty
+func (list *CList) RandomElement(rnd *utils.RandomLife) (interface{}, error) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	if sz := list.count(); sz > 0 {
+		x, err := list.getNode(rnd.NextInt(sz))
+		if err != nil {
+			return nil, err
+		}
+		return x.val, nil
+	}
+	return nil, errEmptySample
+}
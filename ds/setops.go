@@ -0,0 +1,357 @@
+package ds
+
+// Set algebra over lists.
+//
+// The List[T comparable] versions use a Go map to test membership, so each
+// operation runs in O(n+m) instead of the O(n·m) of calling Remove or
+// Contains in a loop.
+// The AnyList[T any] versions need a user supplied hash function to do the
+// same; values that hash alike are then told apart with the list's Equals
+// function, so the hash only has to be consistent with Equals, not perfect.
+//
+// Every operation preserves the original order of the receiver. Operations
+// that return a new list treat their result as a set: each value appears at
+// most once, in the position of its first occurrence.
+// RetainAll and RemoveAll modify the receiver in place and keep duplicates,
+// just like RemoveAll always has.
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// valueSet ...Builds a membership set from a list's values.
+func valueSet[T comparable](values []T) map[T]struct{} {
+	set := make(map[T]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// distinctInto ...Appends to dst every value of src that is not yet in seen, in order
+func distinctInto[T comparable](dst *List[T], src []T, seen map[T]struct{}, keep func(val T) bool) {
+	for _, v := range src {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		if keep(v) {
+			dst.append(v)
+		}
+	}
+}
+
+// Distinct ...Returns a new list holding the first occurrence of every value in this list, in order.
+func (list *List[T]) Distinct() *List[T] {
+	values := list.ToArray()
+
+	result := NewList[T]()
+	distinctInto(result, values, make(map[T]struct{}, len(values)), func(val T) bool {
+		return true
+	})
+	return result
+}
+
+// Union ...Returns a new list holding the distinct values of this list followed by
+// the distinct values of lst that are not in this list.
+func (list *List[T]) Union(lst *List[T]) *List[T] {
+	other := lst.ToArray()
+	values := list.ToArray()
+
+	result := NewList[T]()
+	seen := make(map[T]struct{}, len(values)+len(other))
+	all := func(val T) bool {
+		return true
+	}
+	distinctInto(result, values, seen, all)
+	distinctInto(result, other, seen, all)
+	return result
+}
+
+// Intersect ...Returns a new list holding the distinct values of this list that are also in lst.
+func (list *List[T]) Intersect(lst *List[T]) *List[T] {
+	other := valueSet(lst.ToArray())
+	values := list.ToArray()
+
+	result := NewList[T]()
+	distinctInto(result, values, make(map[T]struct{}, len(values)), func(val T) bool {
+		_, ok := other[val]
+		return ok
+	})
+	return result
+}
+
+// Difference ...Returns a new list holding the distinct values of this list that are not in lst.
+func (list *List[T]) Difference(lst *List[T]) *List[T] {
+	other := valueSet(lst.ToArray())
+	values := list.ToArray()
+
+	result := NewList[T]()
+	distinctInto(result, values, make(map[T]struct{}, len(values)), func(val T) bool {
+		_, ok := other[val]
+		return !ok
+	})
+	return result
+}
+
+// SymmetricDifference ...Returns a new list holding the distinct values found in exactly one of
+// the two lists; those of this list come first, followed by those of lst.
+func (list *List[T]) SymmetricDifference(lst *List[T]) *List[T] {
+	otherValues := lst.ToArray()
+	values := list.ToArray()
+	mine := valueSet(values)
+	other := valueSet(otherValues)
+
+	result := NewList[T]()
+	distinctInto(result, values, make(map[T]struct{}, len(values)), func(val T) bool {
+		_, ok := other[val]
+		return !ok
+	})
+	distinctInto(result, otherValues, make(map[T]struct{}, len(otherValues)), func(val T) bool {
+		_, ok := mine[val]
+		return !ok
+	})
+	return result
+}
+
+// RetainAll ...Removes from this list every element that is not contained in lst.
+// Returns true if the list changed.
+func (list *List[T]) RetainAll(lst *List[T]) bool {
+	other := valueSet(lst.ToArray())
+
+	defer list.unlockAndDeliver()
+	list.lock("RetainAll")
+
+	return list.removeIf(func(val T) bool {
+		_, ok := other[val]
+		return !ok
+	})
+}
+
+// removeIf ...Unlinks every node whose value satisfies the predicate and reports whether any was removed.
+func (list *List[T]) removeIf(predicate func(val T) bool) bool {
+
+	removed := false
+	x := list.firstNode
+	last := list.lastNode
+	sz := list.count()
+
+	for i := 0; i < sz && x != nil; i++ {
+		next := x.next
+		if predicate(x.val) {
+			list.removeNode(x)
+			removed = true
+		}
+		if x == last {
+			break
+		}
+		x = next
+	}
+
+	return removed
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// hashedSet - A set of values of any type, bucketed by a user supplied hash and resolved with an equality function
+type hashedSet[T any] struct {
+	hash    func(val T) uint64
+	equals  func(val1 T, val2 T) bool
+	buckets map[uint64][]hashedEntry[T]
+}
+
+type hashedEntry[T any] struct {
+	val   T
+	count int
+}
+
+func newHashedSet[T any](hash func(val T) uint64, equals func(val1 T, val2 T) bool, capacity int) *hashedSet[T] {
+	return &hashedSet[T]{
+		hash:    hash,
+		equals:  equals,
+		buckets: make(map[uint64][]hashedEntry[T], capacity),
+	}
+}
+
+func newHashedSetOf[T any](hash func(val T) uint64, equals func(val1 T, val2 T) bool, values []T) *hashedSet[T] {
+	set := newHashedSet(hash, equals, len(values))
+	for _, v := range values {
+		set.add(v)
+	}
+	return set
+}
+
+// add ...Records one more occurrence of val. Returns true if val was not in the set before.
+func (set *hashedSet[T]) add(val T) bool {
+	h := set.hash(val)
+	bucket := set.buckets[h]
+	for i := range bucket {
+		if set.equals(bucket[i].val, val) {
+			bucket[i].count++
+			return false
+		}
+	}
+	set.buckets[h] = append(bucket, hashedEntry[T]{val: val, count: 1})
+	return true
+}
+
+func (set *hashedSet[T]) contains(val T) bool {
+	for _, e := range set.buckets[set.hash(val)] {
+		if set.equals(e.val, val) {
+			return true
+		}
+	}
+	return false
+}
+
+// take ...Consumes one occurrence of val. Returns false if none is left.
+func (set *hashedSet[T]) take(val T) bool {
+	bucket := set.buckets[set.hash(val)]
+	for i := range bucket {
+		if set.equals(bucket[i].val, val) {
+			if bucket[i].count == 0 {
+				return false
+			}
+			bucket[i].count--
+			return true
+		}
+	}
+	return false
+}
+
+// newAnyListLike ...Creates an empty list that shares the Equals function of list
+func (list *AnyList[T]) newAnyListLike() *AnyList[T] {
+	result := NewAnyList[T]()
+	result.Equals = list.Equals
+	return result
+}
+
+// distinctAnyInto ...Appends to dst every value of src that is not yet in seen, in order
+func distinctAnyInto[T any](dst *AnyList[T], src []T, seen *hashedSet[T], keep func(val T) bool) {
+	for _, v := range src {
+		if !seen.add(v) {
+			continue
+		}
+		if keep(v) {
+			dst.append(v)
+		}
+	}
+}
+
+// DistinctFunc ...Returns a new list holding the first occurrence of every value in this list, in order.
+// hash must return equal hashes for values that the list's Equals function considers equal.
+func (list *AnyList[T]) DistinctFunc(hash func(val T) uint64) *AnyList[T] {
+	values := list.ToArray()
+
+	result := list.newAnyListLike()
+	distinctAnyInto(result, values, newHashedSet(hash, list.equals, len(values)), func(val T) bool {
+		return true
+	})
+	return result
+}
+
+// UnionFunc ...Returns a new list holding the distinct values of this list followed by
+// the distinct values of lst that are not in this list.
+func (list *AnyList[T]) UnionFunc(lst *AnyList[T], hash func(val T) uint64) *AnyList[T] {
+	other := lst.ToArray()
+	values := list.ToArray()
+
+	result := list.newAnyListLike()
+	seen := newHashedSet(hash, list.equals, len(values)+len(other))
+	all := func(val T) bool {
+		return true
+	}
+	distinctAnyInto(result, values, seen, all)
+	distinctAnyInto(result, other, seen, all)
+	return result
+}
+
+// IntersectFunc ...Returns a new list holding the distinct values of this list that are also in lst.
+func (list *AnyList[T]) IntersectFunc(lst *AnyList[T], hash func(val T) uint64) *AnyList[T] {
+	other := newHashedSetOf(hash, list.equals, lst.ToArray())
+	values := list.ToArray()
+
+	result := list.newAnyListLike()
+	distinctAnyInto(result, values, newHashedSet(hash, list.equals, len(values)), other.contains)
+	return result
+}
+
+// DifferenceFunc ...Returns a new list holding the distinct values of this list that are not in lst.
+func (list *AnyList[T]) DifferenceFunc(lst *AnyList[T], hash func(val T) uint64) *AnyList[T] {
+	other := newHashedSetOf(hash, list.equals, lst.ToArray())
+	values := list.ToArray()
+
+	result := list.newAnyListLike()
+	distinctAnyInto(result, values, newHashedSet(hash, list.equals, len(values)), func(val T) bool {
+		return !other.contains(val)
+	})
+	return result
+}
+
+// SymmetricDifferenceFunc ...Returns a new list holding the distinct values found in exactly one of
+// the two lists; those of this list come first, followed by those of lst.
+func (list *AnyList[T]) SymmetricDifferenceFunc(lst *AnyList[T], hash func(val T) uint64) *AnyList[T] {
+	otherValues := lst.ToArray()
+	values := list.ToArray()
+	mine := newHashedSetOf(hash, list.equals, values)
+	other := newHashedSetOf(hash, list.equals, otherValues)
+
+	result := list.newAnyListLike()
+	distinctAnyInto(result, values, newHashedSet(hash, list.equals, len(values)), func(val T) bool {
+		return !other.contains(val)
+	})
+	distinctAnyInto(result, otherValues, newHashedSet(hash, list.equals, len(otherValues)), func(val T) bool {
+		return !mine.contains(val)
+	})
+	return result
+}
+
+// RetainAllFunc ...Removes from this list every element that is not contained in lst.
+// Returns true if the list changed.
+func (list *AnyList[T]) RetainAllFunc(lst *AnyList[T], hash func(val T) uint64) bool {
+	other := newHashedSetOf(hash, list.equals, lst.ToArray())
+
+	defer list.unlockAndDeliver()
+	list.lock("RetainAllFunc")
+
+	return list.removeIf(func(val T) bool {
+		return !other.contains(val)
+	})
+}
+
+// RemoveAllFunc ...Does the same job as RemoveAll, i.e. for every element of lst the first
+// matching element of this list is removed, but in O(n+m) time.
+// Returns true if the list changed.
+func (list *AnyList[T]) RemoveAllFunc(lst *AnyList[T], hash func(val T) uint64) bool {
+	other := newHashedSetOf(hash, list.equals, lst.ToArray())
+
+	defer list.unlockAndDeliver()
+	list.lock("RemoveAllFunc")
+
+	return list.removeIf(other.take)
+}
+
+// removeIf ...Unlinks every node whose value satisfies the predicate and reports whether any was removed.
+func (list *AnyList[T]) removeIf(predicate func(val T) bool) bool {
+
+	removed := false
+	x := list.firstNode
+	last := list.lastNode
+	sz := list.count()
+
+	for i := 0; i < sz && x != nil; i++ {
+		next := x.next
+		if predicate(x.val) {
+			list.removeNode(x)
+			removed = true
+		}
+		if x == last {
+			break
+		}
+		x = next
+	}
+
+	return removed
+}
@@ -0,0 +1,168 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Stacks.
+//
+// A Stack keeps its values in the nodes of an AnyList, the top of the stack
+// being the last node, so it shares the list's node code, its allocator and
+// its instrumentation, and grows and shrinks one node at a time instead of
+// reallocating a slice. Every method takes the lock once, so Pop does what
+// LastElement and RemoveIndex(Count()-1) did in a single step, and returns
+// ErrEmptyStack instead of panicking when there is nothing to pop.
+//
+// A stack made with a max depth refuses pushes that would take it deeper,
+// with ErrOverflow; Push with several values pushes all of them or none.
+
+// ErrEmptyStack - Returned when popping or peeking at an empty stack, or popping more values than it holds
+var ErrEmptyStack = errors.New("the stack is empty")
+
+// ErrOverflow - Returned when a push would take a stack past its max depth
+var ErrOverflow = errors.New("the stack is full")
+
+// Stack - A thread safe last in, first out stack, see stack.go
+type Stack[T any] struct {
+	list *AnyList[T]
+	// the most values the stack may hold, 0 for no limit
+	maxDepth int
+}
+
+// NewStack ...Creates an empty stack that holds at most maxDepth values, or any number if maxDepth is 0 or less
+func NewStack[T any](maxDepth int) *Stack[T] {
+	return &Stack[T]{list: NewAnyList[T](), maxDepth: max(maxDepth, 0)}
+}
+
+// WithInstrumentation ...Reports the stack's operations, lock timings and traversals to instr, and returns the stack
+func (s *Stack[T]) WithInstrumentation(instr Instrumentation) *Stack[T] {
+	s.list.WithInstrumentation(instr)
+	return s
+}
+
+// WithAllocator ...Makes the stack take its nodes from an allocator, see AnyList.WithAllocator, and returns the stack
+func (s *Stack[T]) WithAllocator(mode AllocMode) *Stack[T] {
+	s.list.WithAllocator(mode)
+	return s
+}
+
+// MaxDepth ...Returns the most values the stack may hold, 0 if there is no limit
+func (s *Stack[T]) MaxDepth() int {
+	return s.maxDepth
+}
+
+// Push ...Pushes the values in order, so the last one ends up on top. If they do not all fit under the max depth,
+// none is pushed and the error wraps ErrOverflow.
+func (s *Stack[T]) Push(vals ...T) error {
+	list := s.list
+
+	defer list.unlockAndDeliver()
+	list.lock("Push")
+
+	if s.maxDepth > 0 && list.size+len(vals) > s.maxDepth {
+		return fmt.Errorf("%w: pushing %d values on %d would pass the max depth of %d", ErrOverflow, len(vals), list.size, s.maxDepth)
+	}
+	for _, val := range vals {
+		list.append(val)
+	}
+	return nil
+}
+
+// Pop ...Removes the value on top and returns it, or ErrEmptyStack
+func (s *Stack[T]) Pop() (T, error) {
+	list := s.list
+
+	defer list.unlockAndDeliver()
+	list.lock("Pop")
+
+	if list.size == 0 {
+		var nilVal T
+		return nilVal, ErrEmptyStack
+	}
+	return s.pop(), nil
+}
+
+// pop ...Removes the value on top of a stack that is not empty and returns it
+func (s *Stack[T]) pop() T {
+	top := s.list.lastNode
+	val := top.val
+	s.list.removeNode(top)
+	return val
+}
+
+// Peek ...Returns the value on top without removing it, or ErrEmptyStack
+func (s *Stack[T]) Peek() (T, error) {
+	list := s.list
+
+	defer list.unlock()
+	list.lock("Peek")
+
+	if list.size == 0 {
+		var nilVal T
+		return nilVal, ErrEmptyStack
+	}
+	return list.lastNode.val, nil
+}
+
+// PopN ...Removes the n values on top and returns them, the top first. If the stack holds fewer than n values,
+// none is popped and the error wraps ErrEmptyStack.
+func (s *Stack[T]) PopN(n int) ([]T, error) {
+	list := s.list
+
+	defer list.unlockAndDeliver()
+	list.lock("PopN")
+
+	if n < 0 {
+		return nil, fmt.Errorf("cannot pop %d values", n)
+	}
+	if n > list.size {
+		return nil, fmt.Errorf("%w: cannot pop %d values off %d", ErrEmptyStack, n, list.size)
+	}
+	vals := make([]T, n)
+	for i := range vals {
+		vals[i] = s.pop()
+	}
+	return vals, nil
+}
+
+// Drain ...Removes every value and returns them, the top first
+func (s *Stack[T]) Drain() []T {
+	list := s.list
+
+	defer list.unlockAndDeliver()
+	list.lock("Drain")
+
+	vals := s.values()
+	list.clear()
+	return vals
+}
+
+// ToArray ...Returns the values, the top first, without removing them
+func (s *Stack[T]) ToArray() []T {
+	list := s.list
+
+	defer list.unlock()
+	list.lock("ToArray")
+
+	return s.values()
+}
+
+// values ...Returns the values, the top first
+func (s *Stack[T]) values() []T {
+	vals := make([]T, 0, s.list.size)
+	for x := s.list.lastNode; x != nil; x = x.prev {
+		vals = append(vals, x.val)
+	}
+	return vals
+}
+
+// Count ...Returns the number of values on the stack
+func (s *Stack[T]) Count() int {
+	return s.list.Count()
+}
+
+// IsEmpty ...Reports whether the stack is empty
+func (s *Stack[T]) IsEmpty() bool {
+	return s.Count() == 0
+}
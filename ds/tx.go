@@ -0,0 +1,358 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Transactions.
+//
+// Every public method of a list takes the lock on its own, so other
+// goroutines may see the list between two calls. Update runs a function
+// under a single lock acquisition instead, handing it a Tx whose methods
+// change the list without locking it again.
+//
+// While the function runs, every change is journaled as an event (see
+// events.go). If the function returns an error or panics, the inverse of
+// each journaled event is replayed, newest first, and the list is back to
+// what it was. Otherwise the events go on to the undo history and the
+// subscribers of the list, so a committed transaction is a single undo step.
+//
+// Journaling needs the position of every changed node, which is O(n) in
+// the middle of a list unless it has a position index (see
+// WithPositionIndex).
+
+// txJournal - The changes made by the transaction in progress on a root list
+type txJournal[T any] struct {
+	events []Event[T]
+	// set while a rollback replays inverse events
+	discard bool
+}
+
+func (j *txJournal[T]) record(ev Event[T]) {
+	if !j.discard {
+		j.events = append(j.events, ev)
+	}
+}
+
+// rollback ...Undoes the journaled changes, newest first
+func (j *txJournal[T]) rollback(replay func(ev Event[T]) error) error {
+	j.discard = true
+	defer func() {
+		j.discard = false
+	}()
+
+	for i := len(j.events) - 1; i >= 0; i-- {
+		if err := replay(inverseEvent(j.events[i])); err != nil {
+			return err
+		}
+	}
+	j.events = nil
+	return nil
+}
+
+// txWindow - Where a sublist sat in its root list when a transaction began. Replaying
+// inverse events on the root list puts the values back but not the ends of the sublists,
+// which may even have been cut loose from their parent if the transaction emptied them.
+type txWindow[L any] struct {
+	list     *L
+	parent   *L
+	start    int
+	size     int
+	parenLen int
+}
+
+// txTarget - The unlocked operations a Tx needs, implemented by List and AnyList
+type txTarget[T any] interface {
+	add(val T)
+	addVal(val T, index int) (bool, error)
+	addValues(args ...T)
+	get(index int) (T, error)
+	set(index int, val T) error
+	remove(val T) bool
+	removeIndex(index int) bool
+	indexOf(val T) int
+	contains(val T) bool
+	count() int
+	clear()
+	values() []T
+}
+
+// Tx - Changes a list from inside Update. It must not be used once Update has returned.
+type Tx[T any] struct {
+	list txTarget[T]
+}
+
+// Add ...Appends val to the list
+func (tx *Tx[T]) Add(val T) {
+	tx.list.add(val)
+}
+
+// AddVal ...Inserts val at index
+func (tx *Tx[T]) AddVal(val T, index int) error {
+	_, err := tx.list.addVal(val, index)
+	return err
+}
+
+// AddValues ...Appends the values to the list
+func (tx *Tx[T]) AddValues(args ...T) {
+	tx.list.addValues(args...)
+}
+
+// Get ...Returns the value at index
+func (tx *Tx[T]) Get(index int) (T, error) {
+	return tx.list.get(index)
+}
+
+// Set ...Replaces the value at index
+func (tx *Tx[T]) Set(index int, val T) error {
+	return tx.list.set(index, val)
+}
+
+// Remove ...Removes the first occurrence of val. Returns false if there is none.
+func (tx *Tx[T]) Remove(val T) bool {
+	return tx.list.remove(val)
+}
+
+// RemoveIndex ...Removes the value at index
+func (tx *Tx[T]) RemoveIndex(index int) error {
+	if !tx.list.removeIndex(index) {
+		return errors.New("Index=(" + strconv.Itoa(index) + ") is out of range")
+	}
+	return nil
+}
+
+// IndexOf ...Returns the position of the first occurrence of val, or -1
+func (tx *Tx[T]) IndexOf(val T) int {
+	return tx.list.indexOf(val)
+}
+
+// Contains ...Reports whether val is in the list
+func (tx *Tx[T]) Contains(val T) bool {
+	return tx.list.contains(val)
+}
+
+// Count ...Returns the size of the list
+func (tx *Tx[T]) Count() int {
+	return tx.list.count()
+}
+
+// Clear ...Removes every value from the list
+func (tx *Tx[T]) Clear() {
+	tx.list.clear()
+}
+
+// ToArray ...Returns the values of the list as a slice
+func (tx *Tx[T]) ToArray() []T {
+	return tx.list.values()
+}
+
+// errRollback ...Reports a rollback that could not put the list back the way it was
+func errRollback(cause error, err error) error {
+	return fmt.Errorf("rollback after %v failed, the list may be corrupt: %w", cause, err)
+}
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// Update ...Runs function with the list locked and returns its error. If function returns an
+// error or panics, every change it made through tx is rolled back; a panic is then re-raised.
+// function must make its changes through tx: calling the methods of the list itself would deadlock.
+// On a sublist, Update locks the list at the top of the sublist chain as well, so goroutines using
+// that list wait for the transaction too. Calling Update inside function deadlocks: transactions do not nest.
+// Sublists taken inside a transaction that rolls back must not be used afterwards.
+func (list *AnyList[T]) Update(function func(tx *Tx[T]) error) (err error) {
+	// the changes are journaled and rolled back on the root, which must not change meanwhile
+	root := list.root()
+	defer root.unlockAndDeliver()
+	root.lock("Update")
+	if list != root {
+		// not through lock, so the instrumentation counts the Update once
+		defer list.mu.Unlock()
+		list.mu.Lock()
+	}
+
+	journal := new(txJournal[T])
+	root.journal = journal
+
+	// events hold positions in the root list, so the rollback runs on the root
+	windows := list.windows()
+
+	failed := true
+	defer func() {
+		if failed {
+			cause := err
+			p := recover()
+			if p != nil {
+				cause = fmt.Errorf("panic: %v", p)
+			}
+			if rerr := journal.rollback(root.replay); rerr != nil {
+				err = errRollback(cause, rerr)
+			} else if rerr := root.restoreWindows(windows); rerr != nil {
+				err = errRollback(cause, rerr)
+			}
+			if p != nil {
+				defer panic(p)
+			}
+		}
+		root.journal = nil
+		for _, ev := range journal.events {
+			root.emit(ev)
+		}
+	}()
+
+	err = function(&Tx[T]{list: list})
+	failed = err != nil
+	return err
+}
+
+// windows ...Returns where the list and the sublists above it sit in the root list
+func (list *AnyList[T]) windows() []txWindow[AnyList[T]] {
+	root := list.root()
+	var windows []txWindow[AnyList[T]]
+	for l := list; l.parent != nil; {
+		parent := l.parent
+		if l.count() > 0 {
+			windows = append(windows, txWindow[AnyList[T]]{
+				list:     l,
+				parent:   parent,
+				start:    root.positionOf(l.firstNode),
+				size:     l.size,
+				parenLen: l.parenLen,
+			})
+		}
+		l = parent
+	}
+	return windows
+}
+
+// restoreWindows ...Puts the sublists of windows back where they sat in this root list
+func (list *AnyList[T]) restoreWindows(windows []txWindow[AnyList[T]]) error {
+	for _, w := range windows {
+		first, err := list.getNode(w.start)
+		if err != nil {
+			return err
+		}
+		last, err := list.getNode(w.start + w.size - 1)
+		if err != nil {
+			return err
+		}
+		w.list.firstNode, w.list.lastNode = first, last
+		w.list.parent, w.list.parenLen, w.list.size = w.parent, w.parenLen, w.size
+		w.list.iter, w.list.nodeIter = nil, nil
+	}
+	return nil
+}
+
+// values ...Returns the values of the list as a slice, without touching the shared iterators
+func (list *AnyList[T]) values() []T {
+	result := make([]T, 0, list.count())
+	list.walkNodes(func(x *node[T]) {
+		result = append(result, x.val)
+	})
+	return result
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// Update ...Runs function with the list locked and returns its error. If function returns an
+// error or panics, every change it made through tx is rolled back; a panic is then re-raised.
+// function must make its changes through tx: calling the methods of the list itself would deadlock.
+// On a sublist, Update locks the list at the top of the sublist chain as well, so goroutines using
+// that list wait for the transaction too. Calling Update inside function deadlocks: transactions do not nest.
+// Sublists taken inside a transaction that rolls back must not be used afterwards.
+func (list *List[T]) Update(function func(tx *Tx[T]) error) (err error) {
+	// the changes are journaled and rolled back on the root, which must not change meanwhile
+	root := list.root()
+	defer root.unlockAndDeliver()
+	root.lock("Update")
+	if list != root {
+		// not through lock, so the instrumentation counts the Update once
+		defer list.mu.Unlock()
+		list.mu.Lock()
+	}
+
+	journal := new(txJournal[T])
+	root.journal = journal
+
+	// events hold positions in the root list, so the rollback runs on the root
+	windows := list.windows()
+
+	failed := true
+	defer func() {
+		if failed {
+			cause := err
+			p := recover()
+			if p != nil {
+				cause = fmt.Errorf("panic: %v", p)
+			}
+			if rerr := journal.rollback(root.replay); rerr != nil {
+				err = errRollback(cause, rerr)
+			} else if rerr := root.restoreWindows(windows); rerr != nil {
+				err = errRollback(cause, rerr)
+			}
+			if p != nil {
+				defer panic(p)
+			}
+		}
+		root.journal = nil
+		for _, ev := range journal.events {
+			root.emit(ev)
+		}
+	}()
+
+	err = function(&Tx[T]{list: list})
+	failed = err != nil
+	return err
+}
+
+// windows ...Returns where the list and the sublists above it sit in the root list
+func (list *List[T]) windows() []txWindow[List[T]] {
+	root := list.root()
+	var windows []txWindow[List[T]]
+	for l := list; l.parent != nil; {
+		parent := l.parent
+		if l.count() > 0 {
+			windows = append(windows, txWindow[List[T]]{
+				list:     l,
+				parent:   parent,
+				start:    root.positionOf(l.firstNode),
+				size:     l.size,
+				parenLen: l.parenLen,
+			})
+		}
+		l = parent
+	}
+	return windows
+}
+
+// restoreWindows ...Puts the sublists of windows back where they sat in this root list
+func (list *List[T]) restoreWindows(windows []txWindow[List[T]]) error {
+	for _, w := range windows {
+		first, err := list.getNode(w.start)
+		if err != nil {
+			return err
+		}
+		last, err := list.getNode(w.start + w.size - 1)
+		if err != nil {
+			return err
+		}
+		w.list.firstNode, w.list.lastNode = first, last
+		w.list.parent, w.list.parenLen, w.list.size = w.parent, w.parenLen, w.size
+		w.list.iter, w.list.nodeIter = nil, nil
+	}
+	return nil
+}
+
+// values ...Returns the values of the list as a slice, without touching the shared iterators
+func (list *List[T]) values() []T {
+	result := make([]T, 0, list.count())
+	list.walkNodes(func(x *lNode[T]) {
+		result = append(result, x.val)
+	})
+	return result
+}
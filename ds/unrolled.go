@@ -0,0 +1,591 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// unrolledBlockSize - The number of values held by each block of an UnrolledList
+const unrolledBlockSize = 64
+
+// ublock - A block of an UnrolledList. It holds up to unrolledBlockSize values in a fixed size array
+type ublock[T any] struct {
+	next *ublock[T]
+	prev *ublock[T]
+	vals [unrolledBlockSize]T
+	n    int
+}
+
+// UnrolledList - A list that stores its values in a doubly linked list of fixed size arrays.
+//
+// It offers the same API as AnyList, but instead of one heap allocation per
+// value it makes one per unrolledBlockSize values. Iteration walks
+// contiguous memory and the garbage collector has far fewer pointers to
+// chase, which makes a big difference for small types such as int.
+// Positional lookups skip whole blocks, so they are unrolledBlockSize times
+// faster than on an AnyList, while an insertion or removal in the middle
+// shifts at most one block.
+//
+// Sublists are views: a window of positions over their parent. Changes made
+// through a view are reflected in the parent, and a view shares its parent's
+// lock. As with AnyList, clearing a view detaches it from its parent.
+// A view keeps its window where it was created, so adding or removing
+// elements before the window through the parent directly shifts what the
+// view sees. When the parent shrinks below the window, the view is cut back
+// to what is left of it, and is empty once the parent has been cleared.
+type UnrolledList[T any] struct {
+	head *ublock[T]
+	tail *ublock[T]
+	size int
+	// Set on views only: the list the view looks into, and where the view starts in it
+	parent *UnrolledList[T]
+	offset int
+	// Shared by a list and all the views over it
+	mu *sync.Mutex
+	// Every instance had better override this function after calling the NewUnrolledList function in order to gain speed in the Remove, IndexOf and other relevant function
+	Equals func(val1 T, val2 T) bool
+}
+
+func NewUnrolledList[T any]() *UnrolledList[T] {
+	list := new(UnrolledList[T])
+
+	list.mu = new(sync.Mutex)
+	list.Equals = defaultEquals[T]
+
+	return list
+}
+
+// equals ...Compares two values with the Equals function of the list, or with defaultEquals if it has none
+func (list *UnrolledList[T]) equals(val1 T, val2 T) bool {
+	if list.Equals == nil {
+		return defaultEquals(val1, val2)
+	}
+	return list.Equals(val1, val2)
+}
+
+func (list *UnrolledList[T]) isSubList() bool {
+	return list.parent != nil
+}
+
+// base ...Returns the list that owns the blocks and the position of this list's first element in it
+func (list *UnrolledList[T]) base() (*UnrolledList[T], int) {
+	r := list
+	offset := 0
+	for r.parent != nil {
+		offset += r.offset
+		r = r.parent
+	}
+	return r, offset
+}
+
+// sync ...Cuts a view back to what is left of its window when its parent has shrunk below it, e.g. because
+// values were removed from the parent directly or the parent was cleared. Only to be called with the lock held.
+func (list *UnrolledList[T]) sync() {
+	if list.parent == nil {
+		return
+	}
+	list.parent.sync()
+	if list.offset > list.parent.size {
+		list.offset = list.parent.size
+	}
+	if avail := list.parent.size - list.offset; list.size > avail {
+		list.size = avail
+	}
+}
+
+// locate ...Returns the block holding the element at index, and its slot in that block.
+// Only to be called on the list that owns the blocks, with 0 <= index < size.
+func (list *UnrolledList[T]) locate(index int) (*ublock[T], int) {
+	if index < (list.size >> 1) {
+		b := list.head
+		for index >= b.n {
+			index -= b.n
+			b = b.next
+		}
+		return b, index
+	}
+	b := list.tail
+	pos := list.size - b.n
+	for index < pos {
+		b = b.prev
+		pos -= b.n
+	}
+	return b, index - pos
+}
+
+// linkBlockAfter ...Links nb after b; a nil b makes nb the head
+func (list *UnrolledList[T]) linkBlockAfter(b *ublock[T], nb *ublock[T]) {
+	nb.prev = b
+	if b == nil {
+		nb.next = list.head
+		list.head = nb
+	} else {
+		nb.next = b.next
+		b.next = nb
+	}
+	if nb.next == nil {
+		list.tail = nb
+	} else {
+		nb.next.prev = nb
+	}
+}
+
+func (list *UnrolledList[T]) unlinkBlock(b *ublock[T]) {
+	if b.prev == nil {
+		list.head = b.next
+	} else {
+		b.prev.next = b.next
+	}
+	if b.next == nil {
+		list.tail = b.prev
+	} else {
+		b.next.prev = b.prev
+	}
+	b.next = nil
+	b.prev = nil
+}
+
+// insert ...Inserts val at index, 0 <= index <= count
+func (list *UnrolledList[T]) insert(index int, val T) {
+	if list.parent != nil {
+		list.parent.insert(list.offset+index, val)
+		list.size++
+		return
+	}
+
+	if index == list.size {
+		if list.tail == nil || list.tail.n == unrolledBlockSize {
+			list.linkBlockAfter(list.tail, new(ublock[T]))
+		}
+		list.tail.vals[list.tail.n] = val
+		list.tail.n++
+		list.size++
+		return
+	}
+
+	b, i := list.locate(index)
+	if b.n == unrolledBlockSize {
+		// split the full block in two halves
+		half := unrolledBlockSize / 2
+		nb := new(ublock[T])
+		copy(nb.vals[:], b.vals[half:])
+		nb.n = unrolledBlockSize - half
+		var zero T
+		for j := half; j < unrolledBlockSize; j++ {
+			b.vals[j] = zero
+		}
+		b.n = half
+		list.linkBlockAfter(b, nb)
+		if i > half {
+			b = nb
+			i -= half
+		}
+	}
+	copy(b.vals[i+1:b.n+1], b.vals[i:b.n])
+	b.vals[i] = val
+	b.n++
+	list.size++
+}
+
+// removeRange ...Removes the elements from index from up to, but not including, index to
+func (list *UnrolledList[T]) removeRange(from int, to int) {
+	if from >= to {
+		return
+	}
+	if list.parent != nil {
+		list.parent.removeRange(list.offset+from, list.offset+to)
+		list.size -= to - from
+		return
+	}
+	if from == 0 && to == list.size {
+		list.head = nil
+		list.tail = nil
+		list.size = 0
+		return
+	}
+
+	var zero T
+	remaining := to - from
+	b, i := list.locate(from)
+	for remaining > 0 {
+		take := b.n - i
+		if take > remaining {
+			take = remaining
+		}
+		copy(b.vals[i:], b.vals[i+take:b.n])
+		for j := b.n - take; j < b.n; j++ {
+			b.vals[j] = zero
+		}
+		b.n -= take
+		remaining -= take
+		list.size -= take
+
+		next := b.next
+		if b.n == 0 {
+			list.unlinkBlock(b)
+		} else if remaining == 0 {
+			// the range ended inside b: let it absorb a sparse neighbour
+			list.mergeWithNext(b)
+			if b.prev != nil {
+				list.mergeWithNext(b.prev)
+			}
+		}
+		b = next
+		i = 0
+	}
+}
+
+// mergeWithNext ...Folds the next block into b when both are at most half full, so sparse blocks do not pile up
+func (list *UnrolledList[T]) mergeWithNext(b *ublock[T]) {
+	next := b.next
+	if next == nil || b.n+next.n > unrolledBlockSize/2 {
+		return
+	}
+	copy(b.vals[b.n:], next.vals[:next.n])
+	b.n += next.n
+	list.unlinkBlock(next)
+}
+
+// nodeAt ...Returns the block and slot of the element at index in this list
+func (list *UnrolledList[T]) nodeAt(index int) (*ublock[T], int, error) {
+	if index < 0 {
+		return nil, 0, errors.New("Index=(" + strconv.Itoa(index) + ") < 0 is not allowed")
+	}
+	if index >= list.size {
+		return nil, 0, errors.New("Index=(" + strconv.Itoa(index) + ") > list-size=(" + strconv.Itoa(list.size) + ") is not allowed")
+	}
+	root, offset := list.base()
+	b, i := root.locate(offset + index)
+	return b, i, nil
+}
+
+// walk ...Calls function on every value of this list, in order, until it returns false
+func (list *UnrolledList[T]) walk(function func(val T) bool) {
+	if list.size == 0 {
+		return
+	}
+	root, offset := list.base()
+	b, i := root.locate(offset)
+	for remaining := list.size; remaining > 0 && b != nil; b, i = b.next, 0 {
+		for ; i < b.n && remaining > 0; i++ {
+			if !function(b.vals[i]) {
+				return
+			}
+			remaining--
+		}
+	}
+}
+
+func (list *UnrolledList[T]) ForEach(function func(val T) bool) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	list.walk(function)
+}
+
+func (list *UnrolledList[T]) ToArray() []T {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	return list.toArray()
+}
+
+func (list *UnrolledList[T]) toArray() []T {
+	result := make([]T, 0, list.size)
+	list.walk(func(val T) bool {
+		result = append(result, val)
+		return true
+	})
+	return result
+}
+
+func (list *UnrolledList[T]) Add(val T) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	list.insert(list.size, val)
+}
+
+func (list *UnrolledList[T]) AddVal(val T, index int) bool {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	if index < 0 || index > list.size {
+		return false
+	}
+	list.insert(index, val)
+	return true
+}
+
+func (list *UnrolledList[T]) AddValues(args ...T) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	list.addValuesAt(list.size, args)
+}
+
+func (list *UnrolledList[T]) AddArray(array []T) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	list.addValuesAt(list.size, array)
+}
+
+func (list *UnrolledList[T]) addValuesAt(index int, values []T) {
+	for i, v := range values {
+		list.insert(index+i, v)
+	}
+}
+
+func (list *UnrolledList[T]) AddAll(lst *UnrolledList[T]) bool {
+	values := lst.ToArray()
+
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	list.addValuesAt(list.size, values)
+	return true
+}
+
+func (list *UnrolledList[T]) AddAllAt(index int, lst *UnrolledList[T]) bool {
+	values := lst.ToArray()
+
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	if index < 0 || index > list.size || len(values) == 0 {
+		return false
+	}
+	list.addValuesAt(index, values)
+	return true
+}
+
+// Clone ...Returns an independent copy of the list. Cloning a sublist gives a list that is no longer tied to the parent.
+func (list *UnrolledList[T]) Clone() *UnrolledList[T] {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	ls := NewUnrolledList[T]()
+	ls.Equals = list.Equals
+	ls.addValuesAt(0, list.toArray())
+	return ls
+}
+
+func (list *UnrolledList[T]) Remove(val T) bool {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	return list.remove(val)
+}
+
+func (list *UnrolledList[T]) remove(val T) bool {
+	i := list.indexOf(val)
+	if i == -1 {
+		return false
+	}
+	list.removeRange(i, i+1)
+	return true
+}
+
+func (list *UnrolledList[T]) RemoveIndex(index int) bool {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	if index < 0 || index >= list.size {
+		return false
+	}
+	list.removeRange(index, index+1)
+	return true
+}
+
+func (list *UnrolledList[T]) RemoveAll(lst *UnrolledList[T]) bool {
+	values := lst.ToArray()
+
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	for _, v := range values {
+		list.remove(v)
+	}
+	return true
+}
+
+func (list *UnrolledList[T]) IsEmpty() bool {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	return list.size == 0
+}
+
+// SubList ...Creates a view of the list... starting at startIndex and ending at endIndex-1.
+// In essence, the element at `endIndex` is not included
+func (list *UnrolledList[T]) SubList(startIndex int, endIndex int) (*UnrolledList[T], error) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	if startIndex < 0 {
+		return nil, errors.New("startIndex(" + strconv.Itoa(startIndex) + ") < 0 is not allowed")
+	}
+	if endIndex > list.size {
+		return nil, errors.New("endIndex(" + strconv.Itoa(endIndex) + ") > listsize(" + strconv.Itoa(list.size) + ") is not allowed")
+	}
+	if startIndex > endIndex {
+		return nil, errors.New("startIndex(" + strconv.Itoa(startIndex) + ") > endIndex(" + strconv.Itoa(endIndex) + ") is not allowed")
+	}
+
+	subList := new(UnrolledList[T])
+	subList.mu = list.mu
+	subList.Equals = list.Equals
+	subList.parent = list
+	subList.offset = startIndex
+	subList.size = endIndex - startIndex
+
+	return subList, nil
+}
+
+// Set - changes the element at that index in the list
+func (list *UnrolledList[T]) Set(index int, val T) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	b, i, err := list.nodeAt(index)
+	if err == nil {
+		b.vals[i] = val
+	}
+}
+
+// Get - returns the element at that index in the list
+func (list *UnrolledList[T]) Get(index int) (T, error) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	b, i, err := list.nodeAt(index)
+	if err != nil {
+		var nilVal T
+		return nilVal, err
+	}
+	return b.vals[i], nil
+}
+
+// LastElement ...Returns the last element of the list, or nil if the list is empty
+func (list *UnrolledList[T]) LastElement() interface{} {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	b, i, err := list.nodeAt(list.size - 1)
+	if err != nil {
+		return nil
+	}
+	return b.vals[i]
+}
+
+func (list *UnrolledList[T]) Contains(val T) bool {
+	return list.IndexOf(val) != -1
+}
+
+func (list *UnrolledList[T]) IndexOf(val T) int {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	return list.indexOf(val)
+}
+
+func (list *UnrolledList[T]) indexOf(val T) int {
+	index := -1
+	i := 0
+	list.walk(func(x T) bool {
+		if list.equals(x, val) {
+			index = i
+			return false
+		}
+		i++
+		return true
+	})
+	return index
+}
+
+// Clear ...Empties the list. A cleared sublist removes its elements from the parent and becomes detached from it.
+func (list *UnrolledList[T]) Clear() bool {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	list.removeRange(0, list.size)
+	if list.parent != nil {
+		list.parent = nil
+		list.offset = 0
+		list.mu = new(sync.Mutex)
+	}
+	return true
+}
+
+func (list *UnrolledList[T]) Count() int {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	return list.size
+}
+
+// Log ...Prints the values and length of the list to stdout.
+//
+// Deprecated: use Dump, which writes to any io.Writer, or print the list with the fmt verbs.
+func (list *UnrolledList[T]) Log(optionalLabel string) {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+	list.sync()
+
+	list.log(optionalLabel)
+}
+
+func (list *UnrolledList[T]) log(optionalLabel string) {
+
+	if list.size == 0 {
+		fmt.Println(optionalLabel + ":\n[], len: 0")
+		return
+	}
+
+	counter := 0
+	var bld strings.Builder
+
+	bld.WriteString(optionalLabel)
+	bld.WriteString(":\n[")
+
+	list.walk(func(x T) bool {
+		if counter > 0 {
+			bld.WriteString(", ")
+		}
+		bld.WriteString(fmt.Sprintf("%v", x))
+		counter++
+		return true
+	})
+
+	bld.WriteString("], len:")
+	bld.WriteString(strconv.Itoa(list.size))
+	bld.WriteString(", confirm-len(")
+	bld.WriteString(strconv.Itoa(counter))
+	bld.WriteString(")")
+
+	fmt.Println(bld.String())
+}
@@ -0,0 +1,316 @@
+package ds
+
+import (
+	"fmt"
+)
+
+// Invariant checks.
+//
+// Validate walks the nodes of a list and reports the first broken invariant
+// it finds:
+//
+//   - firstNode and lastNode are either both nil or both set
+//   - lastNode can be reached from firstNode without going round a cycle
+//   - every node is the prev of its next and the next of its prev
+//   - a list with no parent has nothing before its first node or after its last
+//   - the size of the list matches the number of nodes between its ends
+//   - a sublist lies within its parent, and the parent is valid too
+//   - the position index, if any, holds every node of the root at its position
+//
+// A sublist whose parent has been changed behind its back (by the parent
+// itself or by another sublist) recounts its size lazily, so its size is
+// only checked when it is known to be up to date.
+//
+// Building with the linkedlist_debug tag runs Validate after every change
+// made through a public method and panics on the first error, see debug_on.go.
+
+// ---------------------------------------------------------------------------
+// AnyList[T any]
+// ---------------------------------------------------------------------------
+
+// Validate ...Checks the links, ends and size of the list and of the lists above it, see validate.go
+func (list *AnyList[T]) Validate() error {
+	defer list.unlock()
+	list.lock("Validate")
+
+	return list.validate()
+}
+
+func (list *AnyList[T]) validate() error {
+	if (list.firstNode == nil) != (list.lastNode == nil) {
+		return fmt.Errorf("firstNode is %p but lastNode is %p", list.firstNode, list.lastNode)
+	}
+
+	if list.parent == nil && list.firstNode != nil {
+		if list.firstNode.prev != nil {
+			return fmt.Errorf("the first node of a list without parent has a prev node")
+		}
+		if list.lastNode.next != nil {
+			return fmt.Errorf("the last node of a list without parent has a next node")
+		}
+	}
+
+	n := 0
+	// moves two nodes for every one x moves: catching up with x means going round a cycle
+	fast := list.firstNode
+	for x := list.firstNode; x != nil; x = x.next {
+		if x.next != nil && x.next.prev != x {
+			return fmt.Errorf("node %d (%v) is not the prev of its next node", n, x.val)
+		}
+		if x.prev != nil && x.prev.next != x {
+			return fmt.Errorf("node %d (%v) is not the next of its prev node", n, x.val)
+		}
+		n++
+		if x == list.lastNode {
+			break
+		}
+		if x.next == nil {
+			return fmt.Errorf("lastNode cannot be reached from firstNode, the chain ends after %d nodes", n)
+		}
+		for k := 0; k < 2 && fast != nil; k++ {
+			fast = fast.next
+		}
+		if fast == x.next {
+			return fmt.Errorf("cycle: the chain comes back to a node after %d nodes", n)
+		}
+	}
+
+	upToDate := list.parent == nil || list.parenLen == list.parent.size
+	if upToDate && n != list.size {
+		return fmt.Errorf("size is %d but the list holds %d nodes", list.size, n)
+	}
+
+	if list.parent != nil {
+		if err := list.parent.validate(); err != nil {
+			return fmt.Errorf("parent: %w", err)
+		}
+		if list.firstNode != nil && !list.parent.spans(list.firstNode, list.lastNode) {
+			return fmt.Errorf("the sublist does not lie within its parent")
+		}
+		return nil
+	}
+
+	if list.order != nil {
+		if list.order.len() != n {
+			return fmt.Errorf("the position index holds %d nodes but the list holds %d", list.order.len(), n)
+		}
+		i := 0
+		for x := list.firstNode; x != nil; x = x.next {
+			if r := list.order.rank(x); r != i {
+				return fmt.Errorf("the position index puts node %d (%v) at %d", i, x.val, r)
+			}
+			i++
+		}
+	}
+	return nil
+}
+
+// spans ...Reports whether the nodes from first to last are, in that order, nodes of this list
+func (list *AnyList[T]) spans(first *node[T], last *node[T]) bool {
+	found := false
+	for x := list.firstNode; x != nil; x = x.next {
+		if x == first {
+			found = true
+		}
+		if found && x == last {
+			return true
+		}
+		if x == list.lastNode {
+			break
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------
+// List[T comparable]
+// ---------------------------------------------------------------------------
+
+// Validate ...Checks the links, ends and size of the list and of the lists above it, see validate.go
+func (list *List[T]) Validate() error {
+	defer list.unlock()
+	list.lock("Validate")
+
+	return list.validate()
+}
+
+func (list *List[T]) validate() error {
+	if (list.firstNode == nil) != (list.lastNode == nil) {
+		return fmt.Errorf("firstNode is %p but lastNode is %p", list.firstNode, list.lastNode)
+	}
+
+	if list.parent == nil && list.firstNode != nil {
+		if list.firstNode.prev != nil {
+			return fmt.Errorf("the first node of a list without parent has a prev node")
+		}
+		if list.lastNode.next != nil {
+			return fmt.Errorf("the last node of a list without parent has a next node")
+		}
+	}
+
+	n := 0
+	// moves two nodes for every one x moves: catching up with x means going round a cycle
+	fast := list.firstNode
+	for x := list.firstNode; x != nil; x = x.next {
+		if x.next != nil && x.next.prev != x {
+			return fmt.Errorf("node %d (%v) is not the prev of its next node", n, x.val)
+		}
+		if x.prev != nil && x.prev.next != x {
+			return fmt.Errorf("node %d (%v) is not the next of its prev node", n, x.val)
+		}
+		n++
+		if x == list.lastNode {
+			break
+		}
+		if x.next == nil {
+			return fmt.Errorf("lastNode cannot be reached from firstNode, the chain ends after %d nodes", n)
+		}
+		for k := 0; k < 2 && fast != nil; k++ {
+			fast = fast.next
+		}
+		if fast == x.next {
+			return fmt.Errorf("cycle: the chain comes back to a node after %d nodes", n)
+		}
+	}
+
+	upToDate := list.parent == nil || list.parenLen == list.parent.size
+	if upToDate && n != list.size {
+		return fmt.Errorf("size is %d but the list holds %d nodes", list.size, n)
+	}
+
+	if list.parent != nil {
+		if err := list.parent.validate(); err != nil {
+			return fmt.Errorf("parent: %w", err)
+		}
+		if list.firstNode != nil && !list.parent.spans(list.firstNode, list.lastNode) {
+			return fmt.Errorf("the sublist does not lie within its parent")
+		}
+		return nil
+	}
+
+	if list.order != nil {
+		if list.order.len() != n {
+			return fmt.Errorf("the position index holds %d nodes but the list holds %d", list.order.len(), n)
+		}
+		i := 0
+		for x := list.firstNode; x != nil; x = x.next {
+			if r := list.order.rank(x); r != i {
+				return fmt.Errorf("the position index puts node %d (%v) at %d", i, x.val, r)
+			}
+			i++
+		}
+	}
+	return nil
+}
+
+// spans ...Reports whether the nodes from first to last are, in that order, nodes of this list
+func (list *List[T]) spans(first *lNode[T], last *lNode[T]) bool {
+	found := false
+	for x := list.firstNode; x != nil; x = x.next {
+		if x == first {
+			found = true
+		}
+		if found && x == last {
+			return true
+		}
+		if x == list.lastNode {
+			break
+		}
+	}
+	return false
+}
+
+// ---------------------------------------------------------------------------
+// CList
+// ---------------------------------------------------------------------------
+
+// Validate ...Checks the links, ends and size of the list and of the lists above it, see validate.go
+func (list *CList) Validate() error {
+	defer list.mu.Unlock()
+	list.mu.Lock()
+
+	return list.validate()
+}
+
+func (list *CList) validate() error {
+	if (list.firstNode == nil) != (list.lastNode == nil) {
+		return fmt.Errorf("firstNode is %p but lastNode is %p", list.firstNode, list.lastNode)
+	}
+
+	if list.parent == nil && list.firstNode != nil {
+		if list.firstNode.prev != nil {
+			return fmt.Errorf("the first node of a list without parent has a prev node")
+		}
+		if list.lastNode.next != nil {
+			return fmt.Errorf("the last node of a list without parent has a next node")
+		}
+	}
+
+	n := 0
+	// moves two nodes for every one x moves: catching up with x means going round a cycle
+	fast := list.firstNode
+	for x := list.firstNode; x != nil; x = x.next {
+		if x.next != nil && x.next.prev != x {
+			return fmt.Errorf("node %d (%v) is not the prev of its next node", n, x.val)
+		}
+		if x.prev != nil && x.prev.next != x {
+			return fmt.Errorf("node %d (%v) is not the next of its prev node", n, x.val)
+		}
+		n++
+		if x == list.lastNode {
+			break
+		}
+		if x.next == nil {
+			return fmt.Errorf("lastNode cannot be reached from firstNode, the chain ends after %d nodes", n)
+		}
+		for k := 0; k < 2 && fast != nil; k++ {
+			fast = fast.next
+		}
+		if fast == x.next {
+			return fmt.Errorf("cycle: the chain comes back to a node after %d nodes", n)
+		}
+	}
+
+	upToDate := list.parent == nil || list.parenLen == list.parent.size
+	if upToDate && n != list.size {
+		return fmt.Errorf("size is %d but the list holds %d nodes", list.size, n)
+	}
+
+	if list.parent != nil {
+		if err := list.parent.validate(); err != nil {
+			return fmt.Errorf("parent: %w", err)
+		}
+		if list.firstNode != nil && !list.parent.spans(list.firstNode, list.lastNode) {
+			return fmt.Errorf("the sublist does not lie within its parent")
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// spans ...Reports whether the nodes from first to last are, in that order, nodes of this list
+func (list *CList) spans(first *cNode, last *cNode) bool {
+	found := false
+	for x := list.firstNode; x != nil; x = x.next {
+		if x == first {
+			found = true
+		}
+		if found && x == last {
+			return true
+		}
+		if x == list.lastNode {
+			break
+		}
+	}
+	return false
+}
+
+// check ...Validates the list in debug builds. Public methods that change the list defer it before taking the lock.
+func (list *CList) check() {
+	if debugChecks {
+		if err := list.Validate(); err != nil {
+			panic("linkedlist: invariant broken: " + err.Error())
+		}
+	}
+}
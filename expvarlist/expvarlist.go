@@ -0,0 +1,85 @@
+// Package expvarlist publishes the measurements of instrumented lists through expvar.
+//
+// Importing expvar registers its /debug/vars handler on http.DefaultServeMux,
+// which is why this lives outside package ds.
+//
+//	list := ds.NewAnyList[string]().WithInstrumentation(expvarlist.Publish("queue"))
+//
+// serves, under the name "queue":
+//
+//	{"ops": {"Add": 12, "Get": 3}, "locks": 15, "lockWaitNanos": 5120, "lockHoldNanos": 48213,
+//	 "traversals": 3, "traversalHops": 17}
+package expvarlist
+
+import (
+	"expvar"
+	"time"
+)
+
+// Metrics - An Instrumentation that adds up what it receives in expvar variables
+type Metrics struct {
+	// Calls per public method name
+	Ops *expvar.Map
+	// Number of times the lock was taken
+	Locks *expvar.Int
+	// Total time spent waiting for and holding the lock
+	LockWaitNanos *expvar.Int
+	LockHoldNanos *expvar.Int
+	// Number of walks to a position and the nodes stepped over by all of them
+	Traversals    *expvar.Int
+	TraversalHops *expvar.Int
+}
+
+// New ...Creates Metrics that are not published, e.g. to publish them in a map of your own with Var
+func New() *Metrics {
+	return &Metrics{
+		Ops:           new(expvar.Map).Init(),
+		Locks:         new(expvar.Int),
+		LockWaitNanos: new(expvar.Int),
+		LockHoldNanos: new(expvar.Int),
+		Traversals:    new(expvar.Int),
+		TraversalHops: new(expvar.Int),
+	}
+}
+
+// Publish ...Creates Metrics and publishes them under name.
+// Like expvar.Publish, it panics if name is already in use.
+func Publish(name string) *Metrics {
+	m := New()
+	expvar.Publish(name, m.Var())
+	return m
+}
+
+// Var ...Returns the metrics as a single expvar.Map
+func (m *Metrics) Var() *expvar.Map {
+	v := new(expvar.Map).Init()
+	v.Set("ops", m.Ops)
+	v.Set("locks", m.Locks)
+	v.Set("lockWaitNanos", m.LockWaitNanos)
+	v.Set("lockHoldNanos", m.LockHoldNanos)
+	v.Set("traversals", m.Traversals)
+	v.Set("traversalHops", m.TraversalHops)
+	return v
+}
+
+// Op ...Implements ds.Instrumentation
+func (m *Metrics) Op(name string) {
+	m.Ops.Add(name, 1)
+}
+
+// LockWaited ...Implements ds.Instrumentation
+func (m *Metrics) LockWaited(d time.Duration) {
+	m.Locks.Add(1)
+	m.LockWaitNanos.Add(int64(d))
+}
+
+// LockHeld ...Implements ds.Instrumentation
+func (m *Metrics) LockHeld(d time.Duration) {
+	m.LockHoldNanos.Add(int64(d))
+}
+
+// Traversed ...Implements ds.Instrumentation
+func (m *Metrics) Traversed(hops int) {
+	m.Traversals.Add(1)
+	m.TraversalHops.Add(int64(hops))
+}
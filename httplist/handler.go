@@ -0,0 +1,240 @@
+// Package httplist serves the named lists of a listd.Store as a JSON REST API, so they can be mounted in
+// any service that uses net/http:
+//
+//	GET    /lists                    the names of the lists, as a JSON array
+//	GET    /lists/{name}             the values of the list, as a JSON array
+//	GET    /lists/{name}?from=&to=   the values from index from to index to, excluded; to defaults to the end
+//	POST   /lists/{name}             appends the values of the JSON array in the body, replies {"len": n}
+//	PUT    /lists/{name}/{index}     replaces the value at index with the JSON string in the body
+//	DELETE /lists/{name}/{index}     removes the value at index and replies with it
+//
+// Long-polling: GET /lists/{name}?from=n&wait=30s holds the request while the
+// list has n values or fewer, until values are added or the wait is over, and
+// then replies with the values from index n on, [] if none came. A client that
+// follows a list sends from = the number of values it has seen so far.
+//
+// Lists are made the first time a POST names them; a GET, PUT or DELETE of a
+// list that has not been made replies 404. Request bodies larger than
+// Handler.MaxBody reply 413. Errors reply with their status code and a JSON
+// object {"error": "..."}.
+package httplist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gbenroscience/linkedlist/ds"
+	"github.com/gbenroscience/linkedlist/listd"
+)
+
+// DefaultMaxWait - The longest a long-polling request waits when Handler.MaxWait is 0
+const DefaultMaxWait = time.Minute
+
+// DefaultMaxBody - The largest request body, in bytes, when Handler.MaxBody is 0
+const DefaultMaxBody = 1 << 20
+
+// Handler - An http.Handler for the lists of a listd.Store. Mount it at the root of a mux,
+// e.g. mux.Handle("/lists", h) and mux.Handle("/lists/", h), or under a prefix with http.StripPrefix.
+type Handler struct {
+	store *listd.Store
+	mux   *http.ServeMux
+	// The longest a long-polling request may wait; a longer wait is cut back to it. 0 means DefaultMaxWait.
+	MaxWait time.Duration
+	// The largest request body, in bytes, that POST and PUT read. 0 means DefaultMaxBody.
+	MaxBody int64
+}
+
+// NewHandler ...Creates a Handler serving the lists of store
+func NewHandler(store *listd.Store) *Handler {
+	h := &Handler{store: store, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /lists", h.names)
+	h.mux.HandleFunc("GET /lists/{name}", h.get)
+	h.mux.HandleFunc("POST /lists/{name}", h.push)
+	h.mux.HandleFunc("PUT /lists/{name}/{index}", h.set)
+	h.mux.HandleFunc("DELETE /lists/{name}/{index}", h.delete)
+	return h
+}
+
+// ServeHTTP ...Implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) names(w http.ResponseWriter, r *http.Request) {
+	names := h.store.Names()
+	slices.Sort(names)
+	writeJSON(w, http.StatusOK, names)
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	list, ok := h.store.Lookup(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no list called %q", name))
+		return
+	}
+	query := r.URL.Query()
+	if !query.Has("from") && !query.Has("to") && !query.Has("wait") {
+		// the whole list, in the JSON encoding of ds.AnyList
+		writeJSON(w, http.StatusOK, list)
+		return
+	}
+
+	from, err := intParam(query.Get("from"), 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	to, err := intParam(query.Get("to"), -1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if query.Has("wait") {
+		wait, err := time.ParseDuration(query.Get("wait"))
+		if err != nil || wait < 0 {
+			writeError(w, http.StatusBadRequest, errors.New("wait must be a duration such as 30s"))
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), min(wait, h.maxWait()))
+		defer cancel()
+		// a timeout is not an error: the reply is then whatever is there, usually nothing
+		if _, err := h.store.WaitLen(ctx, name, from); err != nil && r.Context().Err() != nil {
+			return
+		}
+	}
+
+	values, err := h.store.Range(name, from, to)
+	if err != nil {
+		if query.Has("wait") && from > 0 {
+			// waiting past the end of the list for values that did not come
+			values, err = []string{}, nil
+		} else {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, values)
+}
+
+func (h *Handler) push(w http.ResponseWriter, r *http.Request) {
+	// decoded with the JSON encoding of ds.AnyList, so the body is a JSON array
+	values := ds.NewAnyList[string]()
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBody())
+	if err := json.NewDecoder(r.Body).Decode(values); err != nil {
+		writeError(w, decodeStatus(err), err)
+		return
+	}
+	if values.IsEmpty() {
+		writeError(w, http.StatusBadRequest, errors.New("the body holds no values"))
+		return
+	}
+	n := h.store.Push(r.PathValue("name"), values.ToArray()...)
+	writeJSON(w, http.StatusOK, map[string]int{"len": n})
+}
+
+func (h *Handler) set(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if _, ok := h.store.Lookup(name); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no list called %q", name))
+		return
+	}
+	var val string
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBody())
+	if err := json.NewDecoder(r.Body).Decode(&val); err != nil {
+		writeError(w, decodeStatus(err), err)
+		return
+	}
+	if err := h.store.Set(name, index, val); err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	index, err := strconv.Atoi(r.PathValue("index"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if _, ok := h.store.Lookup(name); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no list called %q", name))
+		return
+	}
+	val, err := h.store.Delete(name, index)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, val)
+}
+
+func (h *Handler) maxWait() time.Duration {
+	if h.MaxWait > 0 {
+		return h.MaxWait
+	}
+	return DefaultMaxWait
+}
+
+func (h *Handler) maxBody() int64 {
+	if h.MaxBody > 0 {
+		return h.MaxBody
+	}
+	return DefaultMaxBody
+}
+
+// intParam ...Parses a query parameter, returning def when it is missing
+func intParam(s string, def int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+	return strconv.Atoi(s)
+}
+
+// decodeStatus ...Returns the status code for a request body that could not be decoded
+func decodeStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+func statusOf(err error) int {
+	if errors.Is(err, listd.ErrIndex) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	body, _ := json.Marshal(map[string]string{"error": err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
@@ -0,0 +1,36 @@
+// Package cmdline splits the command lines of the repl in main and of the listd protocol into words.
+package cmdline
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Split ...Splits a command line into words, keeping Go string literals such as "two words" together
+func Split(line string) ([]string, error) {
+	var args []string
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return args, nil
+		}
+		if line[0] != '"' {
+			end := strings.IndexAny(line, " \t")
+			if end < 0 {
+				end = len(line)
+			}
+			args = append(args, line[:end])
+			line = line[end:]
+			continue
+		}
+
+		prefix, err := strconv.QuotedPrefix(line)
+		if err != nil {
+			return nil, fmt.Errorf("unterminated string: %s", line)
+		}
+		word, _ := strconv.Unquote(prefix)
+		args = append(args, word)
+		line = line[len(prefix):]
+	}
+}
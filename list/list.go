@@ -0,0 +1,221 @@
+// Package list mirrors the API of container/list on top of ds.AnyList, so code written for container/list
+// moves over by changing its import path:
+//
+//	import "github.com/gbenroscience/linkedlist/list"
+//
+//	l := list.New()
+//	e := l.PushBack(1)
+//	l.InsertBefore(0, e)
+//	for e := l.Front(); e != nil; e = e.Next() {
+//		fmt.Println(e.Value)
+//	}
+//
+// Every List, Element and method of container/list is here and behaves the
+// same, the zero List included. As with container/list, goroutines that share
+// a List must synchronize their use of it. AnyList returns the underlying
+// list, whose values are the Elements, e.g. to subscribe to it; it must only
+// be changed through the List.
+package list
+
+import (
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+// Element - An element of a List
+type Element struct {
+	// The value stored with this element
+	Value any
+
+	// the list the element belongs to, nil once it is removed
+	list *List
+	// the node of the element in list.l
+	handle *ds.Element[*Element]
+}
+
+// Next ...Returns the next list element or nil
+func (e *Element) Next() *Element {
+	if e.list == nil {
+		return nil
+	}
+	if next := e.handle.Next(); next != nil {
+		return next.Value()
+	}
+	return nil
+}
+
+// Prev ...Returns the previous list element or nil
+func (e *Element) Prev() *Element {
+	if e.list == nil {
+		return nil
+	}
+	if prev := e.handle.Prev(); prev != nil {
+		return prev.Value()
+	}
+	return nil
+}
+
+// List - A doubly linked list. The zero value is an empty list ready to use.
+type List struct {
+	l *ds.AnyList[*Element]
+}
+
+// New ...Returns an initialized list
+func New() *List {
+	return new(List).Init()
+}
+
+// Init ...Initializes or clears list l
+func (l *List) Init() *List {
+	if l.l == nil {
+		l.l = ds.NewAnyList[*Element]()
+		l.l.Equals = func(a, b *Element) bool { return a == b }
+		return l
+	}
+	for e := l.Front(); e != nil; {
+		next := e.Next()
+		e.list, e.handle = nil, nil
+		e = next
+	}
+	l.l.Clear()
+	return l
+}
+
+// lazyInit ...Initializes a zero List value
+func (l *List) lazyInit() {
+	if l.l == nil {
+		l.Init()
+	}
+}
+
+// AnyList ...Returns the ds.AnyList that holds the elements of l
+func (l *List) AnyList() *ds.AnyList[*Element] {
+	l.lazyInit()
+	return l.l
+}
+
+// Len ...Returns the number of elements of list l
+func (l *List) Len() int {
+	if l.l == nil {
+		return 0
+	}
+	return l.l.Count()
+}
+
+// Front ...Returns the first element of list l or nil if the list is empty
+func (l *List) Front() *Element {
+	if l.l == nil {
+		return nil
+	}
+	if front := l.l.Front(); front != nil {
+		return front.Value()
+	}
+	return nil
+}
+
+// Back ...Returns the last element of list l or nil if the list is empty
+func (l *List) Back() *Element {
+	if l.l == nil {
+		return nil
+	}
+	if back := l.l.Back(); back != nil {
+		return back.Value()
+	}
+	return nil
+}
+
+// Remove ...Removes e from l if e is an element of list l, and returns the element value e.Value
+func (l *List) Remove(e *Element) any {
+	if e.list == l {
+		l.l.RemoveElement(e.handle)
+		e.list, e.handle = nil, nil
+	}
+	return e.Value
+}
+
+// PushFront ...Inserts a new element e with value v at the front of list l and returns e
+func (l *List) PushFront(v any) *Element {
+	l.lazyInit()
+	e := &Element{Value: v, list: l}
+	e.handle = l.l.PushFront(e)
+	return e
+}
+
+// PushBack ...Inserts a new element e with value v at the back of list l and returns e
+func (l *List) PushBack(v any) *Element {
+	l.lazyInit()
+	e := &Element{Value: v, list: l}
+	e.handle = l.l.PushBack(e)
+	return e
+}
+
+// InsertBefore ...Inserts a new element e with value v immediately before mark and returns e.
+// If mark is not an element of l, the list is not modified.
+func (l *List) InsertBefore(v any, mark *Element) *Element {
+	if mark.list != l {
+		return nil
+	}
+	e := &Element{Value: v, list: l}
+	e.handle = l.l.InsertBefore(e, mark.handle)
+	return e
+}
+
+// InsertAfter ...Inserts a new element e with value v immediately after mark and returns e.
+// If mark is not an element of l, the list is not modified.
+func (l *List) InsertAfter(v any, mark *Element) *Element {
+	if mark.list != l {
+		return nil
+	}
+	e := &Element{Value: v, list: l}
+	e.handle = l.l.InsertAfter(e, mark.handle)
+	return e
+}
+
+// MoveToFront ...Moves element e to the front of list l. If e is not an element of l, the list is not modified.
+func (l *List) MoveToFront(e *Element) {
+	if e.list != l {
+		return
+	}
+	l.l.MoveToFront(e.handle)
+}
+
+// MoveToBack ...Moves element e to the back of list l. If e is not an element of l, the list is not modified.
+func (l *List) MoveToBack(e *Element) {
+	if e.list != l {
+		return
+	}
+	l.l.MoveToBack(e.handle)
+}
+
+// MoveBefore ...Moves element e to its new position before mark.
+// If e or mark is not an element of l, or e == mark, the list is not modified.
+func (l *List) MoveBefore(e, mark *Element) {
+	if e.list != l || mark.list != l || e == mark {
+		return
+	}
+	l.l.MoveBefore(e.handle, mark.handle)
+}
+
+// MoveAfter ...Moves element e to its new position after mark.
+// If e or mark is not an element of l, or e == mark, the list is not modified.
+func (l *List) MoveAfter(e, mark *Element) {
+	if e.list != l || mark.list != l || e == mark {
+		return
+	}
+	l.l.MoveAfter(e.handle, mark.handle)
+}
+
+// PushBackList ...Inserts a copy of another list at the back of list l. The lists l and other may be the same.
+func (l *List) PushBackList(other *List) {
+	l.lazyInit()
+	for i, e := other.Len(), other.Front(); i > 0; i, e = i-1, e.Next() {
+		l.PushBack(e.Value)
+	}
+}
+
+// PushFrontList ...Inserts a copy of another list at the front of list l. The lists l and other may be the same.
+func (l *List) PushFrontList(other *List) {
+	l.lazyInit()
+	for i, e := other.Len(), other.Back(); i > 0; i, e = i-1, e.Prev() {
+		l.PushFront(e.Value)
+	}
+}
@@ -0,0 +1,226 @@
+// Package client talks to a listd server; see package listd for the protocol.
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServerError - An error reply from the server
+type ServerError string
+
+func (e ServerError) Error() string { return "listd: " + string(e) }
+
+// Client - A connection to a listd server. It is safe for concurrent use;
+// commands are sent one at a time, so a BLPOP holds the connection until it returns.
+type Client struct {
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+// Dial ...Connects to the server at addr, e.g. Dial("tcp", "127.0.0.1:7070") or Dial("unix", "/tmp/listd.sock")
+func Dial(network string, addr string) (*Client, error) {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// NewClient ...Creates a Client that speaks over conn
+func NewClient(conn net.Conn) *Client {
+	return &Client{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+}
+
+// Close ...Closes the connection. A command still waiting for its reply, like a BLPop, fails.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Ping ...Checks that the server answers
+func (c *Client) Ping() error {
+	reply, err := c.do("PING")
+	if err != nil {
+		return err
+	}
+	return reply.ok()
+}
+
+// Push ...Adds values to the end of the list and returns its new length
+func (c *Client) Push(name string, values ...string) (int, error) {
+	if len(values) == 0 {
+		return 0, errors.New("listd: Push needs at least one value")
+	}
+	reply, err := c.do("PUSH", append([]string{name}, values...)...)
+	if err != nil {
+		return 0, err
+	}
+	return reply.int()
+}
+
+// Pop ...Removes the first value of the list and returns it; ok is false if the list was empty
+func (c *Client) Pop(name string) (val string, ok bool, err error) {
+	reply, err := c.do("POP", name)
+	if err != nil {
+		return "", false, err
+	}
+	return reply.value()
+}
+
+// BLPop ...Works like Pop, but waits up to timeout for a value while the list is empty. A timeout of 0 waits for ever.
+func (c *Client) BLPop(name string, timeout time.Duration) (val string, ok bool, err error) {
+	seconds := strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64)
+	reply, err := c.do("BLPOP", name, seconds)
+	if err != nil {
+		return "", false, err
+	}
+	return reply.value()
+}
+
+// Get ...Returns the value at index
+func (c *Client) Get(name string, index int) (string, error) {
+	reply, err := c.do("GET", name, strconv.Itoa(index))
+	if err != nil {
+		return "", err
+	}
+	val, _, err := reply.value()
+	return val, err
+}
+
+// Set ...Replaces the value at index
+func (c *Client) Set(name string, index int, val string) error {
+	reply, err := c.do("SET", name, strconv.Itoa(index), val)
+	if err != nil {
+		return err
+	}
+	return reply.ok()
+}
+
+// Len ...Returns the number of values in the list
+func (c *Client) Len(name string) (int, error) {
+	reply, err := c.do("LEN", name)
+	if err != nil {
+		return 0, err
+	}
+	return reply.int()
+}
+
+// Range ...Returns the values from index from to index to, excluded. A negative to stands for the end of the list.
+func (c *Client) Range(name string, from int, to int) ([]string, error) {
+	reply, err := c.do("RANGE", name, strconv.Itoa(from), strconv.Itoa(to))
+	if err != nil {
+		return nil, err
+	}
+	if err := reply.err(); err != nil {
+		return nil, err
+	}
+	return reply.values, nil
+}
+
+// reply - One reply from the server: its first line and, for *n, the values that follow it
+type reply struct {
+	line   string
+	values []string
+}
+
+// do ...Sends one command and reads its reply. Every argument is sent quoted, so values may hold anything.
+func (c *Client) do(cmd string, args ...string) (reply, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.w.WriteString(cmd)
+	for _, arg := range args {
+		c.w.WriteByte(' ')
+		c.w.WriteString(strconv.Quote(arg))
+	}
+	c.w.WriteByte('\n')
+	if err := c.w.Flush(); err != nil {
+		return reply{}, err
+	}
+
+	line, err := c.readLine()
+	if err != nil {
+		return reply{}, err
+	}
+	rep := reply{line: line}
+	if strings.HasPrefix(line, "*") {
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return reply{}, fmt.Errorf("listd: bad reply %q", line)
+		}
+		rep.values = make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			quoted, err := c.readLine()
+			if err != nil {
+				return reply{}, err
+			}
+			val, err := strconv.Unquote(quoted)
+			if err != nil {
+				return reply{}, fmt.Errorf("listd: bad value %q", quoted)
+			}
+			rep.values = append(rep.values, val)
+		}
+	}
+	return rep, nil
+}
+
+func (c *Client) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (rep reply) err() error {
+	if strings.HasPrefix(rep.line, "-") {
+		return ServerError(rep.line[1:])
+	}
+	return nil
+}
+
+func (rep reply) ok() error {
+	if err := rep.err(); err != nil {
+		return err
+	}
+	if rep.line != "OK" {
+		return fmt.Errorf("listd: bad reply %q", rep.line)
+	}
+	return nil
+}
+
+func (rep reply) int() (int, error) {
+	if err := rep.err(); err != nil {
+		return 0, err
+	}
+	if !strings.HasPrefix(rep.line, ":") {
+		return 0, fmt.Errorf("listd: bad reply %q", rep.line)
+	}
+	return strconv.Atoi(rep.line[1:])
+}
+
+// value ...Parses a $ reply; a _ reply is no value
+func (rep reply) value() (string, bool, error) {
+	if err := rep.err(); err != nil {
+		return "", false, err
+	}
+	if rep.line == "_" {
+		return "", false, nil
+	}
+	if !strings.HasPrefix(rep.line, "$") {
+		return "", false, fmt.Errorf("listd: bad reply %q", rep.line)
+	}
+	val, err := strconv.Unquote(rep.line[1:])
+	if err != nil {
+		return "", false, fmt.Errorf("listd: bad reply %q", rep.line)
+	}
+	return val, true, nil
+}
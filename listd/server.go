@@ -0,0 +1,355 @@
+package listd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gbenroscience/linkedlist/internal/cmdline"
+)
+
+// The protocol.
+//
+// A client sends one command per line: a command name followed by its
+// arguments, separated by spaces. An argument is either a bare word or a Go
+// string literal, e.g. "two words", which is how values holding spaces,
+// quotes or newlines are sent.
+//
+//	PUSH <name> <value>...     appends values, replies with the new length
+//	POP <name>                 removes the first value and replies with it
+//	BLPOP <name> <seconds>     like POP, but waits up to seconds for a value (0 waits for ever)
+//	GET <name> <index>         replies with the value at index
+//	SET <name> <index> <value> replaces the value at index
+//	LEN <name>                 replies with the length
+//	RANGE <name> <from> [<to>] replies with the values from index from to index to, excluded
+//	PING                       replies OK
+//	QUIT                       closes the connection
+//
+// Every command gets one reply, whose first character tells its kind:
+//
+//	OK                         done
+//	:<integer>                 a number
+//	$<Go string literal>       a value
+//	_                          no value: POP on an empty list, or BLPOP timed out
+//	*<n>                       n values follow, one Go string literal per line
+//	-<message>                 the command failed
+//
+// A line longer than MaxLineLength bytes is skipped and replied to with an
+// error. Lists are made the first time PUSH, POP or BLPOP names them; GET,
+// SET, LEN and RANGE see a list that was never made as an empty one.
+//
+// BLPOP delivers a value at most once. The value is removed from the list
+// before the reply is written, and a client that hangs up in between, or
+// whose connection drops before the reply reaches it, loses the value: a
+// successful write only means the reply reached the kernel, not the client.
+
+// MaxLineLength - The longest command line the server reads, newline included
+const MaxLineLength = 64 << 10
+
+// errLineTooLong - Replied to a line longer than MaxLineLength
+var errLineTooLong = fmt.Errorf("line longer than %d bytes", MaxLineLength)
+
+// Server - Serves the lists of a Store over net.Listeners
+type Server struct {
+	store *Store
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+
+	// cancelled when the server closes, to stop BLPOP
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewServer ...Creates a Server for the lists of store
+func NewServer(store *Store) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		store:     store,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// ErrServerClosed - Returned by Serve once Close has been called
+var ErrServerClosed = errors.New("listd: server closed")
+
+// Serve ...Accepts connections on l and serves each of them on its own goroutine, until Close is called
+func (srv *Server) Serve(l net.Listener) error {
+	srv.mu.Lock()
+	if srv.closed {
+		srv.mu.Unlock()
+		return ErrServerClosed
+	}
+	srv.listeners[l] = struct{}{}
+	srv.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			srv.mu.Lock()
+			closed := srv.closed
+			delete(srv.listeners, l)
+			srv.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		srv.mu.Lock()
+		if srv.closed {
+			srv.mu.Unlock()
+			conn.Close()
+			return ErrServerClosed
+		}
+		srv.conns[conn] = struct{}{}
+		srv.wg.Add(1)
+		srv.mu.Unlock()
+
+		go srv.serveConn(conn)
+	}
+}
+
+// Close ...Stops the listeners, closes every connection and waits for them to finish
+func (srv *Server) Close() error {
+	srv.mu.Lock()
+	srv.closed = true
+	for l := range srv.listeners {
+		l.Close()
+	}
+	for conn := range srv.conns {
+		conn.Close()
+	}
+	srv.mu.Unlock()
+
+	srv.cancel()
+	srv.wg.Wait()
+	return nil
+}
+
+func (srv *Server) serveConn(conn net.Conn) {
+	defer srv.wg.Done()
+	defer func() {
+		srv.mu.Lock()
+		delete(srv.conns, conn)
+		srv.mu.Unlock()
+		conn.Close()
+	}()
+
+	r := bufio.NewReaderSize(conn, MaxLineLength)
+	w := bufio.NewWriter(conn)
+	for {
+		line, err := readLine(r)
+		if err == errLineTooLong {
+			writeError(w, err)
+			if err := w.Flush(); err != nil {
+				return
+			}
+			continue
+		}
+		if err != nil {
+			return
+		}
+		args, err := cmdline.Split(strings.TrimRight(line, "\r\n"))
+		if err != nil {
+			writeError(w, err)
+		} else if len(args) > 0 {
+			if strings.EqualFold(args[0], "QUIT") {
+				writeOK(w)
+				w.Flush()
+				return
+			}
+			if err := srv.exec(conn, r, w, strings.ToUpper(args[0]), args[1:]); err != nil {
+				writeError(w, err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// readLine ...Reads a line of at most MaxLineLength bytes; a longer one is read to its end and dropped, and errLineTooLong returned
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadSlice('\n')
+	if err != bufio.ErrBufferFull {
+		return string(line), err
+	}
+	for err == bufio.ErrBufferFull {
+		_, err = r.ReadSlice('\n')
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", errLineTooLong
+}
+
+// watchHangUp ...Cancels ctx if the client hangs up while a command waits; stop must be called before reading from r again
+func watchHangUp(conn net.Conn, r *bufio.Reader, cancel context.CancelFunc) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Peek leaves a command the client sent in the meantime in the buffer
+		if _, err := r.Peek(1); err != nil && !errors.Is(err, os.ErrDeadlineExceeded) {
+			cancel()
+		}
+	}()
+	return func() {
+		conn.SetReadDeadline(time.Now())
+		<-done
+		conn.SetReadDeadline(time.Time{})
+	}
+}
+
+// exec ...Runs one command and writes its reply, or returns the error to reply with
+func (srv *Server) exec(conn net.Conn, r *bufio.Reader, w *bufio.Writer, cmd string, args []string) error {
+	if cmd == "PING" {
+		writeOK(w)
+		return nil
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("unknown command %q or missing list name", cmd)
+	}
+	name, args := args[0], args[1:]
+
+	switch cmd {
+	case "PUSH":
+		if len(args) == 0 {
+			return errors.New("PUSH needs at least one value")
+		}
+		writeInt(w, srv.store.Push(name, args...))
+
+	case "POP":
+		if err := wantArgs(cmd, args, 0, 0); err != nil {
+			return err
+		}
+		val, err := srv.store.Pop(name)
+		if err == ErrEmpty {
+			writeNil(w)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		writeValue(w, val)
+
+	case "BLPOP":
+		if err := wantArgs(cmd, args, 1, 1); err != nil {
+			return err
+		}
+		seconds, err := strconv.ParseFloat(args[0], 64)
+		if err != nil || seconds < 0 {
+			return fmt.Errorf("invalid timeout %q", args[0])
+		}
+		ctx, cancel := context.WithCancel(srv.ctx)
+		defer cancel()
+		if seconds > 0 {
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(seconds*float64(time.Second)))
+			defer cancel()
+		}
+		stop := watchHangUp(conn, r, cancel)
+		val, err := srv.store.WaitPop(ctx, name)
+		stop()
+		if err != nil {
+			if srv.ctx.Err() != nil {
+				return ErrServerClosed
+			}
+			writeNil(w)
+			return nil
+		}
+		// at most once: a client that hangs up after the pop loses the value
+		writeValue(w, val)
+
+	case "GET":
+		if err := wantArgs(cmd, args, 1, 1); err != nil {
+			return err
+		}
+		index, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+		val, err := srv.store.Get(name, index)
+		if err != nil {
+			return err
+		}
+		writeValue(w, val)
+
+	case "SET":
+		if err := wantArgs(cmd, args, 2, 2); err != nil {
+			return err
+		}
+		index, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+		if err := srv.store.Set(name, index, args[1]); err != nil {
+			return err
+		}
+		writeOK(w)
+
+	case "LEN":
+		if err := wantArgs(cmd, args, 0, 0); err != nil {
+			return err
+		}
+		writeInt(w, srv.store.Len(name))
+
+	case "RANGE":
+		if err := wantArgs(cmd, args, 1, 2); err != nil {
+			return err
+		}
+		from, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+		to := -1
+		if len(args) == 2 {
+			if to, err = strconv.Atoi(args[1]); err != nil {
+				return err
+			}
+		}
+		values, err := srv.store.Range(name, from, to)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "*%d\n", len(values))
+		for _, v := range values {
+			w.WriteString(strconv.Quote(v))
+			w.WriteByte('\n')
+		}
+
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+	return nil
+}
+
+func wantArgs(cmd string, args []string, min int, max int) error {
+	if len(args) < min || len(args) > max {
+		return fmt.Errorf("wrong number of arguments for %s", cmd)
+	}
+	return nil
+}
+
+func writeOK(w io.Writer)              { io.WriteString(w, "OK\n") }
+func writeNil(w io.Writer)             { io.WriteString(w, "_\n") }
+func writeInt(w io.Writer, n int)      { fmt.Fprintf(w, ":%d\n", n) }
+func writeValue(w io.Writer, v string) { io.WriteString(w, "$"+strconv.Quote(v)+"\n") }
+
+func writeError(w io.Writer, err error) {
+	// a message must stay on one line
+	io.WriteString(w, "-"+strings.ReplaceAll(err.Error(), "\n", " ")+"\n")
+}
@@ -0,0 +1,244 @@
+// Package listd shares named AnyList[string] instances between processes.
+//
+// A Store holds the lists and adds what sharing them needs on top of
+// package ds: an atomic pop from the front and ways to wait for values to
+// arrive. A Server serves a Store over TCP or a Unix socket with a line-based
+// text protocol, which the client package speaks; see server.go.
+package listd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+// ErrEmpty - Returned when popping from a list that holds no values
+var ErrEmpty = errors.New("the list is empty")
+
+// ErrIndex - Wrapped by the errors about an index that is out of range
+var ErrIndex = errors.New("index out of range")
+
+// entry - A named list and the channel that is closed when values are added to it
+type entry struct {
+	list *ds.AnyList[string]
+
+	mu    sync.Mutex
+	added chan struct{}
+}
+
+// wake ...Wakes everybody waiting for values
+func (e *entry) wake() {
+	e.mu.Lock()
+	close(e.added)
+	e.added = make(chan struct{})
+	e.mu.Unlock()
+}
+
+// waitChan ...Returns a channel that is closed the next time values are added
+func (e *entry) waitChan() <-chan struct{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.added
+}
+
+// Store - Named lists, made on first use. It is safe for concurrent use.
+type Store struct {
+	mu    sync.Mutex
+	lists map[string]*entry
+}
+
+// NewStore ...Creates an empty Store
+func NewStore() *Store {
+	return &Store{lists: make(map[string]*entry)}
+}
+
+func (s *Store) entry(name string) *entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.lists[name]
+	if !ok {
+		list := ds.NewAnyList[string]()
+		list.Equals = func(a, b string) bool { return a == b }
+		e = &entry{list: list, added: make(chan struct{})}
+		// values may be added through the list itself, not only through the store
+		list.Subscribe(func(ev ds.Event[string]) {
+			if ev.Kind == ds.EventInserted {
+				e.wake()
+			}
+		})
+		s.lists[name] = e
+	}
+	return e
+}
+
+// List ...Returns the list called name, making it if needed
+func (s *Store) List(name string) *ds.AnyList[string] {
+	return s.entry(name).list
+}
+
+// Lookup ...Returns the list called name, or false if no list of that name has been made
+func (s *Store) Lookup(name string) (*ds.AnyList[string], bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.lists[name]
+	if !ok {
+		return nil, false
+	}
+	return e.list, true
+}
+
+// Names ...Returns the names of the lists made so far
+func (s *Store) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.lists))
+	for name := range s.lists {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Push ...Adds values to the end of the list and returns its new length
+func (s *Store) Push(name string, values ...string) int {
+	var n int
+	s.List(name).Update(func(tx *ds.Tx[string]) error {
+		tx.AddValues(values...)
+		n = tx.Count()
+		return nil
+	})
+	return n
+}
+
+// Pop ...Removes the first value of the list and returns it, or returns ErrEmpty
+func (s *Store) Pop(name string) (string, error) {
+	var val string
+	err := s.List(name).Update(func(tx *ds.Tx[string]) error {
+		v, err := tx.Get(0)
+		if err != nil {
+			return ErrEmpty
+		}
+		val = v
+		return tx.RemoveIndex(0)
+	})
+	return val, err
+}
+
+// WaitPop ...Works like Pop, but waits for a value while the list is empty, until ctx is done
+func (s *Store) WaitPop(ctx context.Context, name string) (string, error) {
+	e := s.entry(name)
+	for {
+		// taken before trying, so a value added in between is not missed
+		added := e.waitChan()
+		val, err := s.Pop(name)
+		if err != ErrEmpty {
+			return val, err
+		}
+		select {
+		case <-added:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// WaitLen ...Waits until the list holds more than n values, or ctx is done, and returns its length
+func (s *Store) WaitLen(ctx context.Context, name string, n int) (int, error) {
+	e := s.entry(name)
+	for {
+		added := e.waitChan()
+		if size := e.list.Count(); size > n {
+			return size, nil
+		}
+		select {
+		case <-added:
+		case <-ctx.Done():
+			return e.list.Count(), ctx.Err()
+		}
+	}
+}
+
+// Set ...Replaces the value at index, or returns an error wrapping ErrIndex. It does not make a list that was never made.
+func (s *Store) Set(name string, index int, val string) error {
+	list, ok := s.Lookup(name)
+	if !ok {
+		return indexError(index, 0)
+	}
+	return list.Update(func(tx *ds.Tx[string]) error {
+		if n := tx.Count(); index < 0 || index >= n {
+			return indexError(index, n)
+		}
+		return tx.Set(index, val)
+	})
+}
+
+// Delete ...Removes the value at index and returns it, or returns an error wrapping ErrIndex. It does not make a list that was never made.
+func (s *Store) Delete(name string, index int) (string, error) {
+	list, ok := s.Lookup(name)
+	if !ok {
+		return "", indexError(index, 0)
+	}
+	var val string
+	err := list.Update(func(tx *ds.Tx[string]) error {
+		if n := tx.Count(); index < 0 || index >= n {
+			return indexError(index, n)
+		}
+		val, _ = tx.Get(index)
+		return tx.RemoveIndex(index)
+	})
+	return val, err
+}
+
+// Get ...Returns the value at index, or an error if it is out of range. A list that was never made reads as empty.
+func (s *Store) Get(name string, index int) (string, error) {
+	list, ok := s.Lookup(name)
+	if !ok {
+		return "", indexError(index, 0)
+	}
+	return list.Get(index)
+}
+
+// Len ...Returns the length of the list, 0 for a list that was never made
+func (s *Store) Len(name string) int {
+	list, ok := s.Lookup(name)
+	if !ok {
+		return 0
+	}
+	return list.Count()
+}
+
+// Range ...Returns the values of the list from index from to index to, excluded.
+// Indexes past the end are cut back to it; a negative to stands for the end. A list that was never made reads as empty.
+func (s *Store) Range(name string, from int, to int) ([]string, error) {
+	values := []string{}
+	n := 0
+	if list, ok := s.Lookup(name); ok {
+		// walks the list no further than to, rather than copying all of it
+		list.ForEach(func(val string) bool {
+			if to >= 0 && n >= to {
+				return false
+			}
+			if n >= from {
+				values = append(values, val)
+			}
+			n++
+			return true
+		})
+	}
+	if to < 0 || to > n {
+		to = n
+	}
+	if from < 0 || from > to {
+		return nil, fmt.Errorf("invalid range %d to %d", from, to)
+	}
+	return values, nil
+}
+
+func indexError(index int, size int) error {
+	return fmt.Errorf("%w: %d is out of range for %d values", ErrIndex, index, size)
+}
@@ -0,0 +1,196 @@
+// Command benchcmp runs the list benchmarks and compares them with a stored baseline.
+//
+// Run it from the root of the module:
+//
+//	go run ./main/benchcmp            # compare, exit status 1 on a regression
+//	go run ./main/benchcmp -update    # store the current results as the baseline
+//
+// A benchmark regresses when its time per operation grows by more than
+// -threshold, or when it allocates more often than it did in the baseline.
+// Timings depend on the machine, so the baseline should be updated on the
+// machine the comparison runs on.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Result - The measurements of one benchmark, e.g. "Get/List"
+type Result struct {
+	NsPerOp     float64 `json:"nsPerOp"`
+	BytesPerOp  int64   `json:"bytesPerOp"`
+	AllocsPerOp int64   `json:"allocsPerOp"`
+}
+
+// Baseline - The contents of the baseline file
+type Baseline struct {
+	GoVersion  string            `json:"goVersion"`
+	GOOS       string            `json:"goos"`
+	GOARCH     string            `json:"goarch"`
+	Benchmarks map[string]Result `json:"benchmarks"`
+}
+
+const defaultBenchmarks = `^Benchmark(Add|AddVal|Get|IndexOf|Remove|SubList|ForEach)$`
+
+func main() {
+	baselinePath := flag.String("baseline", "main/benchcmp/baseline.json", "the baseline file")
+	update := flag.Bool("update", false, "write the results to the baseline file instead of comparing")
+	threshold := flag.Float64("threshold", 0.2, "the growth in ns/op, as a fraction, that counts as a regression")
+	bench := flag.String("bench", defaultBenchmarks, "the benchmarks to run, as for go test -bench")
+	count := flag.Int("count", 3, "the number of runs of each benchmark; the fastest one is kept")
+	input := flag.String("input", "", "read go test -bench output from this file instead of running the benchmarks")
+	flag.Parse()
+
+	out, err := benchOutput(*input, *bench, *count)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "benchcmp:", err)
+		os.Exit(2)
+	}
+	results := parse(bytes.NewReader(out))
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "benchcmp: no benchmark results in the output:")
+		os.Stderr.Write(out)
+		os.Exit(2)
+	}
+
+	if *update {
+		if err := writeBaseline(*baselinePath, results); err != nil {
+			fmt.Fprintln(os.Stderr, "benchcmp:", err)
+			os.Exit(2)
+		}
+		fmt.Printf("wrote %d results to %s\n", len(results), *baselinePath)
+		return
+	}
+
+	baseline, err := readBaseline(*baselinePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "benchcmp:", err)
+		os.Exit(2)
+	}
+	if compare(os.Stdout, baseline, results, *threshold) > 0 {
+		os.Exit(1)
+	}
+}
+
+// benchOutput ...Runs the benchmarks of the tests package, or reads the output of an earlier run from input
+func benchOutput(input string, bench string, count int) ([]byte, error) {
+	if input != "" {
+		return os.ReadFile(input)
+	}
+	cmd := exec.Command("go", "test", "-run", "^$", "-bench", bench, "-benchmem",
+		"-count", strconv.Itoa(count), "./tests/")
+	cmd.Stderr = os.Stderr
+	return cmd.Output()
+}
+
+// benchLine matches e.g. "BenchmarkGet/List-8   2000   509.2 ns/op   0 B/op   0 allocs/op"
+var benchLine = regexp.MustCompile(`^Benchmark(\S+?)(?:-\d+)?\s+\d+\s+([\d.]+) ns/op(?:\s+(\d+) B/op\s+(\d+) allocs/op)?`)
+
+// parse ...Reads go test -bench output, keeping the fastest run of every benchmark
+func parse(r io.Reader) map[string]Result {
+	results := make(map[string]Result)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m := benchLine.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		var res Result
+		res.NsPerOp, _ = strconv.ParseFloat(m[2], 64)
+		res.BytesPerOp, _ = strconv.ParseInt(m[3], 10, 64)
+		res.AllocsPerOp, _ = strconv.ParseInt(m[4], 10, 64)
+
+		if old, ok := results[m[1]]; !ok || res.NsPerOp < old.NsPerOp {
+			results[m[1]] = res
+		}
+	}
+	return results
+}
+
+// compare ...Prints the results next to the baseline and returns the number of regressions
+func compare(w io.Writer, baseline *Baseline, results map[string]Result, threshold float64) int {
+	if baseline.GOOS != runtime.GOOS || baseline.GOARCH != runtime.GOARCH {
+		fmt.Fprintf(w, "note: the baseline was taken on %s/%s\n", baseline.GOOS, baseline.GOARCH)
+	}
+
+	regressions := 0
+	fmt.Fprintf(w, "%-28s %12s %12s %8s %14s\n", "benchmark", "old ns/op", "new ns/op", "delta", "allocs/op")
+	for _, name := range sortedNames(results) {
+		res := results[name]
+		old, ok := baseline.Benchmarks[name]
+		if !ok {
+			fmt.Fprintf(w, "%-28s %12s %12.1f %8s %14d  new\n", name, "-", res.NsPerOp, "", res.AllocsPerOp)
+			continue
+		}
+
+		delta := 0.0
+		if old.NsPerOp > 0 {
+			delta = res.NsPerOp/old.NsPerOp - 1
+		}
+		var flags []string
+		if delta > threshold {
+			flags = append(flags, "SLOWER")
+		}
+		if res.AllocsPerOp > old.AllocsPerOp {
+			flags = append(flags, "MORE ALLOCS")
+		}
+		if len(flags) > 0 {
+			regressions++
+		}
+		allocs := fmt.Sprintf("%d -> %d", old.AllocsPerOp, res.AllocsPerOp)
+		line := fmt.Sprintf("%-28s %12.1f %12.1f %+7.1f%% %14s  %s", name, old.NsPerOp, res.NsPerOp, delta*100, allocs, strings.Join(flags, ", "))
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+
+	if regressions > 0 {
+		fmt.Fprintf(w, "%d regression(s) beyond %.0f%%\n", regressions, threshold*100)
+	}
+	return regressions
+}
+
+func sortedNames(results map[string]Result) []string {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w (create it with -update)", err)
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &baseline, nil
+}
+
+func writeBaseline(path string, results map[string]Result) error {
+	baseline := Baseline{
+		GoVersion:  runtime.Version(),
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		Benchmarks: results,
+	}
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
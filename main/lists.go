@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+// replList - What the REPL does with a list, whatever its type.
+// Every list holds strings; CList holds them as interface{} values.
+type replList interface {
+	add(values ...string)
+	insert(index int, val string) error
+	remove(index int) error
+	set(index int, val string) error
+	get(index int) (string, error)
+	subList(start int, end int) (replList, error)
+	values() []string
+	// setAll ...Overwrites the values in order, as a single step of the undo history if there is one
+	setAll(name string, values []string) error
+	undo() error
+	redo() error
+	validate() error
+	writeDOT(w io.Writer) error
+	kind() string
+}
+
+var (
+	errNoHistory = errors.New("a CList keeps no undo history")
+	errNoUndo    = errors.New("nothing to undo")
+	errNoRedo    = errors.New("nothing to redo")
+)
+
+func errIndex(index int, size int) error {
+	return fmt.Errorf("index %d is out of range for %d values", index, size)
+}
+
+// stringList - The methods List[string] and AnyList[string] have in common
+type stringList[L any] interface {
+	AddValues(args ...string)
+	AddVal(val string, index int) bool
+	RemoveIndex(index int) bool
+	Set(index int, val string)
+	Get(index int) (string, error)
+	SubList(start int, end int) (L, error)
+	ToArray() []string
+	Count() int
+	BeginGroup(name string)
+	EndGroup() error
+	Undo() bool
+	Redo() bool
+	Validate() error
+	WriteDOT(w io.Writer) error
+	fmt.Formatter
+}
+
+type typedList[L stringList[L]] struct {
+	l        L
+	typeName string
+}
+
+func (t typedList[L]) add(values ...string) { t.l.AddValues(values...) }
+func (t typedList[L]) values() []string     { return t.l.ToArray() }
+func (t typedList[L]) validate() error      { return t.l.Validate() }
+func (t typedList[L]) kind() string         { return t.typeName }
+
+func (t typedList[L]) writeDOT(w io.Writer) error    { return t.l.WriteDOT(w) }
+func (t typedList[L]) Format(f fmt.State, verb rune) { t.l.Format(f, verb) }
+
+func (t typedList[L]) insert(index int, val string) error {
+	if n := t.l.Count(); index < 0 || index > n {
+		return errIndex(index, n)
+	}
+	t.l.AddVal(val, index)
+	return nil
+}
+
+func (t typedList[L]) remove(index int) error {
+	if !t.l.RemoveIndex(index) {
+		return errIndex(index, t.l.Count())
+	}
+	return nil
+}
+
+func (t typedList[L]) set(index int, val string) error {
+	if n := t.l.Count(); index < 0 || index >= n {
+		return errIndex(index, n)
+	}
+	t.l.Set(index, val)
+	return nil
+}
+
+func (t typedList[L]) get(index int) (string, error) {
+	return t.l.Get(index)
+}
+
+func (t typedList[L]) subList(start int, end int) (replList, error) {
+	sub, err := t.l.SubList(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return typedList[L]{sub, t.typeName}, nil
+}
+
+func (t typedList[L]) setAll(name string, values []string) error {
+	t.l.BeginGroup(name)
+	for i, v := range values {
+		t.l.Set(i, v)
+	}
+	// lists made by the REPL always have a history
+	return t.l.EndGroup()
+}
+
+func (t typedList[L]) undo() error {
+	if !t.l.Undo() {
+		return errNoUndo
+	}
+	return nil
+}
+
+func (t typedList[L]) redo() error {
+	if !t.l.Redo() {
+		return errNoRedo
+	}
+	return nil
+}
+
+type cList struct {
+	l *ds.CList
+}
+
+func (c cList) kind() string                  { return "clist" }
+func (c cList) validate() error               { return c.l.Validate() }
+func (c cList) writeDOT(w io.Writer) error    { return c.l.WriteDOT(w) }
+func (c cList) undo() error                   { return errNoHistory }
+func (c cList) redo() error                   { return errNoHistory }
+func (c cList) Format(f fmt.State, verb rune) { c.l.Format(f, verb) }
+
+func (c cList) add(values ...string) {
+	for _, v := range values {
+		c.l.Add(v)
+	}
+}
+
+func (c cList) insert(index int, val string) error {
+	if n := c.l.Count(); index < 0 || index > n {
+		return errIndex(index, n)
+	}
+	c.l.AddVal(val, index)
+	return nil
+}
+
+func (c cList) remove(index int) error {
+	if !c.l.RemoveIndex(index) {
+		return errIndex(index, c.l.Count())
+	}
+	return nil
+}
+
+func (c cList) set(index int, val string) error {
+	if n := c.l.Count(); index < 0 || index >= n {
+		return errIndex(index, n)
+	}
+	c.l.Set(index, val)
+	return nil
+}
+
+func (c cList) get(index int) (string, error) {
+	val, err := c.l.Get(index)
+	if err != nil {
+		return "", err
+	}
+	return val.(string), nil
+}
+
+func (c cList) subList(start int, end int) (replList, error) {
+	sub, err := c.l.SubList(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return cList{sub}, nil
+}
+
+func (c cList) values() []string {
+	var values []string
+	for _, v := range c.l.ToArray() {
+		values = append(values, v.(string))
+	}
+	return values
+}
+
+func (c cList) setAll(name string, values []string) error {
+	for i, v := range values {
+		c.l.Set(i, v)
+	}
+	return nil
+}
+
+// newList ...Creates an empty list of the given kind: list, anylist or clist
+func newList(kind string) (replList, error) {
+	switch kind {
+	case "", "list":
+		return typedList[*ds.List[string]]{ds.NewList[string]().WithHistory(0), "list"}, nil
+	case "anylist":
+		l := ds.NewAnyList[string]().WithHistory(0)
+		l.Equals = func(a, b string) bool { return a == b }
+		return typedList[*ds.AnyList[string]]{l, "anylist"}, nil
+	case "clist":
+		return cList{ds.NewCList()}, nil
+	}
+	return nil, fmt.Errorf("unknown list type %q: use list, anylist or clist", kind)
+}
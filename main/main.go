@@ -1,87 +1,54 @@
+// Command linkedlist is a REPL for trying out List, AnyList and CList, e.g. to reproduce a sublist bug step by step.
+//
+//	go build -o linkedlist ./main
+//	./linkedlist                     # interactive
+//	./linkedlist examples/demo.txt   # run a script, echoing each command
+//	./linkedlist < steps.txt         # the same, from standard input
+//
+// A script stops at its first failing command and the exit status is 1, so a
+// script attached to an issue shows where things go wrong. Type help for the
+// list of commands.
 package main
 
 import (
+	"flag"
 	"fmt"
-
-	"github.com/gbenroscience/linkedlist/ds"
+	"os"
 )
 
-func runComparableListExamples() {
-
-	list := ds.NewList[int]()
-	list.Add(2)
-	list.Add(4)
-	list.Add(6)
-
-	list.Log("Check-Contents-1")
-	list.Add(8)
-	list.Log("Check-Contents-2")
-	list.AddVal(3, 1)
-	list.Log("Check-Contents-3")
-
-	list.Set(0, 10000)
-	list.Log("Check-Contents-4")
-
-	var a []int = []int{20, 40, 60, 80, 100}
-	list.AddArray(a)
-	list.Log("Check-Contents-5")
-
-	subList, err := list.SubList(3, 8)
-	if err != nil {
-		fmt.Printf("err: %v\n", err)
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: linkedlist [script...]")
+		fmt.Fprintln(flag.CommandLine.Output(), helpText)
 	}
-	subList.Log("Check-sublist-contents-1")
-	subList.RemoveIndex(2)
-	subList.RemoveIndex(2)
-	subList.Log("Check-sublist-contents-2")
-	list.Log("Check-Contents-6")
-
-	subList.AddValues(200, 250, 300, 350, 400)
-	subList.Log("Check-sublist-contents-3")
-	list.Log("Check-Contents-7")
-}
-
-func runAnyListExamples() {
-
-	list := ds.NewAnyList[int]()
-	list.Equals = func(val1, val2 int) bool {
-		return val1 == val2
+	flag.Parse()
+
+	s := newSession(os.Stdout)
+
+	if flag.NArg() > 0 {
+		for _, path := range flag.Args() {
+			f, err := os.Open(path)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "linkedlist:", err)
+				os.Exit(1)
+			}
+			err = s.run(f, true, false)
+			f.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "linkedlist: %s: %v\n", path, err)
+				os.Exit(1)
+			}
+		}
+		return
 	}
-	list.Add(2)
-	list.Add(4)
-	list.Add(6)
-
-	list.Log("Check-Contents-1")
-	list.Add(8)
-	list.Log("Check-Contents-2")
-	list.AddVal(3, 1)
-	list.Log("Check-Contents-3")
-
-	list.Set(0, 10000)
-	list.Log("Check-Contents-4")
 
-	var a []int = []int{20, 40, 60, 80, 100}
-	list.AddArray(a)
-	list.Log("Check-Contents-5")
-
-	subList, err := list.SubList(3, 8)
-	if err != nil {
-		fmt.Printf("err: %v\n", err)
+	interactive := false
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		interactive = true
+		fmt.Println("linkedlist REPL, type help for the commands")
+	}
+	if err := s.run(os.Stdin, !interactive, interactive); err != nil {
+		fmt.Fprintln(os.Stderr, "linkedlist:", err)
+		os.Exit(1)
 	}
-	subList.Log("Check-sublist-contents-1")
-	subList.RemoveIndex(2)
-	subList.RemoveIndex(2)
-	subList.Log("Check-sublist-contents-2")
-	list.Log("Check-Contents-6")
-
-	subList.AddValues(200, 250, 300, 350, 400)
-	subList.Log("Check-sublist-contents-3")
-	list.Log("Check-Contents-7")
-}
-
-func main() {
-	fmt.Println("----------------COMPARABLE LIST EXAMPLES----------------")
-	runAnyListExamples()
-	fmt.Println("----------------ANY LIST EXAMPLES----------------")
-	runComparableListExamples()
 }
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// runScript ...Runs script through a new session and returns what it printed and the error run returned
+func runScript(t *testing.T, script string, asScript bool) (string, error) {
+	t.Helper()
+	var out strings.Builder
+	err := newSession(&out).run(strings.NewReader(script), asScript, false)
+	return out.String(), err
+}
+
+func TestREPL(t *testing.T) {
+	out, err := runScript(t, `
+new xs anylist
+add xs 3 1 "two words" 2   # a comment
+sort xs
+get xs 9
+undo xs
+sublist xs 1 3 ys
+remove ys 0
+log
+undo ys
+log xs
+redo xs
+validate xs
+new c clist
+undo c
+new xs
+bogus xs
+`, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `xs: [3 1 two words 2]
+xs: [1 2 3 two words]
+error: Index=(9) > list-size=(4) is not allowed
+xs: [3 1 two words 2]
+ys: [1 two words]
+ys: [two words]
+xs (anylist): [3 two words 2] (len 3, 1 live sublist)
+ys (anylist): [two words] (len 1, sublist of 3)
+ys: [two words]
+xs (anylist): [3 1 two words 2] (len 4, 1 live sublist)
+xs: [3 two words 2]
+ok
+error: a CList keeps no undo history
+error: a list named "xs" already exists
+error: unknown command "bogus", try help
+`
+	if out != want {
+		t.Fatalf("the session printed\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestREPLScriptStopsAtFirstError(t *testing.T) {
+	out, err := runScript(t, `new xs
+add xs a b
+# a comment is not echoed
+remove xs 5
+add xs c
+`, true)
+	if err == nil || !strings.HasPrefix(err.Error(), "line 4: remove xs 5: ") {
+		t.Fatalf("run returned %v, want the error of line 4", err)
+	}
+
+	want := `> new xs
+> add xs a b
+xs: [a b]
+> remove xs 5
+`
+	if out != want {
+		t.Fatalf("the script printed\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestREPLQuitAndBadQuotes(t *testing.T) {
+	s := newSession(&strings.Builder{})
+	if err := s.exec("quit"); err != errQuit {
+		t.Fatalf("quit returned %v", err)
+	}
+	if err := s.exec(`add "unterminated`); err == nil {
+		t.Fatal("a bad quote returned no error")
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gbenroscience/linkedlist/internal/cmdline"
+)
+
+const helpText = `commands:
+  new <name> [list|anylist|clist]    create an empty list (list by default)
+  add <name> <value>...              append values
+  insert <name> <index> <value>      insert a value before index
+  remove <name> <index>              remove the value at index
+  set <name> <index> <value>         replace the value at index
+  get <name> <index>                 print the value at index
+  sublist <name> <start> <end> <new> name the values from start to end (excluded) as a new list
+  sort <name>                        sort the values, numbers by value, everything else as text
+  log [<name>...]                    print the lists, all of them by default
+  undo <name>                        revert the latest change to the list or the list it was taken from
+  redo <name>                        reapply the latest change undone
+  validate <name>                    check the list's links and sizes
+  dot <name>                         print the list's nodes as a Graphviz digraph
+  help                               print this text
+  quit                               leave
+Values are words or "quoted strings". Everything after a # is a comment.`
+
+var errQuit = errors.New("quit")
+
+// session - The named lists of a REPL run and where its output goes
+type session struct {
+	lists map[string]replList
+	// names in the order the lists were made, for log
+	names []string
+	out   io.Writer
+}
+
+func newSession(out io.Writer) *session {
+	return &session{lists: make(map[string]replList), out: out}
+}
+
+// run ...Executes the commands read from in. A script stops at the first error, which is returned with its line number;
+// interactive input reports errors and carries on.
+func (s *session) run(in io.Reader, script bool, prompt bool) error {
+	scanner := bufio.NewScanner(in)
+	for line := 1; ; line++ {
+		if prompt {
+			fmt.Fprint(s.out, "> ")
+		}
+		if !scanner.Scan() {
+			break
+		}
+		text := scanner.Text()
+		if script && strings.TrimSpace(stripComment(text)) != "" {
+			fmt.Fprintln(s.out, ">", text)
+		}
+
+		err := s.exec(text)
+		if err == errQuit {
+			return nil
+		}
+		if err != nil {
+			if script {
+				return fmt.Errorf("line %d: %s: %w", line, strings.TrimSpace(text), err)
+			}
+			fmt.Fprintln(s.out, "error:", err)
+		}
+	}
+	return scanner.Err()
+}
+
+// exec ...Runs one command. A list that panics is reported as an error, so a bug can be reproduced without losing the session.
+func (s *session) exec(line string) (err error) {
+	args, err := cmdline.Split(stripComment(line))
+	if err != nil || len(args) == 0 {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "help":
+		fmt.Fprintln(s.out, helpText)
+		return nil
+	case "quit", "exit":
+		return errQuit
+	case "new":
+		if err := wantArgs(args, 1, 2); err != nil {
+			return err
+		}
+		kind := ""
+		if len(args) == 2 {
+			kind = args[1]
+		}
+		l, err := newList(kind)
+		if err != nil {
+			return err
+		}
+		return s.define(args[0], l)
+	case "log":
+		names := args
+		if len(names) == 0 {
+			names = s.names
+		}
+		for _, name := range names {
+			l, err := s.list(name)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(s.out, "%s (%s): %+v\n", name, l.kind(), l)
+		}
+		return nil
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("unknown command %q, try help", cmd)
+	}
+	l, err := s.list(args[0])
+	if err != nil {
+		return err
+	}
+	name, args := args[0], args[1:]
+
+	switch cmd {
+	case "add":
+		if len(args) == 0 {
+			return errors.New("add needs at least one value")
+		}
+		l.add(args...)
+	case "insert":
+		if err := wantArgs(args, 2, 2); err != nil {
+			return err
+		}
+		index, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+		if err := l.insert(index, args[1]); err != nil {
+			return err
+		}
+	case "remove":
+		index, err := indexArg(args)
+		if err != nil {
+			return err
+		}
+		if err := l.remove(index); err != nil {
+			return err
+		}
+	case "set":
+		if err := wantArgs(args, 2, 2); err != nil {
+			return err
+		}
+		index, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+		if err := l.set(index, args[1]); err != nil {
+			return err
+		}
+	case "get":
+		index, err := indexArg(args)
+		if err != nil {
+			return err
+		}
+		val, err := l.get(index)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(s.out, val)
+		return nil
+	case "sublist":
+		if err := wantArgs(args, 3, 3); err != nil {
+			return err
+		}
+		start, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+		end, err := strconv.Atoi(args[1])
+		if err != nil {
+			return err
+		}
+		sub, err := l.subList(start, end)
+		if err != nil {
+			return err
+		}
+		if err := s.define(args[2], sub); err != nil {
+			return err
+		}
+		name, l = args[2], sub
+	case "sort":
+		values := l.values()
+		slices.SortStableFunc(values, compareValues)
+		if err := l.setAll("sort", values); err != nil {
+			return err
+		}
+	case "undo":
+		if err := l.undo(); err != nil {
+			return err
+		}
+	case "redo":
+		if err := l.redo(); err != nil {
+			return err
+		}
+	case "validate":
+		if err := l.validate(); err != nil {
+			return err
+		}
+		fmt.Fprintln(s.out, "ok")
+		return nil
+	case "dot":
+		return l.writeDOT(s.out)
+	default:
+		return fmt.Errorf("unknown command %q, try help", cmd)
+	}
+
+	fmt.Fprintf(s.out, "%s: %v\n", name, l)
+	return nil
+}
+
+func (s *session) list(name string) (replList, error) {
+	l, ok := s.lists[name]
+	if !ok {
+		return nil, fmt.Errorf("no list named %q", name)
+	}
+	return l, nil
+}
+
+func (s *session) define(name string, l replList) error {
+	if _, ok := s.lists[name]; ok {
+		return fmt.Errorf("a list named %q already exists", name)
+	}
+	s.lists[name] = l
+	s.names = append(s.names, name)
+	return nil
+}
+
+// compareValues ...Orders numbers by value and before text, and text as text
+func compareValues(a string, b string) int {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(x, y)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func wantArgs(args []string, min int, max int) error {
+	if len(args) < min || len(args) > max {
+		return fmt.Errorf("got %d argument(s), want %d", len(args), min)
+	}
+	return nil
+}
+
+func indexArg(args []string) (int, error) {
+	if err := wantArgs(args, 1, 1); err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(args[0])
+}
+
+// stripComment ...Drops everything after a # that is not inside quotes
+func stripComment(line string) string {
+	quoted := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case '#':
+			if !quoted {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+var allocModes = []struct {
+	name string
+	mode ds.AllocMode
+}{
+	{"default", ds.AllocDefault},
+	{"freeList", ds.AllocFreeList},
+	{"pool", ds.AllocPool},
+	{"arena", ds.AllocArena},
+}
+
+// churn ...Pushes to the back and pops from the front, the way a queue is used
+func churn(list *ds.List[int], i int) {
+	list.Add(i)
+	list.RemoveIndex(0)
+}
+
+func TestAllocatorsRecycleNodes(t *testing.T) {
+	for _, m := range allocModes {
+		list := ds.NewList[int]().WithAllocator(m.mode)
+		for i := -100; i < 0; i++ {
+			list.Add(i)
+		}
+
+		i := 0
+		allocs := testing.AllocsPerRun(1000, func() {
+			churn(list, i)
+			i++
+		})
+
+		switch m.mode {
+		case ds.AllocDefault:
+			if allocs < 1 {
+				t.Fatalf("%s: %v allocations per Add, want at least 1", m.name, allocs)
+			}
+		case ds.AllocPool:
+			// a GC cycle may empty the pool, so allow for the odd allocation
+			if allocs > 0.1 {
+				t.Fatalf("%s: %v allocations per Add, want about 0", m.name, allocs)
+			}
+		default:
+			if allocs != 0 {
+				t.Fatalf("%s: %v allocations per Add, want 0", m.name, allocs)
+			}
+		}
+
+		if list.Count() != 100 {
+			t.Fatalf("%s: Count() = %d, want 100", m.name, list.Count())
+		}
+		// AllocsPerRun makes one extra warm-up run, so read the count back from i
+		if first, _ := list.Get(0); first != i-100 {
+			t.Fatalf("%s: Get(0) = %d, want %d", m.name, first, i-100)
+		}
+	}
+}
+
+func TestArenaAllocatesInSlabs(t *testing.T) {
+	allocs := testing.AllocsPerRun(10, func() {
+		list := ds.NewAnyList[int]().WithAllocator(ds.AllocArena)
+		for i := 0; i < 1024; i++ {
+			list.Add(i)
+		}
+	})
+	// the list, its Equals function, the allocator and 4 slabs of 256 nodes
+	if allocs > 10 {
+		t.Fatalf("%v allocations for 1024 elements, want at most 10", allocs)
+	}
+}
+
+func TestCompactKeepsListIntact(t *testing.T) {
+	for _, m := range allocModes {
+		list := ds.NewAnyList[int]().WithAllocator(m.mode)
+		for i := 0; i < 1000; i++ {
+			list.Add(i)
+		}
+		for i := 0; i < 900; i++ {
+			list.RemoveIndex(0)
+		}
+		list.Compact()
+		for i := 0; i < 10; i++ {
+			list.Add(-i)
+		}
+
+		if list.Count() != 110 {
+			t.Fatalf("%s: Count() = %d, want 110", m.name, list.Count())
+		}
+		if v, _ := list.Get(0); v != 900 {
+			t.Fatalf("%s: Get(0) = %d, want 900", m.name, v)
+		}
+		if v, _ := list.Get(109); v != -9 {
+			t.Fatalf("%s: Get(109) = %d, want -9", m.name, v)
+		}
+	}
+}
+
+func BenchmarkQueueChurn(b *testing.B) {
+	for _, m := range allocModes {
+		b.Run(m.name, func(b *testing.B) {
+			list := ds.NewList[int]().WithAllocator(m.mode)
+			for i := 0; i < 1000; i++ {
+				list.Add(i)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				churn(list, i)
+			}
+		})
+	}
+}
+
+func BenchmarkFill(b *testing.B) {
+	for _, m := range allocModes {
+		b.Run(m.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				list := ds.NewList[int]().WithAllocator(m.mode)
+				for j := 0; j < 10000; j++ {
+					list.Add(j)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,427 @@
+package tests
+
+import (
+	"container/list"
+	"slices"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+// The benchmarks below run every operation on CList, List and AnyList, and on
+// container/list and a slice for comparison, all holding benchSize ints.
+// main/benchcmp runs them and compares the results with a stored baseline:
+//
+//	go run ./main/benchcmp
+
+const benchSize = 1000
+
+func benchCList() *ds.CList {
+	l := ds.NewCList()
+	for i := 0; i < benchSize; i++ {
+		l.Add(i)
+	}
+	return l
+}
+
+func benchList() *ds.List[int] {
+	l := ds.NewList[int]()
+	for i := 0; i < benchSize; i++ {
+		l.Add(i)
+	}
+	return l
+}
+
+func benchAnyList() *ds.AnyList[int] {
+	l := newIntAnyList()
+	for i := 0; i < benchSize; i++ {
+		l.Add(i)
+	}
+	return l
+}
+
+func benchContainerList() *list.List {
+	l := list.New()
+	for i := 0; i < benchSize; i++ {
+		l.PushBack(i)
+	}
+	return l
+}
+
+func benchSlice() []int {
+	s := make([]int, 0, benchSize)
+	for i := 0; i < benchSize; i++ {
+		s = append(s, i)
+	}
+	return s
+}
+
+// findElement ...Returns the first element of l holding val, the way IndexOf looks for it
+func findElement(l *list.List, val int) *list.Element {
+	for e := l.Front(); e != nil; e = e.Next() {
+		if e.Value.(int) == val {
+			return e
+		}
+	}
+	return nil
+}
+
+// elementAt ...Walks to the element at index from the nearer end, the way Get does
+func elementAt(l *list.List, index int) *list.Element {
+	if index < l.Len()/2 {
+		e := l.Front()
+		for ; index > 0; index-- {
+			e = e.Next()
+		}
+		return e
+	}
+	e := l.Back()
+	for i := l.Len() - 1; i > index; i-- {
+		e = e.Prev()
+	}
+	return e
+}
+
+// BenchmarkAdd ...Appends to a list that grows with b.N
+func BenchmarkAdd(b *testing.B) {
+	b.Run("CList", func(b *testing.B) {
+		b.ReportAllocs()
+		l := ds.NewCList()
+		for n := 0; n < b.N; n++ {
+			l.Add(n)
+		}
+	})
+	b.Run("List", func(b *testing.B) {
+		b.ReportAllocs()
+		l := ds.NewList[int]()
+		for n := 0; n < b.N; n++ {
+			l.Add(n)
+		}
+	})
+	b.Run("AnyList", func(b *testing.B) {
+		b.ReportAllocs()
+		l := ds.NewAnyList[int]()
+		for n := 0; n < b.N; n++ {
+			l.Add(n)
+		}
+	})
+	b.Run("container/list", func(b *testing.B) {
+		b.ReportAllocs()
+		l := list.New()
+		for n := 0; n < b.N; n++ {
+			l.PushBack(n)
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		b.ReportAllocs()
+		var s []int
+		for n := 0; n < b.N; n++ {
+			s = append(s, n)
+		}
+	})
+}
+
+// BenchmarkAddVal ...Inserts in the middle of a list, starting over from benchSize values every benchSize inserts
+func BenchmarkAddVal(b *testing.B) {
+	const mid = benchSize / 2
+
+	b.Run("CList", func(b *testing.B) {
+		b.ReportAllocs()
+		var l *ds.CList
+		for n := 0; n < b.N; n++ {
+			if n%benchSize == 0 {
+				b.StopTimer()
+				l = benchCList()
+				b.StartTimer()
+			}
+			l.AddVal(n, mid)
+		}
+	})
+	b.Run("List", func(b *testing.B) {
+		b.ReportAllocs()
+		var l *ds.List[int]
+		for n := 0; n < b.N; n++ {
+			if n%benchSize == 0 {
+				b.StopTimer()
+				l = benchList()
+				b.StartTimer()
+			}
+			l.AddVal(n, mid)
+		}
+	})
+	b.Run("AnyList", func(b *testing.B) {
+		b.ReportAllocs()
+		var l *ds.AnyList[int]
+		for n := 0; n < b.N; n++ {
+			if n%benchSize == 0 {
+				b.StopTimer()
+				l = benchAnyList()
+				b.StartTimer()
+			}
+			l.AddVal(n, mid)
+		}
+	})
+	b.Run("container/list", func(b *testing.B) {
+		b.ReportAllocs()
+		var l *list.List
+		for n := 0; n < b.N; n++ {
+			if n%benchSize == 0 {
+				b.StopTimer()
+				l = benchContainerList()
+				b.StartTimer()
+			}
+			l.InsertBefore(n, elementAt(l, mid))
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		b.ReportAllocs()
+		var s []int
+		for n := 0; n < b.N; n++ {
+			if n%benchSize == 0 {
+				b.StopTimer()
+				s = benchSlice()
+				b.StartTimer()
+			}
+			s = slices.Insert(s, mid, n)
+		}
+	})
+}
+
+// BenchmarkGet ...Reads every position in turn
+func BenchmarkGet(b *testing.B) {
+	b.Run("CList", func(b *testing.B) {
+		l := benchCList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.Get(n % benchSize)
+		}
+	})
+	b.Run("List", func(b *testing.B) {
+		l := benchList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.Get(n % benchSize)
+		}
+	})
+	b.Run("AnyList", func(b *testing.B) {
+		l := benchAnyList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.Get(n % benchSize)
+		}
+	})
+	b.Run("container/list", func(b *testing.B) {
+		l := benchContainerList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			_ = elementAt(l, n%benchSize).Value
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		s := benchSlice()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			_ = s[n%benchSize]
+		}
+	})
+}
+
+// BenchmarkIndexOf ...Looks up every value in turn
+func BenchmarkIndexOf(b *testing.B) {
+	b.Run("CList", func(b *testing.B) {
+		l := benchCList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.IndexOf(n % benchSize)
+		}
+	})
+	b.Run("List", func(b *testing.B) {
+		l := benchList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.IndexOf(n % benchSize)
+		}
+	})
+	b.Run("AnyList", func(b *testing.B) {
+		l := benchAnyList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.IndexOf(n % benchSize)
+		}
+	})
+	b.Run("container/list", func(b *testing.B) {
+		l := benchContainerList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			findElement(l, n%benchSize)
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		s := benchSlice()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			_ = slices.Index(s, n%benchSize)
+		}
+	})
+}
+
+// BenchmarkRemove ...Removes a value and adds it back at the end, so the list keeps its size
+func BenchmarkRemove(b *testing.B) {
+	b.Run("CList", func(b *testing.B) {
+		l := benchCList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.Remove(n % benchSize)
+			l.Add(n % benchSize)
+		}
+	})
+	b.Run("List", func(b *testing.B) {
+		l := benchList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.Remove(n % benchSize)
+			l.Add(n % benchSize)
+		}
+	})
+	b.Run("AnyList", func(b *testing.B) {
+		l := benchAnyList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.Remove(n % benchSize)
+			l.Add(n % benchSize)
+		}
+	})
+	b.Run("container/list", func(b *testing.B) {
+		l := benchContainerList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.Remove(findElement(l, n%benchSize))
+			l.PushBack(n % benchSize)
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		s := benchSlice()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			i := slices.Index(s, n%benchSize)
+			s = slices.Delete(s, i, i+1)
+			s = append(s, n%benchSize)
+		}
+	})
+}
+
+// BenchmarkSubList ...Takes the middle half of the list.
+// container/list has no views, so it is left out; the slice re-slices.
+func BenchmarkSubList(b *testing.B) {
+	const start, end = benchSize / 4, 3 * benchSize / 4
+
+	b.Run("CList", func(b *testing.B) {
+		l := benchCList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.SubList(start, end)
+		}
+	})
+	b.Run("List", func(b *testing.B) {
+		l := benchList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.SubList(start, end)
+		}
+	})
+	b.Run("AnyList", func(b *testing.B) {
+		l := benchAnyList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			l.SubList(start, end)
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		s := benchSlice()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			_ = s[start:end:end]
+		}
+	})
+}
+
+// BenchmarkForEach ...Sums the whole list
+func BenchmarkForEach(b *testing.B) {
+	b.Run("CList", func(b *testing.B) {
+		l := benchCList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			sum := 0
+			l.ForEach(func(val interface{}) bool {
+				sum += val.(int)
+				return true
+			})
+		}
+	})
+	b.Run("List", func(b *testing.B) {
+		l := benchList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			sum := 0
+			l.ForEach(func(val int) bool {
+				sum += val
+				return true
+			})
+		}
+	})
+	b.Run("AnyList", func(b *testing.B) {
+		l := benchAnyList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			sum := 0
+			l.ForEach(func(val int) bool {
+				sum += val
+				return true
+			})
+		}
+	})
+	b.Run("container/list", func(b *testing.B) {
+		l := benchContainerList()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			sum := 0
+			for e := l.Front(); e != nil; e = e.Next() {
+				sum += e.Value.(int)
+			}
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		s := benchSlice()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			sum := 0
+			for _, val := range s {
+				sum += val
+			}
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package tests
+
+import (
+	stdlist "container/list"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+	"github.com/gbenroscience/linkedlist/list"
+)
+
+func TestConverters(t *testing.T) {
+	l := ds.FromSlice(ds.NewList[int](), []int{1, 2, 3})
+	assertValues(t, "FromSlice", l.ToArray(), []int{1, 2, 3})
+	assertValues(t, "All", slices.Collect(l.All()), []int{1, 2, 3})
+
+	doubled := ds.Collect(func(yield func(int) bool) {
+		for v := range l.All() {
+			l.Add(v * 2) // the iterator works on a copy, so the list may change meanwhile
+			if !yield(v * 2) {
+				return
+			}
+		}
+	})
+	assertValues(t, "Collect", doubled.ToArray(), []int{2, 4, 6})
+	assertValues(t, "changed while iterating", l.ToArray(), []int{1, 2, 3, 2, 4, 6})
+
+	ages := map[string]int{"carol": 41, "alice": 30, "bob": 25}
+	names := ds.FromMapKeys(ds.NewAnyList[string](), ages, strings.Compare)
+	assertValues(t, "FromMapKeys", names.ToArray(), []string{"alice", "bob", "carol"})
+	byAge := ds.FromMapValues(ds.NewList[int](), ages, func(a, b int) int { return a - b })
+	assertValues(t, "FromMapValues", byAge.ToArray(), []int{25, 30, 41})
+	unsorted := ds.FromMapKeys(ds.NewList[string](), ages, nil).ToArray()
+	slices.Sort(unsorted)
+	assertValues(t, "FromMapKeys unsorted", unsorted, slices.Sorted(maps.Keys(ages)))
+
+	c := ds.FromSeq(ds.NewCList(), slices.Values([]interface{}{"x", 1}))
+	assertValues(t, "CList", slices.Collect(c.All()), []interface{}{"x", 1})
+
+	std := ds.ToContainerList[int](l)
+	if std.Len() != 6 || std.Front().Value != 1 || std.Back().Value != 6 {
+		t.Fatalf("ToContainerList holds %d values, from %v to %v", std.Len(), std.Front().Value, std.Back().Value)
+	}
+	back, err := ds.FromContainerList(ds.NewAnyList[int](), std)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "FromContainerList", back.ToArray(), l.ToArray())
+
+	std.PushBack("seven")
+	into := ds.FromSlice(ds.NewList[int](), []int{0})
+	if _, err := ds.FromContainerList(into, std); err == nil {
+		t.Fatal("FromContainerList took a string as an int")
+	}
+	assertValues(t, "after a failed FromContainerList", into.ToArray(), []int{0})
+}
+
+func TestElements(t *testing.T) {
+	l := newIntAnyList(1, 2, 3).WithPositionIndex().WithHistory(0)
+	mirror := l.ToArray()
+	l.Subscribe(func(ev ds.Event[int]) {
+		mirror = applyEvent(mirror, ev)
+	})
+
+	one, three := l.Front(), l.Back()
+	two := one.Next()
+	if two.Value() != 2 || !two.Next().Same(three) || three.Next() != nil || one.Prev() != nil {
+		t.Fatal("Front, Back, Next and Prev do not walk the list")
+	}
+
+	zero := l.PushFront(0)
+	l.InsertAfter(25, two)
+	l.PushBack(4)
+	assertValues(t, "inserted", l.ToArray(), []int{0, 1, 2, 25, 3, 4})
+
+	l.MoveToBack(zero)
+	l.MoveToFront(three)
+	l.MoveBefore(one, two)
+	l.MoveAfter(two, zero)
+	assertValues(t, "moved", l.ToArray(), []int{3, 1, 25, 4, 0, 2})
+	mustValidate(t, "after the moves", l)
+	if zero.Value() != 0 || !zero.Next().Same(two) {
+		t.Fatal("handles no longer point at their values after the moves")
+	}
+
+	if val, ok := l.RemoveElement(one); !ok || val != 1 {
+		t.Fatalf("RemoveElement = %d, %v", val, ok)
+	}
+	other := newIntAnyList(9)
+	if _, ok := other.RemoveElement(two); ok {
+		t.Fatal("removed an element of another list")
+	}
+	if other.InsertBefore(8, two) != nil {
+		t.Fatal("inserted before an element of another list")
+	}
+	assertValues(t, "removed", l.ToArray(), []int{3, 25, 4, 0, 2})
+	assertValues(t, "mirror", mirror, l.ToArray())
+	mustValidate(t, "after RemoveElement", l)
+
+	// a move is one undo step, like any other call
+	l.Undo()
+	assertValues(t, "RemoveElement undone", l.ToArray(), []int{3, 1, 25, 4, 0, 2})
+	l.Undo()
+	assertValues(t, "MoveAfter undone", l.ToArray(), []int{3, 1, 2, 25, 4, 0})
+}
+
+func TestRemovedElements(t *testing.T) {
+	for _, mode := range []ds.AllocMode{ds.AllocDefault, ds.AllocFreeList} {
+		l := newIntAnyList(1, 2, 3).WithAllocator(mode)
+		two := l.Front().Next()
+		if val, ok := l.RemoveElement(two); !ok || val != 2 {
+			t.Fatalf("RemoveElement = %d, %v", val, ok)
+		}
+		if _, ok := l.RemoveElement(two); ok {
+			t.Fatal("removed an element twice")
+		}
+		// the list may reuse the node of the removed element
+		l.PushBack(4)
+		three := l.Back().Prev()
+		if _, ok := l.RemoveElement(two); ok {
+			t.Fatal("removed an element whose node was reused")
+		}
+		l.MoveToFront(two)
+		l.MoveAfter(two, three)
+		if l.InsertBefore(5, two) != nil || l.InsertAfter(5, two) != nil {
+			t.Fatal("inserted next to a removed element")
+		}
+		if two.Value() != 2 || two.Next() != nil || two.Prev() != nil || two.Same(l.Back()) {
+			t.Fatalf("a removed element holds %d and has neighbours", two.Value())
+		}
+		assertValues(t, "after using a removed element", l.ToArray(), []int{1, 3, 4})
+		mustValidate(t, "after using a removed element", l)
+
+		l.Clear()
+		if three.Next() != nil || l.InsertAfter(5, three) != nil {
+			t.Fatal("an element survived Clear")
+		}
+	}
+}
+
+func TestListShimMatchesContainerList(t *testing.T) {
+	rnd := testRnd(t)
+	want := stdlist.New()
+	var got list.List // the zero value is ready to use, as in container/list
+	var wantElems []*stdlist.Element
+	var gotElems []*list.Element
+
+	for step := 0; step < 2000; step++ {
+		v := rnd.NextInt(100)
+		if len(wantElems) == 0 {
+			wantElems = append(wantElems, want.PushBack(v))
+			gotElems = append(gotElems, got.PushBack(v))
+			continue
+		}
+		i, j := rnd.NextInt(len(wantElems)), rnd.NextInt(len(wantElems))
+		switch rnd.NextInt(9) {
+		case 0:
+			wantElems = append(wantElems, want.PushFront(v))
+			gotElems = append(gotElems, got.PushFront(v))
+		case 1:
+			wantElems = append(wantElems, want.InsertBefore(v, wantElems[i]))
+			gotElems = append(gotElems, got.InsertBefore(v, gotElems[i]))
+		case 2:
+			wantElems = append(wantElems, want.InsertAfter(v, wantElems[i]))
+			gotElems = append(gotElems, got.InsertAfter(v, gotElems[i]))
+		case 3:
+			want.MoveToFront(wantElems[i])
+			got.MoveToFront(gotElems[i])
+		case 4:
+			want.MoveToBack(wantElems[i])
+			got.MoveToBack(gotElems[i])
+		case 5:
+			want.MoveBefore(wantElems[i], wantElems[j])
+			got.MoveBefore(gotElems[i], gotElems[j])
+		case 6:
+			want.MoveAfter(wantElems[i], wantElems[j])
+			got.MoveAfter(gotElems[i], gotElems[j])
+		case 7:
+			if want.Remove(wantElems[i]) != got.Remove(gotElems[i]) {
+				t.Fatal("Remove returned different values")
+			}
+			if gotElems[i].Next() != nil || gotElems[i].Prev() != nil {
+				t.Fatal("a removed element still has neighbours")
+			}
+			wantElems = slices.Delete(wantElems, i, i+1)
+			gotElems = slices.Delete(gotElems, i, i+1)
+		default:
+			wantElems = append(wantElems, want.PushBack(v))
+			gotElems = append(gotElems, got.PushBack(v))
+		}
+	}
+
+	if got.Len() != want.Len() {
+		t.Fatalf("Len = %d, want %d", got.Len(), want.Len())
+	}
+	var forward, backward []any
+	for e := want.Front(); e != nil; e = e.Next() {
+		forward = append(forward, e.Value)
+	}
+	for e := want.Back(); e != nil; e = e.Prev() {
+		backward = append(backward, e.Value)
+	}
+	var gotForward, gotBackward []any
+	for e := got.Front(); e != nil; e = e.Next() {
+		gotForward = append(gotForward, e.Value)
+	}
+	for e := got.Back(); e != nil; e = e.Prev() {
+		gotBackward = append(gotBackward, e.Value)
+	}
+	assertValues(t, "front to back", gotForward, forward)
+	assertValues(t, "back to front", gotBackward, backward)
+	mustValidate(t, "underlying list", got.AnyList())
+
+	got.PushBackList(&got)
+	got.PushFrontList(&got)
+	if got.Len() != 4*want.Len() {
+		t.Fatalf("Len = %d after copying the list onto itself twice, want %d", got.Len(), 4*want.Len())
+	}
+	if got.Init().Len() != 0 || got.Front() != nil {
+		t.Fatal("Init did not clear the list")
+	}
+
+	// every element leaves the list on Init, not only the first one
+	var l list.List
+	l.PushBack(1)
+	old := l.PushBack(2)
+	l.PushBack(3)
+	l.Init()
+	l.PushBack(10)
+	l.PushBack(11)
+	if l.Remove(old) != 2 || old.Next() != nil || l.Len() != 2 {
+		t.Fatal("an element cleared by Init is still in the list")
+	}
+	mustValidate(t, "after Init", l.AnyList())
+}
@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+	"github.com/gbenroscience/linkedlist/utils"
+)
+
+func TestCRDTConcurrentEdits(t *testing.T) {
+	a, b := ds.NewCRDTList[string]("a"), ds.NewCRDTList[string]("b")
+	b.Merge(a.Add("1"), a.Add("2"))
+
+	// both insert between 1 and 2, and a removes 1 that b inserts after
+	opsA := []ds.CRDTOp[string]{}
+	op, _ := a.AddVal("x", 1)
+	opsA = append(opsA, op)
+	op, _ = a.RemoveIndex(0)
+	opsA = append(opsA, op)
+	opB, _ := b.AddVal("y", 1)
+	opB2, _ := b.AddVal("z", 1)
+
+	a.Merge(opB, opB2)
+	b.Merge(opsA...)
+	assertValues(t, "a", a.ToArray(), b.ToArray())
+	// concurrent inserts after the same element go by ID, highest first: z (b:4), y (b:3), x (a:3)
+	assertValues(t, "merged", a.ToArray(), []string{"z", "y", "x", "2"})
+	if a.Tombstones() != 1 {
+		t.Fatalf("a keeps %d tombstones, want 1", a.Tombstones())
+	}
+}
+
+func TestCRDTMergeWaitsForMissingElements(t *testing.T) {
+	a, b, c := ds.NewCRDTList[int]("a"), ds.NewCRDTList[int]("b"), ds.NewCRDTList[int]("c")
+	fromA := a.Add(1)
+	b.Merge(fromA)
+	fromB, _ := b.AddVal(2, 1)
+
+	// c hears from b before a: b's insert names a's element
+	c.Merge(fromB)
+	if c.Count() != 0 || c.Pending() != 1 {
+		t.Fatalf("c holds %v with %d ops pending, want nothing and 1", c.ToArray(), c.Pending())
+	}
+	c.Merge(fromA, fromA)
+	assertValues(t, "c", c.ToArray(), []int{1, 2})
+	if c.Pending() != 0 {
+		t.Fatalf("%d ops still pending", c.Pending())
+	}
+}
+
+// crdtNetwork - Replicas that edit at random and hear from each other in random order,
+// but from each replica in the order it made its ops
+type crdtNetwork struct {
+	rnd      *utils.RandomLife
+	replicas []*ds.CRDTList[int]
+	// ops made by each replica, and how many of them each replica has received
+	sent     [][]ds.CRDTOp[int]
+	received [][]int
+	next     int
+}
+
+func newCRDTNetwork(rnd *utils.RandomLife, n int) *crdtNetwork {
+	net := &crdtNetwork{rnd: rnd}
+	for i := 0; i < n; i++ {
+		net.replicas = append(net.replicas, ds.NewCRDTList[int](fmt.Sprintf("r%d", i)))
+		net.sent = append(net.sent, nil)
+		net.received = append(net.received, make([]int, n))
+	}
+	return net
+}
+
+// step ...Makes a random edit on a random replica, or delivers a few ops from one replica to another
+func (net *crdtNetwork) step() {
+	rnd := net.rnd
+	i := rnd.NextInt(len(net.replicas))
+	r := net.replicas[i]
+
+	switch rnd.WeightedChoice([]float64{4, 2, 5}) {
+	case 0:
+		net.next++
+		op, err := r.AddVal(net.next, rnd.NextInt(r.Count()+1))
+		if err != nil {
+			panic(err)
+		}
+		net.sent[i] = append(net.sent[i], op)
+	case 1:
+		if r.Count() > 0 {
+			op, _ := r.RemoveIndex(rnd.NextInt(r.Count()))
+			net.sent[i] = append(net.sent[i], op)
+		}
+	case 2:
+		from := rnd.NextInt(len(net.replicas))
+		if from == i {
+			return
+		}
+		start := net.received[i][from]
+		end := min(len(net.sent[from]), start+1+rnd.NextInt(3))
+		// now and then an op arrives twice
+		if start > 0 && rnd.NextInt(10) == 0 {
+			start--
+		}
+		r.Merge(net.sent[from][start:end]...)
+		net.received[i][from] = end
+	}
+}
+
+// sync ...Delivers every op to every replica
+func (net *crdtNetwork) sync() {
+	for i, r := range net.replicas {
+		for from := range net.replicas {
+			if from != i {
+				r.Merge(net.sent[from][net.received[i][from]:]...)
+				net.received[i][from] = len(net.sent[from])
+			}
+		}
+	}
+}
+
+func (net *crdtNetwork) assertConverged(t *testing.T) {
+	t.Helper()
+	want := net.replicas[0].ToArray()
+	for _, r := range net.replicas {
+		if r.Pending() != 0 {
+			t.Fatalf("replica %s has %d ops pending", r.Replica(), r.Pending())
+		}
+		assertValues(t, "replica "+r.Replica(), r.ToArray(), want)
+	}
+}
+
+func TestCRDTConvergence(t *testing.T) {
+	rnd := testRnd(t)
+	for round := 0; round < 50; round++ {
+		net := newCRDTNetwork(&rnd, 2+rnd.NextInt(3))
+		for i := 0; i < 200; i++ {
+			net.step()
+		}
+		net.sync()
+		net.assertConverged(t)
+	}
+}
+
+func TestCRDTGC(t *testing.T) {
+	rnd := testRnd(t)
+	net := newCRDTNetwork(&rnd, 3)
+	for i := 0; i < 300; i++ {
+		net.step()
+	}
+
+	r0 := net.replicas[0]
+	if r0.Tombstones() == 0 {
+		t.Fatal("the edits left no tombstones")
+	}
+	// replicas that have not caught up hold the collection back
+	net.sent[0] = append(net.sent[0], r0.Add(-1))
+	if n := r0.GC(net.replicas[1].Version(), net.replicas[2].Version()); n != 0 {
+		t.Fatalf("GC dropped %d tombstones before the replicas caught up", n)
+	}
+
+	net.sync()
+	versions := []map[string]uint64{r0.Version(), net.replicas[1].Version(), net.replicas[2].Version()}
+	// only some replicas collect, and the edits go on
+	if n := r0.GC(versions[1:]...); n == 0 || r0.Tombstones() != 0 {
+		t.Fatalf("GC dropped %d tombstones, %d left", n, r0.Tombstones())
+	}
+	net.replicas[1].GC(versions[0], versions[2])
+	for i := 0; i < 300; i++ {
+		net.step()
+	}
+	net.sync()
+	net.assertConverged(t)
+}
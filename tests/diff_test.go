@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+func eqString(x, y string) bool { return x == y }
+
+// editCount ...Returns the number of values a patch deletes and inserts
+func editCount[T any](patch ds.Patch[T]) int {
+	n := 0
+	for _, e := range patch {
+		if e.Kind != ds.EditKeep {
+			n += len(e.Values)
+		}
+	}
+	return n
+}
+
+// lcsLen ...Returns the length of the longest common subsequence of a and b, the slow way
+func lcsLen(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestDiffIsShortest(t *testing.T) {
+	a, b := strings.Split("ABCABBA", ""), strings.Split("CBABAC", "")
+	if n := editCount(ds.Diff(a, b, eqString)); n != 5 {
+		t.Fatalf("Diff(ABCABBA, CBABAC) makes %d edits, want 5", n)
+	}
+
+	rnd := testRnd(t)
+	for i := 0; i < 500; i++ {
+		a := strings.Split(rnd.NextStringFrom("abc", rnd.NextInt(30)), "")
+		b := strings.Split(rnd.NextStringFrom("abc", rnd.NextInt(30)), "")
+		if len(a) == 1 && a[0] == "" {
+			a = nil
+		}
+		if len(b) == 1 && b[0] == "" {
+			b = nil
+		}
+		patch := ds.Diff(a, b, eqString)
+		if n, want := editCount(patch), len(a)+len(b)-2*lcsLen(a, b); n != want {
+			t.Fatalf("Diff(%v, %v) makes %d edits, want %d: %v", a, b, n, want, patch)
+		}
+
+		list := ds.NewList[string]()
+		list.AddValues(a...)
+		if err := ds.Apply(list, patch); err != nil {
+			t.Fatalf("Apply(%v, %v): %v", a, patch, err)
+		}
+		assertValues(t, "patched", list.ToArray(), b)
+	}
+}
+
+func TestApplyChecksTheList(t *testing.T) {
+	patch := ds.Diff([]int{1, 2, 3}, []int{1, 3, 4}, func(x, y int) bool { return x == y })
+
+	list := newIntAnyList(1, 5, 3)
+	if err := ds.Apply(list, patch); err == nil {
+		t.Fatal("a patch that deletes 2 applied to a list holding 5")
+	}
+	if err := ds.Apply(newIntAnyList(1, 2), patch); err == nil {
+		t.Fatal("a patch made for 3 values applied to 2")
+	}
+	assertValues(t, "untouched", list.ToArray(), []int{1, 5, 3})
+
+	// a malformed patch, as a decoded one may be, is an error and not a panic
+	var malformed ds.Patch[string]
+	if err := json.Unmarshal([]byte(`[{"op":"delete","values":["a","b"]},{"op":"keep","n":-1}]`), &malformed); err != nil {
+		t.Fatal(err)
+	}
+	one := ds.NewList[string]()
+	one.Add("a")
+	if err := ds.Apply(one, malformed); err == nil {
+		t.Fatal("a patch that deletes two values applied to one")
+	}
+	assertValues(t, "untouched by the malformed patch", one.ToArray(), []string{"a"})
+
+	// a sublist is patched in place
+	parent := newIntAnyList(0, 1, 2, 3, 9)
+	sub, _ := parent.SubList(1, 4)
+	if err := ds.Apply(sub, patch); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "sublist", sub.ToArray(), []int{1, 3, 4})
+	assertValues(t, "parent", parent.ToArray(), []int{0, 1, 3, 4, 9})
+
+	// a change that replaces every value of a sublist leaves the sublist in its parent
+	replace := ds.Diff([]int{2, 3}, []int{9, 8}, func(x, y int) bool { return x == y })
+	anyParent := newIntAnyList(0, 1, 2, 3, 4, 5)
+	anySub, _ := anyParent.SubList(2, 4)
+	if err := ds.Apply(anySub, replace); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "replaced sublist", anySub.ToArray(), []int{9, 8})
+	assertValues(t, "parent of the replaced sublist", anyParent.ToArray(), []int{0, 1, 9, 8, 4, 5})
+	listParent := newIntList(0, 1, 2, 3, 4, 5)
+	listSub, _ := listParent.SubList(2, 4)
+	if err := ds.Apply(listSub, replace); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "replaced List sublist", listSub.ToArray(), []int{9, 8})
+	assertValues(t, "parent of the replaced List sublist", listParent.ToArray(), []int{0, 1, 9, 8, 4, 5})
+
+	clist := ds.NewCList()
+	clist.AddValues(1, 2, 3)
+	cpatch := ds.Diff([]interface{}{1, 2, 3}, []interface{}{3, 2, 1}, func(x, y interface{}) bool { return x == y })
+	if err := ds.Apply(clist, cpatch); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "CList", clist.ToArray(), []interface{}{3, 2, 1})
+}
+
+func TestApplyIsOneStep(t *testing.T) {
+	list := ds.NewList[string]().WithHistory(0)
+	list.AddValues("a", "b", "c", "d")
+	before := list.ToArray()
+	after := []string{"x", "b", "d", "e", "f"}
+
+	if err := ds.Apply(list, ds.Diff(before, after, eqString)); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "patched", list.ToArray(), after)
+	list.Undo()
+	assertValues(t, "undone", list.ToArray(), before)
+}
+
+func TestPatchFormats(t *testing.T) {
+	a := strings.Split("a b c d e f g h i j", " ")
+	b := strings.Split("a B c d e f g h j k", " ")
+	patch := ds.Diff(a, b, eqString)
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"op":"keep","n":1},{"op":"delete","values":["b"]},{"op":"insert","values":["B"]},{"op":"keep","n":6},` +
+		`{"op":"delete","values":["i"]},{"op":"keep","n":1},{"op":"insert","values":["k"]}]`
+	if string(data) != want {
+		t.Fatalf("the patch encoded as\n%s\nwant\n%s", data, want)
+	}
+	var decoded ds.Patch[string]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	list := ds.NewList[string]()
+	list.AddValues(a...)
+	if err := ds.Apply(list, decoded); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "patched by the decoded patch", list.ToArray(), b)
+
+	unified := `@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+@@ -8,3 +8,3 @@
+ h
+-i
+ j
++k
+`
+	if got := patch.Unified(a, 1); got != unified {
+		t.Fatalf("Unified returned\n%s\nwant\n%s", got, unified)
+	}
+
+	if got := ds.Diff(nil, []string{"x", "y"}, eqString).Unified(nil, 3); got != "@@ -0,0 +1,2 @@\n+x\n+y\n" {
+		t.Fatalf("Unified of an insertion into nothing returned\n%s", got)
+	}
+}
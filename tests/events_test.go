@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+// applyEvent ...Replays an event on a slice mirror of the list
+func applyEvent[T any](mirror []T, ev ds.Event[T]) []T {
+	switch ev.Kind {
+	case ds.EventInserted:
+		tail := append([]T{}, mirror[ev.Index:]...)
+		return append(append(mirror[:ev.Index], ev.Values...), tail...)
+	case ds.EventRemoved:
+		return append(mirror[:ev.Index], mirror[ev.Index+len(ev.Values):]...)
+	case ds.EventSet:
+		mirror[ev.Index] = ev.New
+		return mirror
+	case ds.EventCleared:
+		return mirror[:0]
+	}
+	return mirror
+}
+
+func TestSubscribeReportsEveryChange(t *testing.T) {
+	list := ds.NewList[int]()
+	list.AddValues(1, 2, 3)
+
+	var events []ds.Event[int]
+	cancel := list.Subscribe(func(ev ds.Event[int]) {
+		events = append(events, ev)
+	})
+
+	list.Add(4)
+	list.AddVal(9, 1)
+	list.Set(0, 10)
+	list.RemoveIndex(2)
+	list.Remove(4)
+	list.AddAll(newIntList(7, 8))
+	list.Clear()
+
+	want := []struct {
+		kind  ds.EventKind
+		index int
+		count int
+	}{
+		{ds.EventInserted, 3, 1},
+		{ds.EventInserted, 1, 1},
+		{ds.EventSet, 0, 0},
+		{ds.EventRemoved, 2, 1},
+		{ds.EventRemoved, 3, 1},
+		{ds.EventInserted, 3, 2},
+		{ds.EventCleared, 0, 5},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		ev := events[i]
+		if ev.Kind != w.kind || ev.Index != w.index || len(ev.Values) != w.count {
+			t.Fatalf("event %d is %v at %d with %d values, want %v at %d with %d values",
+				i, ev.Kind, ev.Index, len(ev.Values), w.kind, w.index, w.count)
+		}
+	}
+	if events[2].Old != 1 || events[2].New != 10 {
+		t.Fatalf("Set event went from %d to %d, want 1 to 10", events[2].Old, events[2].New)
+	}
+
+	cancel()
+	list.Add(1)
+	if len(events) != len(want) {
+		t.Fatalf("events are still delivered after cancel")
+	}
+}
+
+func TestSubscriberMirrorsListThroughSubLists(t *testing.T) {
+	for _, positions := range []bool{false, true} {
+		rnd := testRnd(t)
+		list := newIntAnyList()
+		if positions {
+			list.WithPositionIndex()
+		}
+		for i := 0; i < 50; i++ {
+			list.Add(i)
+		}
+
+		mirror := list.ToArray()
+		list.Subscribe(func(ev ds.Event[int]) {
+			mirror = applyEvent(mirror, ev)
+		})
+
+		for i := 0; i < 500; i++ {
+			n := list.Count()
+			switch rnd.NextInt(5) {
+			case 0:
+				list.AddVal(-i, rnd.NextInt(n+1))
+			case 1:
+				list.RemoveIndex(rnd.NextInt(n))
+			case 2:
+				list.Set(rnd.NextInt(n), i)
+			case 3, 4:
+				start := rnd.NextInt(n - 1)
+				sub, err := list.SubList(start, start+2)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sub.Set(1, i)
+				sub.AddVal(-i, 1)
+				sub.RemoveIndex(0)
+			}
+			if n < 10 {
+				for j := 0; j < 20; j++ {
+					list.Add(j)
+				}
+			}
+		}
+
+		sub, _ := list.SubList(5, 15)
+		sub.Clear()
+
+		assertValues(t, "mirror", mirror, list.ToArray())
+	}
+}
+
+func TestSubscribersRunOutsideTheLock(t *testing.T) {
+	list := newIntAnyList(1, 2, 3)
+
+	sizes := []int{}
+	list.Subscribe(func(ev ds.Event[int]) {
+		// would deadlock if events were delivered with the list locked
+		sizes = append(sizes, list.Count())
+		if ev.Kind == ds.EventInserted && ev.Values[0] == 4 {
+			list.Add(5)
+		}
+	})
+	list.Add(4)
+
+	assertValues(t, "sizes", sizes, []int{4, 5})
+}
+
+// TestSubscribeWhileChanging is meant for go test -race: the list hands events to its hub and history
+// while other goroutines install them
+func TestSubscribeWhileChanging(t *testing.T) {
+	list := newIntAnyList()
+	typed := ds.NewList[int]()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			list.Add(i)
+			typed.Add(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		list.WithHistory(0)
+		typed.WithHistory(0)
+		list.Subscribe(func(ev ds.Event[int]) {})
+		typed.Subscribe(func(ev ds.Event[int]) {})
+	}()
+	wg.Wait()
+
+	if list.Count() != 100 || typed.Count() != 100 {
+		t.Fatalf("Count = %d and %d, want 100", list.Count(), typed.Count())
+	}
+}
+
+func TestSubscribeChan(t *testing.T) {
+	list := ds.NewList[string]()
+	events, cancel := list.SubscribeChan(4)
+
+	list.Add("a")
+	list.Add("b")
+	list.Set(0, "c")
+
+	for _, want := range []ds.EventKind{ds.EventInserted, ds.EventInserted, ds.EventSet} {
+		if ev := <-events; ev.Kind != want {
+			t.Fatalf("got %v, want %v", ev.Kind, want)
+		}
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Fatalf("the channel must be closed once the subscription is cancelled")
+	}
+	list.Add("d")
+}
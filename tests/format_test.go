@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+func TestFormatVerbs(t *testing.T) {
+	list := newIntList(1, 2, 3, 4, 5)
+	sub, _ := list.SubList(1, 3)
+
+	cases := []struct {
+		format string
+		arg    interface{}
+		want   string
+	}{
+		{"%v", list, "[1 2 3 4 5]"},
+		{"%s", list.String(), "[1 2 3 4 5]"},
+		{"%03d", sub, "[002 003]"},
+		{"%+v", sub, "[2 3] (len 2, sublist of 5)"},
+		{"%+v", list, "[1 2 3 4 5] (len 5, 1 live sublist)"},
+		{"%#v", sub, "[]int{2, 3}"},
+		{"%q", newStringAnyList("a", "b"), `["a" "b"]`},
+		{"%#v", newStringAnyList("a"), `[]string{"a"}`},
+		{"%v", ds.NewCList(), "[]"},
+		{"%#v", newIntUnrolledList(7, 8), "[]int{7, 8}"},
+		{"%#v", ds.NewCList(), "[]interface {}{}"},
+	}
+	for _, c := range cases {
+		if got := fmt.Sprintf(c.format, c.arg); got != c.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", c.format, got, c.want)
+		}
+	}
+}
+
+func newStringAnyList(values ...string) *ds.AnyList[string] {
+	list := ds.NewAnyList[string]()
+	list.AddValues(values...)
+	return list
+}
+
+func TestDumpTruncates(t *testing.T) {
+	list := ds.NewAnyList[int]()
+	for i := 0; i < 1000; i++ {
+		list.Add(i)
+	}
+
+	var b bytes.Buffer
+	if err := list.Dump(&b, ds.DumpOptions{Label: "numbers", MaxValues: 3, Details: true}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "numbers:\n[0 1 2 ... (997 more)] (len 1000)\n"; b.String() != want {
+		t.Fatalf("got %q, want %q", b.String(), want)
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	list := newIntList()
+	for i := 0; i < 100; i++ {
+		list.Add(i)
+	}
+
+	var b bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&b, nil))
+	logger.Info("state", "list", list)
+
+	out := b.String()
+	for _, want := range []string{"list.len=100", "list.values=\"[0 1 2", "list.truncated=true"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in %q", want, out)
+		}
+	}
+}
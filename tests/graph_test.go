@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+func TestWriteDOT(t *testing.T) {
+	list := newIntList(1, 2, 3, 4)
+	sub, _ := list.SubList(1, 3)
+
+	var b strings.Builder
+	if err := list.WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	dot := b.String()
+
+	for _, want := range []string{
+		"digraph List {",
+		`n0 [label="1"];`,
+		"n0 -> n1;",
+		"n1 -> n0 [style=dashed, color=gray];",
+		"head -> n0;",
+		"tail -> n3;",
+		`v0 [label="SubList 1\nsize 2", style=dashed];`,
+		`v0 -> n1 [style=dotted, label="first"];`,
+		`v0 -> n2 [style=dotted, label="last"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Fatalf("missing %q in\n%s", want, dot)
+		}
+	}
+	runtime.KeepAlive(sub)
+}
+
+func TestWriteMermaidShowsOutsideNeighbours(t *testing.T) {
+	list := ds.NewCList()
+	list.AddValues("a", `say "hi"`, "c")
+	sub, _ := list.SubList(1, 2)
+
+	var b strings.Builder
+	if err := sub.WriteMermaid(&b); err != nil {
+		t.Fatal(err)
+	}
+	mermaid := b.String()
+
+	for _, want := range []string{
+		"flowchart LR",
+		`n0["say #quot;hi#quot;"]`,
+		// the parent's nodes on either side
+		"n1(( ))",
+		"n2(( ))",
+		"head --> n0",
+		"tail --> n0",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Fatalf("missing %q in\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestDiagramsForgetCollectedSubLists(t *testing.T) {
+	list := newIntAnyList(1, 2, 3, 4)
+	func() {
+		sub, _ := list.SubList(0, 2)
+		sub.Set(0, 10)
+	}()
+	runtime.GC()
+
+	var b strings.Builder
+	list.WriteDOT(&b)
+	if strings.Contains(b.String(), "SubList") {
+		t.Fatalf("a collected sublist is still drawn:\n%s", b.String())
+	}
+}
+
+func TestDiagramsCutLabelsByRunes(t *testing.T) {
+	list := ds.NewList[string]()
+	list.Add(strings.Repeat("é", 40))
+
+	var b strings.Builder
+	list.WriteDOT(&b)
+	want := `n0 [label="` + strings.Repeat("é", 29) + `..."];`
+	if !strings.Contains(b.String(), want) {
+		t.Fatalf("missing %q in\n%s", want, b.String())
+	}
+}
+
+func TestDiagramsSyncSubLists(t *testing.T) {
+	list := newIntList(1, 2, 3, 4)
+	sub, _ := list.SubList(1, 3)
+	// added through the parent between the ends of the sublist
+	list.AddVal(9, 2)
+
+	var b strings.Builder
+	list.WriteDOT(&b)
+	if want := `v0 [label="SubList 1\nsize 3", style=dashed];`; !strings.Contains(b.String(), want) {
+		t.Fatalf("missing %q in\n%s", want, b.String())
+	}
+	runtime.KeepAlive(sub)
+}
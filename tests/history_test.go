@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+func TestUndoRedoEveryEdit(t *testing.T) {
+	list := newIntAnyList(1, 2, 3, 4, 5).WithHistory(0)
+
+	snapshots := [][]int{list.ToArray()}
+	edits := []func(){
+		func() { list.Add(6) },
+		func() { list.AddVal(9, 0) },
+		func() { list.AddAllAt(2, newIntAnyList(7, 8)) },
+		func() { list.Set(3, 30) },
+		func() { list.Remove(4) },
+		func() { list.RemoveIndex(0) },
+		func() {
+			sub, err := list.SubList(1, 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sub.Clear()
+		},
+		func() { list.Clear() },
+	}
+	for _, edit := range edits {
+		edit()
+		snapshots = append(snapshots, list.ToArray())
+	}
+
+	for i := len(snapshots) - 2; i >= 0; i-- {
+		if !list.Undo() {
+			t.Fatalf("Undo failed with %d steps left", i+1)
+		}
+		assertValues(t, "after Undo", list.ToArray(), snapshots[i])
+	}
+	if list.Undo() {
+		t.Fatalf("Undo must report false once the history is exhausted")
+	}
+
+	for i := 1; i < len(snapshots); i++ {
+		if !list.Redo() {
+			t.Fatalf("Redo failed at step %d", i)
+		}
+		assertValues(t, "after Redo", list.ToArray(), snapshots[i])
+	}
+	if list.Redo() {
+		t.Fatalf("Redo must report false once every step is redone")
+	}
+}
+
+func TestUndoGroupsAndNames(t *testing.T) {
+	list := ds.NewList[string]().WithHistory(0)
+	list.AddValues("a", "b", "c")
+
+	list.BeginGroup("move")
+	list.RemoveIndex(0)
+	list.Add("a")
+	if err := list.EndGroup(); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "moved", list.ToArray(), []string{"b", "c", "a"})
+
+	if name := list.UndoName(); name != "move" {
+		t.Fatalf("UndoName is %q, want move", name)
+	}
+	list.Undo()
+	assertValues(t, "undone", list.ToArray(), []string{"a", "b", "c"})
+	if name := list.RedoName(); name != "move" {
+		t.Fatalf("RedoName is %q, want move", name)
+	}
+
+	// a new edit drops what could have been redone
+	list.Set(0, "z")
+	if list.Redo() {
+		t.Fatalf("Redo must not be possible after a new edit")
+	}
+	if err := list.EndGroup(); err == nil {
+		t.Fatalf("EndGroup without BeginGroup must fail")
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	list := ds.NewList[int]().WithHistory(3)
+	for i := 0; i < 10; i++ {
+		list.Add(i)
+	}
+
+	undone := 0
+	for list.Undo() {
+		undone++
+	}
+	if undone != 3 {
+		t.Fatalf("undid %d steps, want 3", undone)
+	}
+	assertValues(t, "oldest kept state", list.ToArray(), []int{0, 1, 2, 3, 4, 5, 6})
+}
+
+func TestUndoThroughSubListsMatchesSnapshots(t *testing.T) {
+	rnd := testRnd(t)
+	list := newIntAnyList().WithPositionIndex().WithHistory(0)
+	for i := 0; i < 30; i++ {
+		list.Add(i)
+	}
+
+	snapshots := [][]int{list.ToArray()}
+	for i := 0; i < 200; i++ {
+		n := list.Count()
+		switch rnd.NextInt(4) {
+		case 0:
+			list.AddVal(-i, rnd.NextInt(n+1))
+		case 1:
+			list.RemoveIndex(rnd.NextInt(n))
+		case 2:
+			list.Set(rnd.NextInt(n), i)
+		case 3:
+			start := 1 + rnd.NextInt(n-3)
+			sub, err := list.SubList(start, start+2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sub.Set(0, i)
+		}
+		snapshots = append(snapshots, list.ToArray())
+		if list.Count() < 10 {
+			list.AddValues(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+			snapshots = append(snapshots, list.ToArray())
+		}
+	}
+
+	for i := len(snapshots) - 2; i >= 0; i-- {
+		if !list.Undo() {
+			t.Fatalf("Undo failed with %d steps left", i+1)
+		}
+		assertValues(t, "after Undo", list.ToArray(), snapshots[i])
+	}
+	assertValues(t, "initial state", list.ToArray(), snapshots[0])
+}
+
+func TestConcurrentUndoAndRedo(t *testing.T) {
+	list := newIntAnyList().WithHistory(0)
+	const workers, each = 8, 50
+	want := make([]int, workers*each)
+	for i := range want {
+		want[i] = i
+		list.Add(i)
+	}
+
+	run := func(step func() bool) {
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < each; i++ {
+					if !step() {
+						t.Error("a step went missing")
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	// each step appends at the index its predecessor left, so the steps must be replayed one at a time, in order
+	run(list.Undo)
+	assertValues(t, "everything undone", list.ToArray(), []int{})
+	run(list.Redo)
+	assertValues(t, "everything redone", list.ToArray(), want)
+	mustValidate(t, "after concurrent undos and redos", list)
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gbenroscience/linkedlist/httplist"
+	"github.com/gbenroscience/linkedlist/listd"
+)
+
+// httpDo ...Sends a request and decodes its JSON reply into reply, returning the status code
+func httpDo(t *testing.T, method string, url string, body string, reply any) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if reply != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, reply); err != nil {
+			t.Fatalf("%s %s replied %s: %v", method, url, data, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestHTTPListEndpoints(t *testing.T) {
+	store := listd.NewStore()
+	mux := http.NewServeMux()
+	h := httplist.NewHandler(store)
+	mux.Handle("/api/", http.StripPrefix("/api", h))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	url := srv.URL + "/api/lists/"
+
+	var pushed map[string]int
+	if code := httpDo(t, "POST", url+"todo", `["a", "b", "c", "d"]`, &pushed); code != 200 || pushed["len"] != 4 {
+		t.Fatalf("POST replied %d %v", code, pushed)
+	}
+	if code := httpDo(t, "PUT", url+"todo/1", `"B"`, nil); code != http.StatusNoContent {
+		t.Fatalf("PUT replied %d", code)
+	}
+	var removed string
+	if code := httpDo(t, "DELETE", url+"todo/0", "", &removed); code != 200 || removed != "a" {
+		t.Fatalf("DELETE replied %d %q", code, removed)
+	}
+
+	var values []string
+	httpDo(t, "GET", url+"todo", "", &values)
+	assertValues(t, "GET", values, []string{"B", "c", "d"})
+	httpDo(t, "GET", url+"todo?from=1&to=2", "", &values)
+	assertValues(t, "GET range", values, []string{"c"})
+
+	var names []string
+	httpDo(t, "GET", srv.URL+"/api/lists", "", &names)
+	assertValues(t, "names", names, []string{"todo"})
+
+	h.MaxBody = 64
+	var failure map[string]string
+	for _, c := range []struct {
+		method, path, body string
+		code               int
+	}{
+		{"PUT", "todo/9", `"x"`, http.StatusNotFound},
+		{"DELETE", "todo/-1", "", http.StatusNotFound},
+		{"PUT", "todo/x", `"x"`, http.StatusBadRequest},
+		{"POST", "todo", `{"not": "an array"}`, http.StatusBadRequest},
+		{"GET", "todo?from=5", "", http.StatusBadRequest},
+		{"GET", "missing", "", http.StatusNotFound},
+		{"GET", "missing?from=0&wait=1ms", "", http.StatusNotFound},
+		{"PUT", "missing/0", `"x"`, http.StatusNotFound},
+		{"DELETE", "missing/0", "", http.StatusNotFound},
+		{"POST", "todo", `["` + strings.Repeat("x", 100) + `"]`, http.StatusRequestEntityTooLarge},
+		{"PUT", "todo/0", `"` + strings.Repeat("x", 100) + `"`, http.StatusRequestEntityTooLarge},
+	} {
+		failure = nil
+		if code := httpDo(t, c.method, url+c.path, c.body, &failure); code != c.code || failure["error"] == "" {
+			t.Errorf("%s %s replied %d %v, want %d and an error", c.method, c.path, code, failure, c.code)
+		}
+	}
+
+	httpDo(t, "GET", srv.URL+"/api/lists", "", &names)
+	assertValues(t, "names after requests for an unknown list", names, []string{"todo"})
+}
+
+func TestHTTPListLongPolling(t *testing.T) {
+	store := listd.NewStore()
+	srv := httptest.NewServer(httplist.NewHandler(store))
+	defer srv.Close()
+	store.Push("feed", "old")
+
+	got := make(chan []string, 1)
+	go func() {
+		var values []string
+		// not httpDo, which may not fail the test from this goroutine
+		if resp, err := http.Get(srv.URL + "/lists/feed?from=1&wait=5s"); err == nil {
+			json.NewDecoder(resp.Body).Decode(&values)
+			resp.Body.Close()
+		}
+		got <- values
+	}()
+	time.Sleep(50 * time.Millisecond)
+	store.Push("feed", "new", "newer")
+
+	select {
+	case values := <-got:
+		assertValues(t, "long poll", values, []string{"new", "newer"})
+	case <-time.After(5 * time.Second):
+		t.Fatal("the long poll was not woken by Push")
+	}
+
+	var values []string
+	start := time.Now()
+	httpDo(t, "GET", srv.URL+"/lists/feed?from=3&wait=50ms", "", &values)
+	if len(values) != 0 || time.Since(start) < 50*time.Millisecond {
+		t.Fatalf("a long poll that timed out returned %v after %v", values, time.Since(start))
+	}
+}
@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+func TestIndexedListAgreesWithPlainList(t *testing.T) {
+	rnd := testRnd(t)
+
+	plain := ds.NewList[int]()
+	indexed := ds.NewList[int]().WithIndex()
+
+	for i := 0; i < 2000; i++ {
+		val := rnd.NextInt(50)
+		switch rnd.NextInt(5) {
+		case 0, 1:
+			plain.Add(val)
+			indexed.Add(val)
+		case 2:
+			index := rnd.NextInt(plain.Count() + 1)
+			plain.AddVal(val, index)
+			indexed.AddVal(val, index)
+		case 3:
+			plain.Remove(val)
+			indexed.Remove(val)
+		case 4:
+			if plain.Count() > 0 {
+				index := rnd.NextInt(plain.Count())
+				plain.Set(index, val)
+				indexed.Set(index, val)
+			}
+		}
+
+		probe := rnd.NextInt(50)
+		if plain.IndexOf(probe) != indexed.IndexOf(probe) {
+			t.Fatalf("step %d: IndexOf(%d) = %d, want %d", i, probe, indexed.IndexOf(probe), plain.IndexOf(probe))
+		}
+		if plain.Contains(probe) != indexed.Contains(probe) {
+			t.Fatalf("step %d: Contains(%d) = %v, want %v", i, probe, indexed.Contains(probe), plain.Contains(probe))
+		}
+	}
+	assertValues(t, "indexed list", indexed.ToArray(), plain.ToArray())
+}
+
+func TestIndexedAnyListSubList(t *testing.T) {
+	list := newIntAnyList(1, 2, 3, 4, 5, 6, 7, 8, 9).WithIndex(hashInt)
+
+	sub, err := list.SubList(2, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sub.Contains(4) || sub.Contains(1) || sub.Contains(8) {
+		t.Fatalf("sublist Contains is not limited to the sublist range: %v", sub.ToArray())
+	}
+	if i := sub.IndexOf(5); i != 2 {
+		t.Fatalf("sublist IndexOf(5) = %d, want 2", i)
+	}
+
+	sub.Remove(4)
+	if list.Contains(4) {
+		t.Fatalf("removing through the sublist must update the parent index")
+	}
+	if i := list.IndexOf(9); i != 7 {
+		t.Fatalf("IndexOf(9) = %d, want 7", i)
+	}
+
+	list.Clear()
+	if list.Contains(9) || list.IndexOf(9) != -1 {
+		t.Fatalf("Clear must empty the index")
+	}
+}
+
+func BenchmarkContains(b *testing.B) {
+	const n = 10000
+	build := func() *ds.AnyList[int] {
+		list := newIntAnyList()
+		for i := 0; i < n; i++ {
+			list.Add(i)
+		}
+		return list
+	}
+
+	b.Run("scan", func(b *testing.B) {
+		list := build()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			list.Contains(i % n)
+		}
+	})
+	b.Run("indexed", func(b *testing.B) {
+		list := build().WithIndex(hashInt)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			list.Contains(i % n)
+		}
+	})
+}
+
+func BenchmarkRemoveValue(b *testing.B) {
+	const n = 10000
+	b.Run("scan", func(b *testing.B) {
+		list := newIntAnyList()
+		for i := 0; i < n; i++ {
+			list.Add(i)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			v := (i * 7919) % n
+			list.Remove(v)
+			list.Add(v)
+		}
+	})
+	b.Run("indexed", func(b *testing.B) {
+		list := newIntAnyList().WithIndex(hashInt)
+		for i := 0; i < n; i++ {
+			list.Add(i)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			v := (i * 7919) % n
+			list.Remove(v)
+			list.Add(v)
+		}
+	})
+}
+
+// BenchmarkIndexMemory reports the heap cost of one element with and without the index
+func BenchmarkIndexMemory(b *testing.B) {
+	const n = 100000
+	measure := func(b *testing.B, build func() any) {
+		var keep any
+		var before, after runtime.MemStats
+		for i := 0; i < b.N; i++ {
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			keep = build()
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+		}
+		b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc)/n, "B/elem")
+		runtime.KeepAlive(keep)
+	}
+
+	b.Run("plain", func(b *testing.B) {
+		measure(b, func() any {
+			list := ds.NewList[int]()
+			for i := 0; i < n; i++ {
+				list.Add(i)
+			}
+			return list
+		})
+	})
+	b.Run("indexed", func(b *testing.B) {
+		measure(b, func() any {
+			list := ds.NewList[int]().WithIndex()
+			for i := 0; i < n; i++ {
+				list.Add(i)
+			}
+			return list
+		})
+	})
+}
+
+func TestIndexedFirstOccurrenceAmongDuplicates(t *testing.T) {
+	rnd := testRnd(t)
+	plain := ds.NewAnyList[int]()
+	// every value shares one hash, so the bucket holds all of them, in order of position
+	indexed := ds.NewAnyList[int]().WithIndex(func(int) uint64 { return 0 })
+	plain.Equals = func(a, b int) bool { return a == b }
+	indexed.Equals = plain.Equals
+
+	for i := 0; i < 1000; i++ {
+		val := rnd.NextInt(4)
+		switch rnd.NextInt(4) {
+		case 0, 1:
+			index := rnd.NextInt(plain.Count() + 1)
+			plain.AddVal(val, index)
+			indexed.AddVal(val, index)
+		case 2:
+			plain.Remove(val)
+			indexed.Remove(val)
+		case 3:
+			if plain.Count() > 0 {
+				index := rnd.NextInt(plain.Count())
+				plain.Set(index, val)
+				indexed.Set(index, val)
+			}
+		}
+		if n := plain.Count(); n > 2 {
+			from := rnd.NextInt(n - 1)
+			to := from + 1 + rnd.NextInt(n-from)
+			plainSub, _ := plain.SubList(from, to)
+			indexedSub, _ := indexed.SubList(from, to)
+			probe := rnd.NextInt(4)
+			if got, want := indexedSub.IndexOf(probe), plainSub.IndexOf(probe); got != want {
+				t.Fatalf("step %d: SubList(%d, %d).IndexOf(%d) = %d, want %d", i, from, to, probe, got, want)
+			}
+		}
+	}
+	assertValues(t, "indexed list", indexed.ToArray(), plain.ToArray())
+	mustValidate(t, "indexed list", indexed)
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gbenroscience/linkedlist/ds"
+	"github.com/gbenroscience/linkedlist/expvarlist"
+)
+
+// counting ...An Instrumentation that remembers what it was told
+type counting struct {
+	mu     sync.Mutex
+	ops    map[string]int
+	waited int
+	held   int
+	hops   []int
+}
+
+func (c *counting) Op(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ops == nil {
+		c.ops = make(map[string]int)
+	}
+	c.ops[name]++
+}
+
+func (c *counting) LockWaited(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waited++
+}
+
+func (c *counting) LockHeld(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.held++
+}
+
+func (c *counting) Traversed(hops int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hops = append(c.hops, hops)
+}
+
+func TestInstrumentationCountsOpsLocksAndHops(t *testing.T) {
+	c := &counting{}
+	list := ds.NewAnyList[int]().WithInstrumentation(c)
+	for i := 0; i < 10; i++ {
+		list.Add(i)
+	}
+	list.Get(3)
+	list.Get(8)
+	sub, _ := list.SubList(2, 6)
+	sub.Get(1)
+
+	if c.ops["Add"] != 10 || c.ops["Get"] != 3 || c.ops["SubList"] != 1 {
+		t.Fatalf("ops = %v", c.ops)
+	}
+	if c.waited != 14 || c.held != 14 {
+		t.Fatalf("waited %d times and held %d times, want 14 each", c.waited, c.held)
+	}
+	// Get(8) walks back from the end, SubList walks to both of its ends,
+	// and the sublist reports to the same instrumentation
+	want := []int{3, 1, 2, 4, 1}
+	if len(c.hops) != len(want) {
+		t.Fatalf("hops = %v, want %v", c.hops, want)
+	}
+	for i := range want {
+		if c.hops[i] != want[i] {
+			t.Fatalf("hops = %v, want %v", c.hops, want)
+		}
+	}
+}
+
+func TestInstrumentationOffDoesNotAllocate(t *testing.T) {
+	list := newIntList(1, 2, 3, 4, 5)
+	allocs := testing.AllocsPerRun(100, func() {
+		list.Get(3)
+		list.Contains(4)
+	})
+	if allocs != 0 {
+		t.Fatalf("%v allocations per run without instrumentation", allocs)
+	}
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	m := expvarlist.New()
+	list := ds.NewList[string]().WithInstrumentation(m)
+	list.AddValues("a", "b", "c")
+	list.Get(1)
+
+	if got := m.Ops.Get("AddValues").String(); got != "1" {
+		t.Fatalf("AddValues = %s, want 1", got)
+	}
+	if m.Locks.Value() != 2 || m.Traversals.Value() != 1 || m.TraversalHops.Value() != 1 {
+		t.Fatalf("metrics = %s", m.Var())
+	}
+	if !strings.Contains(m.Var().String(), `"ops": {"AddValues": 1, "Get": 1}`) {
+		t.Fatalf("metrics = %s", m.Var())
+	}
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	list := newIntList(1, 2, 3)
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "[1,2,3]" {
+		t.Fatalf("List encoded as %s", data)
+	}
+
+	sub, _ := newIntAnyList(0, 1, 2, 3, 4).SubList(1, 3)
+	if data, _ := json.Marshal(sub); string(data) != "[1,2]" {
+		t.Fatalf("sublist encoded as %s", data)
+	}
+	if data, _ := json.Marshal(ds.NewCList()); string(data) != "[]" {
+		t.Fatalf("empty CList encoded as %s", data)
+	}
+
+	// lists nested in other values, and zero lists, decode too
+	var doc struct {
+		Names ds.AnyList[string]
+		Sizes ds.List[int]
+	}
+	if err := json.Unmarshal([]byte(`{"Names": ["a", "b"], "Sizes": [4, 5, 6]}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "Names", doc.Names.ToArray(), []string{"a", "b"})
+	assertValues(t, "Sizes", doc.Sizes.ToArray(), []int{4, 5, 6})
+
+	if err := json.Unmarshal([]byte(`["x"]`), list); err == nil {
+		t.Fatal("decoding strings into a List[int] did not fail")
+	}
+	assertValues(t, "after a failed decode", list.ToArray(), []int{1, 2, 3})
+}
+
+func TestUnmarshalJSONIsOneStep(t *testing.T) {
+	list := ds.NewList[string]().WithHistory(0)
+	list.AddValues("a", "b")
+
+	var kinds []ds.EventKind
+	list.Subscribe(func(ev ds.Event[string]) {
+		kinds = append(kinds, ev.Kind)
+	})
+	if err := json.Unmarshal([]byte(`["x", "y", "z"]`), list); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "decoded", list.ToArray(), []string{"x", "y", "z"})
+	assertValues(t, "events", kinds, []ds.EventKind{ds.EventCleared, ds.EventInserted})
+
+	list.Undo()
+	assertValues(t, "undone", list.ToArray(), []string{"a", "b"})
+}
+
+func TestUnrolledUnmarshalJSONKeepsSubList(t *testing.T) {
+	list := ds.NewUnrolledList[int]()
+	list.AddValues(0, 1, 2, 3, 4)
+	sub, _ := list.SubList(1, 4)
+
+	if err := json.Unmarshal([]byte(`[7, 8]`), sub); err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "sublist", sub.ToArray(), []int{7, 8})
+	assertValues(t, "parent", list.ToArray(), []int{0, 7, 8, 4})
+}
+
+func TestUnmarshalJSONIntoNilField(t *testing.T) {
+	var s struct {
+		L *ds.AnyList[int]
+		U *ds.UnrolledList[int]
+	}
+	if err := json.Unmarshal([]byte(`{"L": [1, 2, 3], "U": [4, 5, 6]}`), &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if i := s.L.IndexOf(2); i != 1 {
+		t.Fatalf("IndexOf(2) = %d, want 1", i)
+	}
+	if !s.L.Contains(3) {
+		t.Fatal("Contains(3) = false, want true")
+	}
+	if !s.L.Remove(1) {
+		t.Fatal("Remove(1) = false, want true")
+	}
+	assertValues(t, "any list", s.L.ToArray(), []int{2, 3})
+
+	if i := s.U.IndexOf(5); i != 1 {
+		t.Fatalf("unrolled IndexOf(5) = %d, want 1", i)
+	}
+	assertValues(t, "unrolled list", s.U.ToArray(), []int{4, 5, 6})
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gbenroscience/linkedlist/listd"
+	"github.com/gbenroscience/linkedlist/listd/client"
+)
+
+// startListd ...Serves a new store on network and returns a client connected to it
+func startListd(t *testing.T, network string, addr string) (*listd.Store, func() *client.Client) {
+	t.Helper()
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := listd.NewStore()
+	srv := listd.NewServer(store)
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(l) }()
+	t.Cleanup(func() {
+		srv.Close()
+		if err := <-done; !errors.Is(err, listd.ErrServerClosed) {
+			t.Errorf("Serve returned %v", err)
+		}
+	})
+
+	dial := func() *client.Client {
+		c, err := client.Dial(network, l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { c.Close() })
+		return c
+	}
+	return store, dial
+}
+
+func TestListdCommands(t *testing.T) {
+	for _, network := range []string{"tcp", "unix"} {
+		t.Run(network, func(t *testing.T) {
+			addr := "127.0.0.1:0"
+			if network == "unix" {
+				addr = filepath.Join(t.TempDir(), "listd.sock")
+			}
+			store, dial := startListd(t, network, addr)
+			c := dial()
+
+			if err := c.Ping(); err != nil {
+				t.Fatal(err)
+			}
+			n, err := c.Push("jobs", "a", "two words", "line\nbreak", `"quoted"`)
+			if err != nil || n != 4 {
+				t.Fatalf("Push returned %d, %v", n, err)
+			}
+			if err := c.Set("jobs", 0, "A"); err != nil {
+				t.Fatal(err)
+			}
+			if v, err := c.Get("jobs", 2); err != nil || v != "line\nbreak" {
+				t.Fatalf("Get returned %q, %v", v, err)
+			}
+			values, err := c.Range("jobs", 1, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertValues(t, "Range", values, []string{"two words", "line\nbreak", `"quoted"`})
+
+			if v, ok, err := c.Pop("jobs"); err != nil || !ok || v != "A" {
+				t.Fatalf("Pop returned %q, %v, %v", v, ok, err)
+			}
+			if n, err := c.Len("jobs"); err != nil || n != 3 {
+				t.Fatalf("Len returned %d, %v", n, err)
+			}
+			// the server's lists are ordinary AnyLists
+			assertValues(t, "store", store.List("jobs").ToArray(), []string{"two words", "line\nbreak", `"quoted"`})
+
+			if _, ok, err := c.Pop("empty"); err != nil || ok {
+				t.Fatalf("Pop of an empty list returned %v, %v", ok, err)
+			}
+			var serverErr client.ServerError
+			if _, err := c.Get("jobs", 7); !errors.As(err, &serverErr) {
+				t.Fatalf("Get out of range returned %v, want a ServerError", err)
+			}
+			// an error leaves the connection usable
+			if err := c.Ping(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestListdBLPop(t *testing.T) {
+	_, dial := startListd(t, "tcp", "127.0.0.1:0")
+	waiter, pusher := dial(), dial()
+
+	got := make(chan string, 1)
+	go func() {
+		v, ok, err := waiter.BLPop("queue", 5*time.Second)
+		if err != nil || !ok {
+			v = "no value"
+		}
+		got <- v
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if _, err := pusher.Push("queue", "job"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case v := <-got:
+		if v != "job" {
+			t.Fatalf("BLPop returned %q", v)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("BLPop was not woken by Push")
+	}
+
+	if _, ok, err := waiter.BLPop("queue", 50*time.Millisecond); err != nil || ok {
+		t.Fatalf("BLPop on an empty list returned %v, %v", ok, err)
+	}
+	if n, err := pusher.Len("queue"); err != nil || n != 0 {
+		t.Fatalf("Len returned %d, %v", n, err)
+	}
+}
+
+func TestListdBLPopClientGone(t *testing.T) {
+	store, dial := startListd(t, "tcp", "127.0.0.1:0")
+	waiter := dial()
+
+	go waiter.BLPop("queue", 0)
+	time.Sleep(50 * time.Millisecond)
+	waiter.Close()
+	time.Sleep(50 * time.Millisecond)
+
+	// nobody is waiting any more, so the value stays in the list
+	store.Push("queue", "job")
+	if n := store.List("queue").Count(); n != 1 {
+		t.Fatalf("the list holds %d values, want 1", n)
+	}
+}
+
+func TestListdLongLine(t *testing.T) {
+	store, dial := startListd(t, "tcp", "127.0.0.1:0")
+	c := dial()
+
+	var serverErr client.ServerError
+	if _, err := c.Push("jobs", strings.Repeat("x", listd.MaxLineLength)); !errors.As(err, &serverErr) {
+		t.Fatalf("Push of a line that is too long returned %v, want a ServerError", err)
+	}
+	if _, ok := store.Lookup("jobs"); ok {
+		t.Fatal("the line that was too long made a list")
+	}
+	// the rest of the line was skipped, so the next command is read as one
+	if n, err := c.Push("jobs", "a"); err != nil || n != 1 {
+		t.Fatalf("Push returned %d, %v", n, err)
+	}
+}
+
+func TestListdUnknownLists(t *testing.T) {
+	store, dial := startListd(t, "tcp", "127.0.0.1:0")
+	c := dial()
+
+	if n, err := c.Len("ghost"); err != nil || n != 0 {
+		t.Fatalf("Len returned %d, %v", n, err)
+	}
+	if values, err := c.Range("ghost", 0, -1); err != nil || len(values) != 0 {
+		t.Fatalf("Range returned %q, %v", values, err)
+	}
+	var serverErr client.ServerError
+	if _, err := c.Get("ghost", 0); !errors.As(err, &serverErr) {
+		t.Fatalf("Get returned %v, want a ServerError", err)
+	}
+	if err := c.Set("ghost", 0, "x"); !errors.As(err, &serverErr) {
+		t.Fatalf("Set returned %v, want a ServerError", err)
+	}
+	if names := store.Names(); len(names) != 0 {
+		t.Fatalf("the store holds %q after commands that do not make lists", names)
+	}
+
+	store.Push("jobs", "a", "b", "c", "d")
+	values, err := store.Range("jobs", 1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "Range", values, []string{"b", "c"})
+	if _, err := store.Range("jobs", 3, 2); err == nil {
+		t.Fatal("Range of 3 to 2 returned no error")
+	}
+}
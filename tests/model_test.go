@@ -0,0 +1,423 @@
+package tests
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+	"github.com/gbenroscience/linkedlist/utils"
+)
+
+// Model-based testing.
+//
+// A sequence of operations is applied to a real list and to a plain slice
+// model side by side, and after every step each list and sublist in play
+// must hold the same values as the model and pass Validate.
+//
+// The model follows the sublist contract: a sublist is a window on its
+// parent, so changes made through it show up in every list above it, and a
+// change made through a list invalidates the sublists taken from it, which
+// are dropped. A sublist that has been emptied loses its place in its parent:
+// adding to it starts a list of its own, which the model then follows as a
+// separate chain of lists.
+//
+// Operations hold raw numbers that are only turned into a list, an index and
+// a value when they are applied, so any sequence is valid. That lets the
+// fuzz target decode arbitrary bytes, and lets a failing sequence be shrunk
+// by simply leaving operations out.
+
+const (
+	// values are drawn from a small range so that Remove and IndexOf find duplicates
+	modelValues = 50
+	// the deepest chain of sublists taken
+	modelDepth = 6
+	// the most chains of lists in play at once
+	modelChains = 4
+)
+
+// modelList - The operations the model runs, over CList, List[int] and AnyList[int]
+type modelList interface {
+	Add(val int)
+	AddVal(val int, index int) bool
+	Set(index int, val int)
+	RemoveIndex(index int) bool
+	Remove(val int)
+	Clear()
+	SubList(start int, end int) (modelList, error)
+	Get(index int) (int, error)
+	IndexOf(val int) int
+	Count() int
+	Values() []int
+	Validate() error
+}
+
+// intList - The methods List[int] and AnyList[int] have in common
+type intList[L any] interface {
+	Add(val int)
+	AddVal(val int, index int) bool
+	Set(index int, val int)
+	RemoveIndex(index int) bool
+	Remove(val int) bool
+	Clear() bool
+	SubList(start int, end int) (L, error)
+	Get(index int) (int, error)
+	IndexOf(val int) int
+	Count() int
+	ToArray() []int
+	Validate() error
+}
+
+type typedModel[L intList[L]] struct {
+	intList[L]
+}
+
+func (m typedModel[L]) Remove(val int) { m.intList.Remove(val) }
+func (m typedModel[L]) Clear()         { m.intList.Clear() }
+func (m typedModel[L]) Values() []int  { return m.ToArray() }
+
+func (m typedModel[L]) SubList(start int, end int) (modelList, error) {
+	sub, err := m.intList.SubList(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return typedModel[L]{sub}, nil
+}
+
+type cListModel struct {
+	l *ds.CList
+}
+
+func (m cListModel) Add(val int)                    { m.l.Add(val) }
+func (m cListModel) AddVal(val int, index int) bool { return m.l.AddVal(val, index) }
+func (m cListModel) Set(index int, val int)         { m.l.Set(index, val) }
+func (m cListModel) RemoveIndex(index int) bool     { return m.l.RemoveIndex(index) }
+func (m cListModel) Remove(val int)                 { m.l.Remove(val) }
+func (m cListModel) Clear()                         { m.l.Clear() }
+func (m cListModel) IndexOf(val int) int            { return m.l.IndexOf(val) }
+func (m cListModel) Count() int                     { return m.l.Count() }
+func (m cListModel) Validate() error                { return m.l.Validate() }
+func (m cListModel) Get(index int) (int, error) {
+	val, err := m.l.Get(index)
+	if err != nil {
+		return 0, err
+	}
+	return val.(int), nil
+}
+
+func (m cListModel) SubList(start int, end int) (modelList, error) {
+	sub, err := m.l.SubList(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return cListModel{sub}, nil
+}
+
+func (m cListModel) Values() []int {
+	values := []int{}
+	for _, v := range m.l.ToArray() {
+		values = append(values, v.(int))
+	}
+	return values
+}
+
+var modelKinds = []struct {
+	name string
+	new  func() modelList
+}{
+	{"CList", func() modelList { return cListModel{ds.NewCList()} }},
+	{"List", func() modelList { return typedModel[*ds.List[int]]{ds.NewList[int]()} }},
+	{"AnyList", func() modelList { return typedModel[*ds.AnyList[int]]{newIntAnyList()} }},
+}
+
+const (
+	opAdd = iota
+	opAddVal
+	opSet
+	opRemoveIndex
+	opRemove
+	opClear
+	opSubList
+	opGet
+	opIndexOf
+	opCount
+)
+
+// modelOp - One operation, as raw numbers: which list, which operation and two arguments
+type modelOp struct {
+	kind, target, a, b int
+}
+
+// randomOps ...Generates n operations, biased towards adding so that lists grow
+func randomOps(rnd *utils.RandomLife, n int) []modelOp {
+	ops := make([]modelOp, n)
+	for i := range ops {
+		kind := rnd.NextInt(opCount + 3)
+		if kind >= opCount {
+			kind = opAdd
+		}
+		ops[i] = modelOp{kind: kind, target: rnd.NextInt(64), a: rnd.NextInt(1 << 16), b: rnd.NextInt(1 << 16)}
+	}
+	return ops
+}
+
+// decodeOps ...Turns fuzzer bytes into operations, four bytes each
+func decodeOps(data []byte) []modelOp {
+	var ops []modelOp
+	for ; len(data) >= 4; data = data[4:] {
+		ops = append(ops, modelOp{kind: int(data[0]) % opCount, target: int(data[1]), a: int(data[2]), b: int(data[3])})
+	}
+	return ops
+}
+
+// modelView - A list in play and the part of its chain's values it holds
+type modelView struct {
+	list         modelList
+	start, count int
+}
+
+// modelChain - A list, the sublists taken from it one inside the other, and the values of the outermost list
+type modelChain struct {
+	views  []modelView
+	values []int
+}
+
+type modelRun struct {
+	chains []*modelChain
+	// what was done, for the report of a failure
+	trace []string
+}
+
+// runModel ...Applies ops to a list made by newList and to the model, and returns the first difference between them
+func runModel(newList func() modelList, ops []modelOp) (r *modelRun, err error) {
+	r = &modelRun{chains: []*modelChain{{views: []modelView{{list: newList()}}}}}
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	for _, op := range ops {
+		if err := r.apply(op); err != nil {
+			return r, err
+		}
+		if err := r.check(); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+func (r *modelRun) apply(op modelOp) error {
+	c := r.chains[op.target/modelDepth%len(r.chains)]
+	v := op.target % len(c.views)
+	view := &c.views[v]
+	n := view.count
+	name := fmt.Sprintf("list%d.%d", slices.Index(r.chains, c), v)
+	val := op.a % modelValues
+
+	if op.kind != opGet && op.kind != opIndexOf && !(op.kind == opSubList && len(c.views) == modelDepth) {
+		// changing a list invalidates the sublists taken from it, and a chain follows one sublist of each list
+		c.views = c.views[:v+1]
+	}
+
+	switch op.kind {
+	case opAdd, opAddVal:
+		index := n
+		if op.kind == opAdd {
+			r.log("%s.Add(%d)", name, val)
+			view.list.Add(val)
+		} else {
+			index = op.b % (n + 1)
+			r.log("%s.AddVal(%d, %d)", name, val, index)
+			if !view.list.AddVal(val, index) {
+				return fmt.Errorf("AddVal(%d, %d) on %d values failed", val, index, n)
+			}
+		}
+		if n == 0 && v > 0 {
+			// an emptied sublist has no place in its parent any more
+			c.views = c.views[:v]
+			r.chains = append(r.chains, &modelChain{views: []modelView{{list: view.list, count: 1}}, values: []int{val}})
+			if len(r.chains) > modelChains {
+				r.chains = r.chains[1:]
+			}
+			return nil
+		}
+		c.insert(v, view.start+index, val)
+
+	case opSet:
+		if n == 0 {
+			return nil
+		}
+		index := op.b % n
+		r.log("%s.Set(%d, %d)", name, index, val)
+		view.list.Set(index, val)
+		c.values[view.start+index] = val
+
+	case opRemoveIndex:
+		index := op.b % (n + 1)
+		r.log("%s.RemoveIndex(%d)", name, index)
+		removed := view.list.RemoveIndex(index)
+		if removed != (index < n) {
+			return fmt.Errorf("RemoveIndex(%d) on %d values = %v", index, n, removed)
+		}
+		if removed {
+			c.delete(v, view.start+index, 1)
+		}
+
+	case opRemove:
+		r.log("%s.Remove(%d)", name, val)
+		view.list.Remove(val)
+		if i := slices.Index(c.values[view.start:view.start+n], val); i >= 0 {
+			c.delete(v, view.start+i, 1)
+		}
+
+	case opClear:
+		r.log("%s.Clear()", name)
+		view.list.Clear()
+		c.delete(v, view.start, n)
+
+	case opSubList:
+		start := op.a % (n + 1)
+		end := start + op.b%(n-start+1)
+		if len(c.views) == modelDepth {
+			return nil
+		}
+		r.log("%s.SubList(%d, %d)", name, start, end)
+		sub, err := view.list.SubList(start, end)
+		if err != nil {
+			return fmt.Errorf("SubList(%d, %d) on %d values: %v", start, end, n, err)
+		}
+		c.views = append(c.views, modelView{list: sub, start: view.start + start, count: end - start})
+
+	case opGet:
+		index := op.b % (n + 1)
+		r.log("%s.Get(%d)", name, index)
+		got, err := view.list.Get(index)
+		if index == n {
+			if err == nil {
+				return fmt.Errorf("Get(%d) on %d values did not fail", index, n)
+			}
+		} else if err != nil || got != c.values[view.start+index] {
+			return fmt.Errorf("Get(%d) = %d, %v, want %d", index, got, err, c.values[view.start+index])
+		}
+
+	case opIndexOf:
+		r.log("%s.IndexOf(%d)", name, val)
+		want := slices.Index(c.values[view.start:view.start+n], val)
+		if got := view.list.IndexOf(val); got != want {
+			return fmt.Errorf("IndexOf(%d) = %d, want %d", val, got, want)
+		}
+	}
+	return nil
+}
+
+// insert ...Inserts val at pos in the chain's values, through view v and the views outside it
+func (c *modelChain) insert(v int, pos int, val int) {
+	c.values = slices.Insert(c.values, pos, val)
+	for i := 0; i <= v; i++ {
+		c.views[i].count++
+	}
+}
+
+// delete ...Deletes n values at pos from the chain's values, through view v and the views outside it
+func (c *modelChain) delete(v int, pos int, n int) {
+	c.values = slices.Delete(c.values, pos, pos+n)
+	for i := 0; i <= v; i++ {
+		c.views[i].count -= n
+	}
+}
+
+func (r *modelRun) check() error {
+	for ci, c := range r.chains {
+		for vi, view := range c.views {
+			want := c.values[view.start : view.start+view.count]
+			if got := view.list.Values(); !slices.Equal(got, want) {
+				return fmt.Errorf("list%d.%d holds %v, want %v", ci, vi, got, want)
+			}
+			if got := view.list.Count(); got != view.count {
+				return fmt.Errorf("list%d.%d.Count() = %d, want %d", ci, vi, got, view.count)
+			}
+			if err := view.list.Validate(); err != nil {
+				return fmt.Errorf("list%d.%d: %v", ci, vi, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *modelRun) log(format string, args ...interface{}) {
+	r.trace = append(r.trace, fmt.Sprintf(format, args...))
+}
+
+// shrinkOps ...Leaves out as many operations as it can while fails still holds, halving the chunks it tries to leave out
+func shrinkOps(ops []modelOp, fails func([]modelOp) bool) []modelOp {
+	for chunk := len(ops) / 2; chunk >= 1; chunk /= 2 {
+		for i := 0; i+chunk <= len(ops); {
+			candidate := slices.Concat(ops[:i], ops[i+chunk:])
+			if fails(candidate) {
+				ops = candidate
+			} else {
+				i += chunk
+			}
+		}
+	}
+	return ops
+}
+
+// reportModelFailure ...Shrinks ops to a short sequence that still fails and reports it
+func reportModelFailure(t *testing.T, newList func() modelList, ops []modelOp, err error) {
+	t.Helper()
+	ops = shrinkOps(ops, func(candidate []modelOp) bool {
+		_, err := runModel(newList, candidate)
+		return err != nil
+	})
+	r, err := runModel(newList, ops)
+	t.Fatalf("%v after %d operation(s):\n\t%s", err, len(r.trace), strings.Join(r.trace, "\n\t"))
+}
+
+func TestModel(t *testing.T) {
+	rnd := testRnd(t)
+
+	for _, kind := range modelKinds {
+		t.Run(kind.name, func(t *testing.T) {
+			for i := 0; i < 200; i++ {
+				ops := randomOps(&rnd, 60)
+				if _, err := runModel(kind.new, ops); err != nil {
+					reportModelFailure(t, kind.new, ops, err)
+				}
+			}
+		})
+	}
+}
+
+func TestShrinkOps(t *testing.T) {
+	var ops []modelOp
+	for i := 0; i < 40; i++ {
+		ops = append(ops, modelOp{kind: opAdd, a: i})
+	}
+	// fails whenever 7 and 23 are both added
+	fails := func(ops []modelOp) bool {
+		return slices.ContainsFunc(ops, func(op modelOp) bool { return op.a == 7 }) &&
+			slices.ContainsFunc(ops, func(op modelOp) bool { return op.a == 23 })
+	}
+	got := shrinkOps(ops, fails)
+	if want := []modelOp{{kind: opAdd, a: 7}, {kind: opAdd, a: 23}}; !slices.Equal(got, want) {
+		t.Fatalf("shrunk to %v, want %v", got, want)
+	}
+}
+
+func FuzzModel(f *testing.F) {
+	// add, take a sublist, add to it, empty it and add again
+	f.Add([]byte{0, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 6, 0, 1, 1, 0, 1, 4, 0, 5, 1, 0, 0, 0, 1, 9, 0})
+	f.Add([]byte{1, 0, 5, 0, 1, 0, 6, 1, 6, 0, 0, 2, 6, 1, 0, 1, 3, 2, 0, 0, 2, 2, 7, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := decodeOps(data)
+		for _, kind := range modelKinds {
+			if _, err := runModel(kind.new, ops); err != nil {
+				reportModelFailure(t, kind.new, ops, err)
+			}
+		}
+	})
+}
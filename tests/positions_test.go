@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+	"github.com/gbenroscience/linkedlist/utils"
+)
+
+func TestPositionIndexAgreesWithPlainList(t *testing.T) {
+	rnd := testRnd(t)
+
+	plain := newIntAnyList()
+	indexed := newIntAnyList().WithPositionIndex()
+
+	for i := 0; i < 3000; i++ {
+		val := rnd.NextInt(1000)
+		switch rnd.NextInt(4) {
+		case 0:
+			plain.Add(val)
+			indexed.Add(val)
+		case 1:
+			index := rnd.NextInt(plain.Count() + 1)
+			plain.AddVal(val, index)
+			indexed.AddVal(val, index)
+		case 2:
+			if plain.Count() > 0 {
+				index := rnd.NextInt(plain.Count())
+				plain.RemoveIndex(index)
+				indexed.RemoveIndex(index)
+			}
+		case 3:
+			if plain.Count() > 0 {
+				index := rnd.NextInt(plain.Count())
+				plain.Set(index, val)
+				indexed.Set(index, val)
+			}
+		}
+
+		if plain.Count() > 0 {
+			index := rnd.NextInt(plain.Count())
+			want, _ := plain.Get(index)
+			got, err := indexed.Get(index)
+			if err != nil || got != want {
+				t.Fatalf("step %d: Get(%d) = %d, %v, want %d", i, index, got, err, want)
+			}
+		}
+	}
+	assertValues(t, "position indexed list", indexed.ToArray(), plain.ToArray())
+}
+
+func TestPositionIndexSubList(t *testing.T) {
+	list := ds.NewList[int]().WithPositionIndex()
+	for i := 0; i < 100; i++ {
+		list.Add(i)
+	}
+
+	sub, err := list.SubList(40, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if v, _ := sub.Get(i); v != 40+i {
+			t.Fatalf("sub.Get(%d) = %d, want %d", i, v, 40+i)
+		}
+	}
+
+	sub.RemoveIndex(5)
+	if v, _ := sub.Get(5); v != 46 {
+		t.Fatalf("after RemoveIndex, sub.Get(5) = %d, want 46", v)
+	}
+	if v, _ := list.Get(45); v != 46 {
+		t.Fatalf("after RemoveIndex, list.Get(45) = %d, want 46", v)
+	}
+	if list.Count() != 99 || sub.Count() != 19 {
+		t.Fatalf("sizes are %d and %d, want 99 and 19", list.Count(), sub.Count())
+	}
+}
+
+func BenchmarkGetRandom(b *testing.B) {
+	const n = 100000
+	build := func(list *ds.AnyList[int]) *ds.AnyList[int] {
+		for i := 0; i < n; i++ {
+			list.Add(i)
+		}
+		return list
+	}
+
+	b.Run("walk", func(b *testing.B) {
+		list := build(newIntAnyList())
+		rnd := utils.NewRnd()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			list.Get(rnd.NextInt(n))
+		}
+	})
+	b.Run("positionIndex", func(b *testing.B) {
+		list := build(newIntAnyList().WithPositionIndex())
+		rnd := utils.NewRnd()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			list.Get(rnd.NextInt(n))
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"container/heap"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+// drain ...Pops every value off pq, in the order they come
+func drain[T any](t *testing.T, pq *ds.PriorityQueue[T]) []T {
+	t.Helper()
+	var values []T
+	for !pq.IsEmpty() {
+		val, _, err := pq.Pop()
+		if err != nil {
+			t.Fatalf("Pop: %v", err)
+		}
+		values = append(values, val)
+	}
+	return values
+}
+
+func TestPriorityQueueOrders(t *testing.T) {
+	rnd := testRnd(t)
+	priorities := make([]int, 200)
+	for i := range priorities {
+		priorities[i] = rnd.NextInt(50)
+	}
+	sorted := slices.Clone(priorities)
+	slices.Sort(sorted)
+
+	lowest := ds.NewPriorityQueue[int](ds.MinFirst)
+	highest := ds.NewPriorityQueue[int](ds.MaxFirst)
+	for _, p := range priorities {
+		lowest.Push(p, float64(p))
+		highest.Push(p, float64(p))
+	}
+	if val, priority, _ := lowest.Peek(); val != sorted[0] || priority != float64(sorted[0]) {
+		t.Fatalf("Peek = %d, %v, want %d", val, priority, sorted[0])
+	}
+	assertValues(t, "min first", drain(t, lowest), sorted)
+	slices.Reverse(sorted)
+	assertValues(t, "max first", drain(t, highest), sorted)
+
+	if _, _, err := lowest.Pop(); !errors.Is(err, ds.ErrEmptyQueue) {
+		t.Fatalf("Pop on an empty queue: %v, want ErrEmptyQueue", err)
+	}
+	if _, _, err := highest.Peek(); !errors.Is(err, ds.ErrEmptyQueue) {
+		t.Fatalf("Peek on an empty queue: %v, want ErrEmptyQueue", err)
+	}
+}
+
+func TestPriorityQueueKeepsPushOrderOfEquals(t *testing.T) {
+	pq := ds.NewPriorityQueue[string](ds.MaxFirst)
+	for _, s := range []string{"a", "b", "c"} {
+		pq.Push(s, 1)
+	}
+	pq.Push("urgent", 2)
+	assertValues(t, "values", drain(t, pq), []string{"urgent", "a", "b", "c"})
+
+	// a value moved among equals takes the place its push gave it
+	first := pq.Push("first", 1)
+	pq.Push("second", 2)
+	pq.Push("third", 2)
+	pq.Update(first, 2)
+	assertValues(t, "after Update", drain(t, pq), []string{"first", "second", "third"})
+
+	kept := pq.Push("kept", 1)
+	pq.Clear()
+	if kept.Queued() || pq.Count() != 0 {
+		t.Fatal("a value survived Clear")
+	}
+}
+
+func TestPriorityQueueUpdateAndRemove(t *testing.T) {
+	pq := ds.NewPriorityQueue[string](ds.MinFirst)
+	a := pq.Push("a", 1)
+	b := pq.Push("b", 2)
+	c := pq.Push("c", 3)
+	d := pq.Push("d", 4)
+
+	if err := pq.Update(d, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := pq.Update(a, 5); err != nil {
+		t.Fatal(err)
+	}
+	if d.Priority() != 0 {
+		t.Fatalf("Priority = %v after Update, want 0", d.Priority())
+	}
+	if err := pq.Remove(c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Queued() {
+		t.Fatal("a removed value is still queued")
+	}
+	if err := pq.Remove(c); !errors.Is(err, ds.ErrNotQueued) {
+		t.Fatalf("Remove twice: %v, want ErrNotQueued", err)
+	}
+	if err := pq.Update(c, 1); !errors.Is(err, ds.ErrNotQueued) {
+		t.Fatalf("Update after Remove: %v, want ErrNotQueued", err)
+	}
+	other := ds.NewPriorityQueue[string](ds.MinFirst)
+	if err := other.Remove(b); !errors.Is(err, ds.ErrNotQueued) {
+		t.Fatalf("Remove from another queue: %v, want ErrNotQueued", err)
+	}
+
+	assertValues(t, "values", drain(t, pq), []string{"d", "b", "a"})
+	if a.Queued() || b.Queued() || d.Queued() {
+		t.Fatal("a popped value is still queued")
+	}
+}
+
+func TestPriorityQueueConcurrent(t *testing.T) {
+	pq := ds.NewPriorityQueue[int](ds.MinFirst)
+	const workers, each = 8, 200
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				h := pq.Push(i, float64(i))
+				if i%4 == 0 {
+					pq.Update(h, float64(-i))
+				}
+				if i%10 == 0 {
+					pq.Pop()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := pq.Count(), workers*(each-each/10); got != want {
+		t.Fatalf("Count = %d, want %d", got, want)
+	}
+	_, last, _ := pq.Peek()
+	for !pq.IsEmpty() {
+		_, priority, _ := pq.Pop()
+		if priority < last {
+			t.Fatalf("popped priority %v after %v", priority, last)
+		}
+		last = priority
+	}
+}
+
+func TestListHeap(t *testing.T) {
+	rnd := testRnd(t)
+	list := newIntAnyList().WithPositionIndex()
+	for i := 0; i < 100; i++ {
+		list.Add(rnd.NextInt(1000))
+	}
+	want := list.ToArray()
+	slices.Sort(want)
+
+	h := ds.NewListHeap(list, func(a, b int) bool { return a < b })
+	heap.Init(h)
+	mustValidate(t, "after Init", list)
+	if first, _ := list.Get(0); first != want[0] {
+		t.Fatalf("index 0 holds %d after Init, want the least value %d", first, want[0])
+	}
+
+	heap.Push(h, -1)
+	want = append([]int{-1}, want...)
+	var got []int
+	for h.Len() > 0 {
+		got = append(got, heap.Pop(h).(int))
+	}
+	assertValues(t, "popped", got, want)
+	mustValidate(t, "after Pop", list)
+}
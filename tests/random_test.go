@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"flag"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/utils"
+)
+
+var seed = flag.Int64("seed", 0, "seed the randomised tests, e.g. with the seed a failed run logged")
+
+// testRnd ...Returns a RandomLife seeded with -seed, or from the clock, and logs the seed if the test fails
+func testRnd(t testing.TB) utils.RandomLife {
+	rnd := utils.NewRnd()
+	if *seed != 0 {
+		rnd = utils.NewRndSeed(*seed)
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("replay with: go test ./tests -run '^%s$' -seed %d", t.Name(), rnd.Seed())
+		}
+	})
+	return rnd
+}
+
+func TestRndSeedReplays(t *testing.T) {
+	a, b := utils.NewRndSeed(42), utils.NewRndSeed(42)
+	for i := 0; i < 100; i++ {
+		if x, y := a.NextInt(1000), b.NextInt(1000); x != y {
+			t.Fatalf("draw %d: %d != %d", i, x, y)
+		}
+	}
+	if a.NextString(12) != b.NextString(12) || a.NextGaussian(0, 1) != b.NextGaussian(0, 1) {
+		t.Fatal("the same seed generated different values")
+	}
+	if a.Seed() != 42 {
+		t.Fatalf("Seed() = %d, want 42", a.Seed())
+	}
+}
+
+func TestRndIsSafeForConcurrentUse(t *testing.T) {
+	rnd := utils.NewRndSeed(1)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		// copies share the source and its lock
+		go func(rnd utils.RandomLife) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if n := rnd.NextInt(10); n < 0 || n >= 10 {
+					t.Errorf("NextInt(10) = %d", n)
+				}
+				rnd.NextFloat()
+				rnd.NextDigits(3)
+			}
+		}(rnd)
+	}
+	wg.Wait()
+}
+
+func TestRndGenerators(t *testing.T) {
+	rnd := testRnd(t)
+
+	s := rnd.NextString(200)
+	if len(s) != 200 || strings.Trim(s, utils.ALPHABET) != "" {
+		t.Fatalf("NextString(200) = %q", s)
+	}
+	if d := rnd.NextDigits(50); len(d) != 50 || strings.Trim(d, utils.DIGITS) != "" {
+		t.Fatalf("NextDigits(50) = %q", d)
+	}
+
+	values := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	rnd.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	if !slices.Equal(sorted, []int{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Fatalf("Shuffle lost values: %v", values)
+	}
+
+	counts := make([]int, 3)
+	for i := 0; i < 3000; i++ {
+		counts[rnd.WeightedChoice([]float64{1, 0, 3})]++
+	}
+	if counts[1] != 0 || counts[2] < 2*counts[0] {
+		t.Fatalf("WeightedChoice picked %v for weights [1 0 3]", counts)
+	}
+	if got := rnd.WeightedChoice([]float64{0, -1}); got != -1 {
+		t.Fatalf("WeightedChoice without positive weights = %d, want -1", got)
+	}
+
+	sum := 0.0
+	for i := 0; i < 2000; i++ {
+		sum += rnd.NextGaussian(10, 2)
+	}
+	if mean := sum / 2000; math.Abs(mean-10) > 0.5 {
+		t.Fatalf("NextGaussian(10, 2) averaged %v", mean)
+	}
+}
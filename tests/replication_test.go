@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+// waitCaughtUp ...Waits until replica has applied every op of log
+func waitCaughtUp[T any](t *testing.T, replica *ds.Replica[T], log *ds.OpLog[T]) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		id, seq := replica.Position()
+		if id == log.ID() && seq == log.Seq() {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the replica is at op %d of log %q, want op %d of log %q", seq, id, log.Seq(), log.ID())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// syncOver ...Connects replica to log through an in-memory pipe and returns a function that disconnects it
+func syncOver[T any](t *testing.T, log *ds.OpLog[T], replica *ds.Replica[T]) func() {
+	primaryEnd, replicaEnd := net.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan struct{})
+	go func() {
+		log.Serve(ctx, primaryEnd)
+		close(served)
+	}()
+	followed := make(chan error, 1)
+	go func() { followed <- replica.Sync(replicaEnd) }()
+
+	return func() {
+		cancel()
+		primaryEnd.Close()
+		replicaEnd.Close()
+		<-served
+		<-followed
+	}
+}
+
+func TestReplicaFollowsPrimary(t *testing.T) {
+	primary := newIntAnyList(1, 2, 3).WithOpLog(0)
+	log := primary.OpLog()
+	replica := ds.NewReplica(newIntAnyList())
+
+	disconnect := syncOver(t, log, replica)
+	defer disconnect()
+
+	primary.Add(4)
+	primary.AddVal(0, 0)
+	primary.Set(2, 20)
+	primary.RemoveIndex(1)
+	sub, _ := primary.SubList(1, 3)
+	sub.AddValues(7, 8)
+	primary.Update(func(tx *ds.Tx[int]) error {
+		tx.RemoveIndex(0)
+		tx.Add(9)
+		return nil
+	})
+	waitCaughtUp(t, replica, log)
+	assertValues(t, "replica", replica.List().ToArray(), primary.ToArray())
+
+	primary.Clear()
+	primary.AddValues(5, 6)
+	waitCaughtUp(t, replica, log)
+	assertValues(t, "replica after Clear", replica.List().ToArray(), []int{5, 6})
+}
+
+func TestReplicaCatchUp(t *testing.T) {
+	primary := newIntAnyList().WithOpLog(4)
+	log := primary.OpLog()
+	replica := ds.NewReplica(newIntAnyList())
+
+	// a new replica starts from a snapshot
+	primary.AddValues(1, 2, 3)
+	disconnect := syncOver(t, log, replica)
+	waitCaughtUp(t, replica, log)
+	disconnect()
+
+	// a short absence is made up from the log tail
+	primary.Add(4)
+	primary.RemoveIndex(0)
+	var kinds []ds.EventKind
+	cancel := replica.List().Subscribe(func(ev ds.Event[int]) {
+		kinds = append(kinds, ev.Kind)
+	})
+	disconnect = syncOver(t, log, replica)
+	waitCaughtUp(t, replica, log)
+	disconnect()
+	cancel()
+	assertValues(t, "after the tail", replica.List().ToArray(), []int{2, 3, 4})
+	assertValues(t, "tail events", kinds, []ds.EventKind{ds.EventInserted, ds.EventRemoved})
+
+	// a long one needs a snapshot again, as the log keeps only 4 ops
+	for i := 0; i < 10; i++ {
+		primary.Add(10 + i)
+	}
+	if _, err := log.Since(4); err != ds.ErrOpLogTruncated {
+		t.Fatalf("Since(4) returned %v, want ErrOpLogTruncated", err)
+	}
+	disconnect = syncOver(t, log, replica)
+	waitCaughtUp(t, replica, log)
+	disconnect()
+	assertValues(t, "after the snapshot", replica.List().ToArray(), primary.ToArray())
+}
+
+func TestReplicaRejectsGaps(t *testing.T) {
+	replica := ds.NewReplica(newIntAnyList())
+	insert := func(seq uint64, v int) ds.Op[int] {
+		return ds.Op[int]{Seq: seq, Event: ds.Event[int]{Kind: ds.EventInserted, Index: 0, Values: []int{v}}}
+	}
+
+	if err := replica.Apply(insert(1, 1)); err != nil {
+		t.Fatal(err)
+	}
+	// a duplicate is skipped, a gap is refused
+	if err := replica.Apply(insert(1, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := replica.Apply(insert(3, 3)); err == nil {
+		t.Fatal("op 3 was applied after op 1")
+	}
+	assertValues(t, "replica", replica.List().ToArray(), []int{1})
+}
+
+func TestOpJSON(t *testing.T) {
+	list := ds.NewList[string]().WithOpLog(0)
+	list.AddValues("a", "b")
+	list.Set(1, "B")
+
+	ops, _ := list.OpLog().Since(0)
+	data, err := json.Marshal(ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"seq":1,"kind":"Inserted","index":0,"values":["a"],"old":"","new":""},` +
+		`{"seq":2,"kind":"Inserted","index":1,"values":["b"],"old":"","new":""},` +
+		`{"seq":3,"kind":"Set","index":1,"old":"b","new":"B"}]`
+	if string(data) != want {
+		t.Fatalf("ops encoded as\n%s\nwant\n%s", data, want)
+	}
+
+	var decoded []ds.Op[string]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded[2].Kind != ds.EventSet || decoded[2].New != "B" {
+		t.Fatalf("decoded %+v", decoded[2])
+	}
+}
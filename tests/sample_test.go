@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+	"github.com/gbenroscience/linkedlist/utils"
+)
+
+func TestShuffleRelinksSubList(t *testing.T) {
+	rnd := testRnd(t)
+	list := newIntAnyList(0, 1, 2, 3, 4, 5, 6, 7, 8, 9).WithPositionIndex().WithHistory(0)
+
+	mirror := list.ToArray()
+	list.Subscribe(func(ev ds.Event[int]) {
+		mirror = applyEvent(mirror, ev)
+	})
+
+	sub, _ := list.SubList(3, 8)
+	sub.Shuffle(&rnd)
+	mustValidate(t, "sublist", sub)
+	mustValidate(t, "list", list)
+
+	got := list.ToArray()
+	assertValues(t, "outside the sublist", slices.Concat(got[:3], got[8:]), []int{0, 1, 2, 8, 9})
+	shuffled := slices.Clone(got[3:8])
+	slices.Sort(shuffled)
+	assertValues(t, "inside the sublist", shuffled, []int{3, 4, 5, 6, 7})
+	assertValues(t, "sublist", sub.ToArray(), got[3:8])
+	assertValues(t, "mirror", mirror, got)
+	for i, v := range got {
+		if x, _ := list.Get(i); x != v {
+			t.Fatalf("Get(%d) = %d after Shuffle, want %d", i, x, v)
+		}
+	}
+
+	if !list.Undo() {
+		t.Fatal("nothing to undo after Shuffle")
+	}
+	assertValues(t, "after Undo", list.ToArray(), []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+}
+
+func TestShuffleReplaysFromSeed(t *testing.T) {
+	a, b := utils.NewRndSeed(7), utils.NewRndSeed(7)
+	l1, l2 := newIntList().WithIndex(), ds.NewCList()
+	for i := 0; i < 20; i++ {
+		l1.Add(i)
+		l2.Add(i)
+	}
+	l1.Shuffle(&a)
+	l2.Shuffle(&b)
+	mustValidate(t, "CList", l2)
+	mustValidate(t, "List", l1)
+
+	for i, v := range l1.ToArray() {
+		if x, _ := l2.Get(i); x != v {
+			t.Fatalf("the same seed shuffled differently: %v and %v", l1.ToArray(), l2.ToArray())
+		}
+		if l1.IndexOf(v) != i {
+			t.Fatalf("IndexOf(%d) = %d after Shuffle, want %d", v, l1.IndexOf(v), i)
+		}
+	}
+}
+
+func TestSample(t *testing.T) {
+	rnd := testRnd(t)
+	list := newIntList(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+	got := list.Sample(&rnd, 4)
+	if len(got) != 4 || !slices.IsSorted(got) || len(slices.Compact(slices.Clone(got))) != 4 {
+		t.Fatalf("Sample(4) = %v, want 4 distinct values in list order", got)
+	}
+	assertValues(t, "Sample(20)", list.Sample(&rnd, 20), list.ToArray())
+
+	counts := make([]int, 4)
+	small := newIntList(0, 1, 2, 3)
+	for i := 0; i < 4000; i++ {
+		counts[small.Sample(&rnd, 1)[0]]++
+	}
+	for v, c := range counts {
+		if c < 800 || c > 1200 {
+			t.Fatalf("value %d was sampled %d times out of 4000: %v", v, c, counts)
+		}
+	}
+}
+
+func TestRandomElement(t *testing.T) {
+	rnd := testRnd(t)
+	if _, err := ds.NewAnyList[string]().RandomElement(&rnd); err == nil {
+		t.Fatal("RandomElement on an empty list did not fail")
+	}
+	list := newStringAnyList("a", "b", "c")
+	for i := 0; i < 20; i++ {
+		if v, err := list.RandomElement(&rnd); err != nil || list.IndexOf(v) < 0 {
+			t.Fatalf("RandomElement() = %q, %v", v, err)
+		}
+	}
+}
+
+func TestReservoirOverForEach(t *testing.T) {
+	rnd := testRnd(t)
+	list := newIntAnyList()
+	for i := 0; i < 100; i++ {
+		list.Add(i)
+	}
+
+	r := ds.NewReservoir[int](&rnd, 3)
+	list.ForEach(r.Offer)
+	got := r.Values()
+	slices.Sort(got)
+	if r.Seen() != 100 || len(got) != 3 || len(slices.Compact(got)) != 3 {
+		t.Fatalf("kept %v after %d values, want 3 distinct values after 100", r.Values(), r.Seen())
+	}
+
+	few := ds.NewReservoir[int](&rnd, 10)
+	newIntList(1, 2).ForEach(few.Offer)
+	assertValues(t, "fewer values than k", few.Values(), []int{1, 2})
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+func newIntList(values ...int) *ds.List[int] {
+	list := ds.NewList[int]()
+	list.AddValues(values...)
+	return list
+}
+
+func newIntAnyList(values ...int) *ds.AnyList[int] {
+	list := ds.NewAnyList[int]()
+	list.Equals = func(val1, val2 int) bool {
+		return val1 == val2
+	}
+	list.AddValues(values...)
+	return list
+}
+
+func hashInt(val int) uint64 {
+	return uint64(val)
+}
+
+func assertValues[T any](t *testing.T, label string, got []T, want []T) {
+	t.Helper()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("%s: got %v, want %v", label, got, want)
+	}
+}
+
+func TestListSetOperations(t *testing.T) {
+	a := newIntList(5, 1, 3, 1, 7, 5, 9)
+	b := newIntList(9, 2, 3, 2, 8)
+
+	assertValues(t, "Distinct", a.Distinct().ToArray(), []int{5, 1, 3, 7, 9})
+	assertValues(t, "Union", a.Union(b).ToArray(), []int{5, 1, 3, 7, 9, 2, 8})
+	assertValues(t, "Intersect", a.Intersect(b).ToArray(), []int{3, 9})
+	assertValues(t, "Difference", a.Difference(b).ToArray(), []int{5, 1, 7})
+	assertValues(t, "SymmetricDifference", a.SymmetricDifference(b).ToArray(), []int{5, 1, 7, 2, 8})
+
+	// the receiver must be left untouched by the non mutating operations
+	assertValues(t, "receiver", a.ToArray(), []int{5, 1, 3, 1, 7, 5, 9})
+
+	if !a.RetainAll(newIntList(1, 5)) {
+		t.Fatalf("RetainAll should report a change")
+	}
+	assertValues(t, "RetainAll", a.ToArray(), []int{5, 1, 1, 5})
+	if a.Count() != 4 {
+		t.Fatalf("RetainAll: size is %d, want 4", a.Count())
+	}
+	if a.RetainAll(newIntList(1, 5)) {
+		t.Fatalf("RetainAll should not report a change the second time")
+	}
+}
+
+func TestListRemoveAllRemovesFirstOccurrences(t *testing.T) {
+	list := newIntList(1, 2, 1, 3, 1, 2)
+	list.RemoveAll(newIntList(1, 2, 1))
+
+	assertValues(t, "RemoveAll", list.ToArray(), []int{3, 1, 2})
+	if list.Count() != 3 {
+		t.Fatalf("RemoveAll: size is %d, want 3", list.Count())
+	}
+}
+
+func TestAnyListSetOperations(t *testing.T) {
+	a := newIntAnyList(5, 1, 3, 1, 7, 5, 9)
+	b := newIntAnyList(9, 2, 3, 2, 8)
+
+	assertValues(t, "DistinctFunc", a.DistinctFunc(hashInt).ToArray(), []int{5, 1, 3, 7, 9})
+	assertValues(t, "UnionFunc", a.UnionFunc(b, hashInt).ToArray(), []int{5, 1, 3, 7, 9, 2, 8})
+	assertValues(t, "IntersectFunc", a.IntersectFunc(b, hashInt).ToArray(), []int{3, 9})
+	assertValues(t, "DifferenceFunc", a.DifferenceFunc(b, hashInt).ToArray(), []int{5, 1, 7})
+	assertValues(t, "SymmetricDifferenceFunc", a.SymmetricDifferenceFunc(b, hashInt).ToArray(), []int{5, 1, 7, 2, 8})
+
+	a.RetainAllFunc(newIntAnyList(1, 5), hashInt)
+	assertValues(t, "RetainAllFunc", a.ToArray(), []int{5, 1, 1, 5})
+
+	a.RemoveAllFunc(newIntAnyList(5, 1), hashInt)
+	assertValues(t, "RemoveAllFunc", a.ToArray(), []int{1, 5})
+}
+
+func TestAnyListSetOperationsWithCollidingHash(t *testing.T) {
+	// Every value lands in the same bucket, so Equals alone must tell them apart
+	constant := func(val int) uint64 {
+		return 42
+	}
+	a := newIntAnyList(4, 4, 6, 8)
+	b := newIntAnyList(6, 10)
+
+	assertValues(t, "DistinctFunc", a.DistinctFunc(constant).ToArray(), []int{4, 6, 8})
+	assertValues(t, "IntersectFunc", a.IntersectFunc(b, constant).ToArray(), []int{6})
+	assertValues(t, "DifferenceFunc", a.DifferenceFunc(b, constant).ToArray(), []int{4, 8})
+}
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+func TestStackLIFO(t *testing.T) {
+	s := ds.NewStack[int](0).WithAllocator(ds.AllocFreeList)
+	if err := s.Push(1, 2, 3); err != nil {
+		t.Fatal(err)
+	}
+	s.Push(4)
+	if top, _ := s.Peek(); top != 4 {
+		t.Fatalf("Peek = %d, want 4", top)
+	}
+	assertValues(t, "values", s.ToArray(), []int{4, 3, 2, 1})
+
+	if top, err := s.Pop(); err != nil || top != 4 {
+		t.Fatalf("Pop = %d, %v, want 4", top, err)
+	}
+	vals, err := s.PopN(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "PopN", vals, []int{3, 2})
+	if _, err := s.PopN(2); !errors.Is(err, ds.ErrEmptyStack) {
+		t.Fatalf("PopN past the bottom: %v, want ErrEmptyStack", err)
+	}
+	if s.Count() != 1 {
+		t.Fatalf("Count = %d after a failed PopN, want 1", s.Count())
+	}
+
+	s.Push(5, 6)
+	assertValues(t, "Drain", s.Drain(), []int{6, 5, 1})
+	if !s.IsEmpty() {
+		t.Fatal("stack not empty after Drain")
+	}
+	if _, err := s.Pop(); !errors.Is(err, ds.ErrEmptyStack) {
+		t.Fatalf("Pop on an empty stack: %v, want ErrEmptyStack", err)
+	}
+	if _, err := s.Peek(); !errors.Is(err, ds.ErrEmptyStack) {
+		t.Fatalf("Peek on an empty stack: %v, want ErrEmptyStack", err)
+	}
+}
+
+func TestStackMaxDepth(t *testing.T) {
+	s := ds.NewStack[string](3)
+	if err := s.Push("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Push("c", "d"); !errors.Is(err, ds.ErrOverflow) {
+		t.Fatalf("Push past the max depth: %v, want ErrOverflow", err)
+	}
+	assertValues(t, "after the overflow", s.ToArray(), []string{"b", "a"})
+	if err := s.Push("c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Push("d"); !errors.Is(err, ds.ErrOverflow) {
+		t.Fatalf("Push on a full stack: %v, want ErrOverflow", err)
+	}
+	s.Pop()
+	if err := s.Push("d"); err != nil {
+		t.Fatalf("Push after a Pop: %v", err)
+	}
+}
+
+func TestStackConcurrent(t *testing.T) {
+	const workers, each = 8, 500
+	s := ds.NewStack[int](workers * each / 2)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	pushed, popped := 0, 0
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < each; i++ {
+				if err := s.Push(i); err == nil {
+					mu.Lock()
+					pushed++
+					mu.Unlock()
+				}
+				if i%3 == 0 {
+					if _, err := s.Pop(); err == nil {
+						mu.Lock()
+						popped++
+						mu.Unlock()
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := s.Count(); got != pushed-popped || got > s.MaxDepth() {
+		t.Fatalf("Count = %d, pushed %d and popped %d, max depth %d", got, pushed, popped, s.MaxDepth())
+	}
+}
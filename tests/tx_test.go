@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+func TestUpdateCommits(t *testing.T) {
+	list := newIntAnyList(1, 2, 3, 4)
+
+	var events []ds.Event[int]
+	list.Subscribe(func(ev ds.Event[int]) {
+		events = append(events, ev)
+	})
+
+	err := list.Update(func(tx *ds.Tx[int]) error {
+		v, err := tx.Get(0)
+		if err != nil {
+			return err
+		}
+		if err := tx.RemoveIndex(0); err != nil {
+			return err
+		}
+		tx.Add(v)
+		return tx.Set(0, 20)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValues(t, "committed", list.ToArray(), []int{20, 3, 4, 1})
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+}
+
+func TestUpdateRollsBackOnError(t *testing.T) {
+	list := ds.NewList[string]().WithHistory(0)
+	list.AddValues("a", "b", "c", "d")
+
+	var events []ds.Event[string]
+	list.Subscribe(func(ev ds.Event[string]) {
+		events = append(events, ev)
+	})
+
+	failure := errors.New("out of stock")
+	err := list.Update(func(tx *ds.Tx[string]) error {
+		tx.RemoveIndex(1)
+		tx.AddVal("x", 0)
+		tx.Set(2, "y")
+		tx.Remove("d")
+		tx.Add("z")
+		tx.Clear()
+		tx.AddValues("p", "q")
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Update returned %v, want %v", err, failure)
+	}
+	assertValues(t, "rolled back", list.ToArray(), []string{"a", "b", "c", "d"})
+	if len(events) != 0 {
+		t.Fatalf("a rolled back transaction must not be reported, got %+v", events)
+	}
+	// the only step left to undo is the initial AddValues
+	list.Undo()
+	if list.Count() != 0 || list.Undo() {
+		t.Fatalf("the rolled back transaction ended up in the history")
+	}
+}
+
+func TestUpdateRollsBackOnPanic(t *testing.T) {
+	list := newIntAnyList(1, 2, 3).WithPositionIndex()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("the panic must be re-raised")
+			}
+		}()
+		list.Update(func(tx *ds.Tx[int]) error {
+			tx.AddVal(9, 1)
+			tx.RemoveIndex(3)
+			panic("boom")
+		})
+	}()
+	assertValues(t, "rolled back", list.ToArray(), []int{1, 2, 3})
+
+	// the lock has been released
+	list.Add(4)
+	assertValues(t, "after panic", list.ToArray(), []int{1, 2, 3, 4})
+}
+
+func TestUpdateIsOneUndoStep(t *testing.T) {
+	list := newIntAnyList(1, 2, 3).WithHistory(0)
+
+	list.Update(func(tx *ds.Tx[int]) error {
+		tx.RemoveIndex(0)
+		tx.Add(1)
+		return nil
+	})
+	assertValues(t, "committed", list.ToArray(), []int{2, 3, 1})
+
+	list.Undo()
+	assertValues(t, "undone", list.ToArray(), []int{1, 2, 3})
+}
+
+func TestUpdateOnSubListLocksTheRoot(t *testing.T) {
+	list := newIntAnyList(1, 2, 3, 4, 5, 6)
+	sub, err := list.SubList(2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started, added := make(chan struct{}), make(chan struct{})
+	go func() {
+		<-started
+		list.Add(99)
+		close(added)
+	}()
+	err = sub.Update(func(tx *ds.Tx[int]) error {
+		close(started)
+		tx.Set(0, 30)
+		select {
+		case <-added:
+			t.Error("the parent changed during a transaction on its sublist")
+		case <-time.After(50 * time.Millisecond):
+		}
+		return errors.New("failure")
+	})
+	if err == nil {
+		t.Fatal("the transaction must fail")
+	}
+	<-added
+
+	// the rollback only reverts the transaction's own changes
+	assertValues(t, "list", list.ToArray(), []int{1, 2, 3, 4, 5, 6, 99})
+	assertValues(t, "sublist", sub.ToArray(), []int{3, 4})
+}
+
+func TestRollbackOfEmptiedSubList(t *testing.T) {
+	list := newIntAnyList(0, 1, 2, 3, 4, 5, 6).WithPositionIndex()
+	sub, err := list.SubList(2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sub.Update(func(tx *ds.Tx[int]) error {
+		tx.Clear()
+		return errors.New("failure")
+	})
+	if err == nil {
+		t.Fatal("the transaction must fail")
+	}
+	assertValues(t, "list rolled back", list.ToArray(), []int{0, 1, 2, 3, 4, 5, 6})
+	assertValues(t, "sublist rolled back", sub.ToArray(), []int{2, 3, 4})
+	mustValidate(t, "after the rollback", list)
+
+	other := newIntList(0, 1, 2, 3, 4)
+	window, err := other.SubList(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = window.Update(func(tx *ds.Tx[int]) error {
+		tx.Remove(1)
+		tx.Remove(2)
+		tx.Add(99)
+		return errors.New("failure")
+	})
+	if err == nil {
+		t.Fatal("the transaction must fail")
+	}
+	assertValues(t, "list rolled back", other.ToArray(), []int{0, 1, 2, 3, 4})
+	assertValues(t, "sublist rolled back", window.ToArray(), []int{1, 2})
+	mustValidate(t, "after the rollback", other)
+
+	// the sublist still works as a window on its parent
+	window.Add(7)
+	assertValues(t, "added through the sublist", other.ToArray(), []int{0, 1, 2, 7, 3, 4})
+}
@@ -0,0 +1,236 @@
+package tests
+
+import (
+	"container/list"
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+	"github.com/gbenroscience/linkedlist/utils"
+)
+
+func newIntUnrolledList(values ...int) *ds.UnrolledList[int] {
+	list := ds.NewUnrolledList[int]()
+	list.Equals = func(val1, val2 int) bool {
+		return val1 == val2
+	}
+	list.AddValues(values...)
+	return list
+}
+
+func TestUnrolledListAgreesWithSlice(t *testing.T) {
+	rnd := testRnd(t)
+
+	list := newIntUnrolledList()
+	var model []int
+
+	for i := 0; i < 5000; i++ {
+		val := rnd.NextInt(100)
+		switch rnd.NextInt(5) {
+		case 0, 1:
+			list.Add(val)
+			model = append(model, val)
+		case 2:
+			index := rnd.NextInt(len(model) + 1)
+			list.AddVal(val, index)
+			model = append(model[:index], append([]int{val}, model[index:]...)...)
+		case 3:
+			if len(model) > 0 {
+				index := rnd.NextInt(len(model))
+				list.RemoveIndex(index)
+				model = append(model[:index], model[index+1:]...)
+			}
+		case 4:
+			if len(model) > 0 {
+				index := rnd.NextInt(len(model))
+				list.Set(index, val)
+				model[index] = val
+			}
+		}
+		if list.Count() != len(model) {
+			t.Fatalf("step %d: Count() = %d, want %d", i, list.Count(), len(model))
+		}
+	}
+	assertValues(t, "unrolled list", list.ToArray(), model)
+
+	for i, want := range model {
+		if got, _ := list.Get(i); got != want {
+			t.Fatalf("Get(%d) = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestUnrolledSubList(t *testing.T) {
+	list := newIntUnrolledList()
+	for i := 0; i < 200; i++ {
+		list.Add(i)
+	}
+
+	sub, err := list.SubList(100, 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nested, err := sub.SubList(10, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nested.Add(-1)
+	if v, _ := list.Get(120); v != -1 {
+		t.Fatalf("list.Get(120) = %d, want -1", v)
+	}
+	if list.Count() != 201 || sub.Count() != 51 || nested.Count() != 11 {
+		t.Fatalf("sizes are %d, %d, %d, want 201, 51, 11", list.Count(), sub.Count(), nested.Count())
+	}
+
+	sub.RemoveIndex(0)
+	if v, _ := list.Get(100); v != 101 {
+		t.Fatalf("list.Get(100) = %d, want 101", v)
+	}
+	if i := sub.IndexOf(149); i != 49 {
+		t.Fatalf("sub.IndexOf(149) = %d, want 49", i)
+	}
+
+	sub.Clear()
+	if list.Count() != 150 || sub.Count() != 0 {
+		t.Fatalf("after Clear, sizes are %d and %d, want 150 and 0", list.Count(), sub.Count())
+	}
+	if v, _ := list.Get(100); v != 150 {
+		t.Fatalf("after Clear, list.Get(100) = %d, want 150", v)
+	}
+
+	// a cleared view is detached: adding to it leaves the parent alone
+	sub.Add(7)
+	if list.Count() != 150 {
+		t.Fatalf("cleared sublist still writes to its parent")
+	}
+}
+
+const iterationSize = 100000
+
+func BenchmarkIterate(b *testing.B) {
+	b.Run("UnrolledList", func(b *testing.B) {
+		list := newIntUnrolledList()
+		for i := 0; i < iterationSize; i++ {
+			list.Add(i)
+		}
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			sum := 0
+			list.ForEach(func(val int) bool {
+				sum += val
+				return true
+			})
+		}
+	})
+	b.Run("AnyList", func(b *testing.B) {
+		list := newIntAnyList()
+		for i := 0; i < iterationSize; i++ {
+			list.Add(i)
+		}
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			sum := 0
+			list.ForEach(func(val int) bool {
+				sum += val
+				return true
+			})
+		}
+	})
+	b.Run("container/list", func(b *testing.B) {
+		l := list.New()
+		for i := 0; i < iterationSize; i++ {
+			l.PushBack(i)
+		}
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			sum := 0
+			for e := l.Front(); e != nil; e = e.Next() {
+				sum += e.Value.(int)
+			}
+		}
+	})
+}
+
+func BenchmarkAppend(b *testing.B) {
+	b.Run("UnrolledList", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			list := ds.NewUnrolledList[int]()
+			for i := 0; i < iterationSize; i++ {
+				list.Add(i)
+			}
+		}
+	})
+	b.Run("AnyList", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			list := ds.NewAnyList[int]()
+			for i := 0; i < iterationSize; i++ {
+				list.Add(i)
+			}
+		}
+	})
+	b.Run("container/list", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			l := list.New()
+			for i := 0; i < iterationSize; i++ {
+				l.PushBack(i)
+			}
+		}
+	})
+}
+
+func BenchmarkUnrolledGetRandom(b *testing.B) {
+	list := newIntUnrolledList()
+	for i := 0; i < iterationSize; i++ {
+		list.Add(i)
+	}
+	rnd := utils.NewRnd()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		list.Get(rnd.NextInt(iterationSize))
+	}
+}
+
+func TestUnrolledSubListAfterParentCleared(t *testing.T) {
+	list := newIntUnrolledList(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	sub, _ := list.SubList(2, 8)
+	inner, _ := sub.SubList(1, 3)
+
+	list.Clear()
+	for _, view := range []*ds.UnrolledList[int]{sub, inner} {
+		if view.Count() != 0 || len(view.ToArray()) != 0 {
+			t.Fatalf("a view of a cleared list holds %d values: %v", view.Count(), view.ToArray())
+		}
+		if _, err := view.Get(0); err == nil {
+			t.Fatal("Get(0) on a view of a cleared list did not fail")
+		}
+	}
+
+	// the view is empty, not broken: values added to it go into the parent
+	sub.Add(7)
+	assertValues(t, "list", list.ToArray(), []int{7})
+}
+
+func TestUnrolledSubListAfterParentShrank(t *testing.T) {
+	list := newIntUnrolledList()
+	for i := 0; i < 200; i++ {
+		list.Add(i)
+	}
+	sub, _ := list.SubList(150, 200)
+
+	for i := 0; i < 20; i++ {
+		list.RemoveIndex(0)
+	}
+	// the parent holds 180 values, so 30 are left of the window
+	if sub.Count() != 30 || len(sub.ToArray()) != 30 {
+		t.Fatalf("Count = %d and ToArray holds %d values, want 30", sub.Count(), len(sub.ToArray()))
+	}
+	if v, err := sub.Get(29); err != nil || v != 199 {
+		t.Fatalf("Get(29) = %d, %v, want 199", v, err)
+	}
+	if _, err := sub.Get(30); err == nil {
+		t.Fatal("Get(30) past what is left of the window did not fail")
+	}
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/gbenroscience/linkedlist/ds"
+)
+
+// mustValidate ...Fails the test if list, or a list above it, is corrupt
+func mustValidate(t *testing.T, label string, list interface{ Validate() error }) {
+	t.Helper()
+	if err := list.Validate(); err != nil {
+		t.Fatalf("%s: %v", label, err)
+	}
+}
+
+func TestSubListEditsAtTheEnds(t *testing.T) {
+	ranges := []struct {
+		name       string
+		start, end int
+	}{
+		{"head", 0, 3},
+		{"tail", 5, 8},
+		{"whole", 0, 8},
+		{"middle", 2, 6},
+	}
+	for _, r := range ranges {
+		list := newIntAnyList(0, 1, 2, 3, 4, 5, 6, 7)
+		sub, err := list.SubList(r.start, r.end)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inner, err := sub.SubList(0, sub.Count())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mirror := list.ToArray()
+		edit := func(label string, apply func()) {
+			apply()
+			mustValidate(t, r.name+": "+label, inner)
+			mustValidate(t, r.name+": "+label, sub)
+			mustValidate(t, r.name+": "+label, list)
+			assertValues(t, r.name+": "+label, list.ToArray(), mirror)
+		}
+
+		edit("prepend", func() {
+			inner.AddVal(100, 0)
+			mirror = insertAt(mirror, r.start, 100)
+		})
+		edit("append", func() {
+			inner.Add(101)
+			mirror = insertAt(mirror, r.end+1, 101)
+		})
+		edit("remove first", func() {
+			inner.RemoveIndex(0)
+			mirror = removeAt(mirror, r.start)
+		})
+		edit("remove last", func() {
+			inner.RemoveIndex(inner.Count() - 1)
+			mirror = removeAt(mirror, r.end)
+		})
+		if sub.Count() != r.end-r.start || inner.Count() != sub.Count() {
+			t.Fatalf("%s: sublists hold %d and %d values, want %d", r.name, sub.Count(), inner.Count(), r.end-r.start)
+		}
+		edit("clear", func() {
+			inner.Clear()
+			mirror = append(mirror[:r.start], mirror[r.end:]...)
+		})
+		if sub.Count() != 0 || list.Count() != len(mirror) {
+			t.Fatalf("%s: sizes after Clear are %d and %d, want 0 and %d", r.name, sub.Count(), list.Count(), len(mirror))
+		}
+	}
+}
+
+func insertAt(values []int, index int, val int) []int {
+	values = append(values, 0)
+	copy(values[index+1:], values[index:])
+	values[index] = val
+	return values
+}
+
+func removeAt(values []int, index int) []int {
+	return append(values[:index], values[index+1:]...)
+}
+
+func TestRandomSubListEditsStayValid(t *testing.T) {
+	rnd := testRnd(t)
+	list := ds.NewList[int]().WithPositionIndex()
+	mirror := []int{}
+	for i := 0; i < 40; i++ {
+		list.Add(i)
+		mirror = append(mirror, i)
+	}
+
+	for i := 0; i < 1000; i++ {
+		n := list.Count()
+		start := rnd.NextInt(n)
+		end := start + 1 + rnd.NextInt(n-start)
+		sub, err := list.SubList(start, end)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch rnd.NextInt(4) {
+		case 0:
+			at := rnd.NextInt(end - start + 1)
+			sub.AddVal(-i, at)
+			mirror = insertAt(mirror, start+at, -i)
+		case 1:
+			at := rnd.NextInt(end - start)
+			sub.RemoveIndex(at)
+			mirror = removeAt(mirror, start+at)
+		case 2:
+			sub.Add(-i)
+			mirror = insertAt(mirror, end, -i)
+		case 3:
+			if end-start < 5 {
+				sub.Clear()
+				mirror = append(mirror[:start], mirror[end:]...)
+			}
+		}
+		mustValidate(t, "sublist", sub)
+		mustValidate(t, "list", list)
+		if list.Count() < 10 {
+			for j := 0; j < 30; j++ {
+				list.Add(j)
+				mirror = append(mirror, j)
+			}
+		}
+	}
+	assertValues(t, "values", list.ToArray(), mirror)
+}
+
+func TestClearedSubListBecomesIndependent(t *testing.T) {
+	list := ds.NewCList()
+	list.AddValues(1, 2, 3, 4, 5)
+	sub, _ := list.SubList(1, 3)
+
+	sub.Clear()
+	sub.Add(9)
+
+	mustValidate(t, "list", list)
+	mustValidate(t, "sublist", sub)
+	assertValues(t, "list", list.ToArray(), []interface{}{1, 4, 5})
+	assertValues(t, "sublist", sub.ToArray(), []interface{}{9})
+}
+
+func TestValidateSpotsStaleSubList(t *testing.T) {
+	list := newIntList(1, 2, 3, 4, 5)
+	sub, _ := list.SubList(1, 3)
+
+	// the parent drops the first node of the sublist behind its back
+	list.RemoveIndex(1)
+
+	if err := sub.Validate(); err == nil {
+		t.Fatalf("Validate must report a sublist whose first node is gone")
+	}
+	mustValidate(t, "list", list)
+}
@@ -2,13 +2,22 @@ package utils
 
 import (
 	"math/rand"
+	"strings"
 	"sync"
 	"time"
 )
 
-// RandomLife ...
+// RandomLife ...A source of random test data that is safe for concurrent use and can be replayed from its seed.
+// Make it with NewRnd or NewRndSeed; copies share the same source and lock.
 type RandomLife struct {
+	// SeededRand is the source the methods draw from, under the shared lock.
+	//
+	// Deprecated: SeededRand is not safe for concurrent use and drawing from it directly bypasses the lock;
+	// use the methods of RandomLife instead.
 	SeededRand *rand.Rand
+	seed       int64
+	// guards SeededRand, which is not safe for concurrent use; shared by every copy
+	mu *sync.Mutex
 }
 
 // Letters of the alphabet in upper and lower case
@@ -17,72 +26,135 @@ const (
 	DIGITS   = "0123456789"
 )
 
-// NewRnd ...
+// NewRnd ...Seeds from the clock; log Seed() to be able to replay a run with NewRndSeed
 func NewRnd() RandomLife {
+	return NewRndSeed(time.Now().UnixNano())
+}
+
+// NewRndSeed ...Seeds with seed, so the same seed produces the same values
+func NewRndSeed(seed int64) RandomLife {
 	return RandomLife{
-		SeededRand: rand.New(rand.NewSource(time.Now().UnixNano())),
+		SeededRand: rand.New(rand.NewSource(seed)),
+		seed:       seed,
+		mu:         &sync.Mutex{},
 	}
 }
 
-//NextInt - Generates a number between 0 and max, max. excluded
-func (rnd *RandomLife) NextInt(max int) int {
-
-	var mu sync.Mutex
+// Seed ...Returns the seed the RandomLife was made with
+func (rnd *RandomLife) Seed() int64 {
+	return rnd.seed
+}
 
-	// lock/unlock when accessing the rand from a goroutine
-	mu.Lock()
-	i := 0 + rnd.SeededRand.Intn(max)
-	mu.Unlock()
+// lock ...Takes the shared lock. A RandomLife that was not made by NewRnd or NewRndSeed has none and is not safe for concurrent use.
+func (rnd *RandomLife) lock() {
+	if rnd.mu != nil {
+		rnd.mu.Lock()
+	}
+}
 
-	return i
+func (rnd *RandomLife) unlock() {
+	if rnd.mu != nil {
+		rnd.mu.Unlock()
+	}
+}
 
+// NextInt - Generates a number between 0 and max, max. excluded
+func (rnd *RandomLife) NextInt(max int) int {
+	defer rnd.unlock()
+	rnd.lock()
+	return rnd.SeededRand.Intn(max)
 }
 
-//NextInt - Generates a number between 0 and max, max. excluded
+// NextBool - Generates true or false with even odds
 func (rnd *RandomLife) NextBool() bool {
+	defer rnd.unlock()
+	rnd.lock()
+	return rnd.SeededRand.Intn(2) == 1
+}
 
-	var mu sync.Mutex
+// NextFloat - Generates a number between 0 and 1, 1 excluded
+func (rnd *RandomLife) NextFloat() float64 {
+	defer rnd.unlock()
+	rnd.lock()
+	return rnd.SeededRand.Float64()
+}
 
-	// lock/unlock when accessing the rand from a goroutine
-	mu.Lock()
-	i := 0 + rnd.SeededRand.Intn(2)
-	mu.Unlock()
+// NextGaussian ...Generates a normally distributed number with the given mean and standard deviation
+func (rnd *RandomLife) NextGaussian(mean float64, stddev float64) float64 {
+	defer rnd.unlock()
+	rnd.lock()
+	return mean + rnd.SeededRand.NormFloat64()*stddev
+}
 
-	return i == 1
+// NextString ...Generates a string of length characters picked from ALPHABET
+func (rnd *RandomLife) NextString(length int) string {
+	return rnd.NextStringFrom(ALPHABET, length)
+}
 
+// NextDigits ...Generates a string of length characters picked from DIGITS
+func (rnd *RandomLife) NextDigits(length int) string {
+	return rnd.NextStringFrom(DIGITS, length)
 }
 
-//NextFloat - Generates a number between 0 and 1
-func (rnd *RandomLife) NextFloat() float64 {
+// NextStringFrom ...Generates a string of length bytes picked from chars
+func (rnd *RandomLife) NextStringFrom(chars string, length int) string {
+	defer rnd.unlock()
+	rnd.lock()
 
-	var mu sync.Mutex
+	var b strings.Builder
+	b.Grow(length)
+	for i := 0; i < length; i++ {
+		b.WriteByte(chars[rnd.SeededRand.Intn(len(chars))])
+	}
+	return b.String()
+}
 
-	// lock/unlock when accessing the rand from a goroutine
-	mu.Lock()
-	i := 0 + rnd.SeededRand.Float64()
-	mu.Unlock()
+// Shuffle ...Puts n elements in a random order (Fisher–Yates), calling swap to exchange the elements at i and j.
+// swap runs without the lock held, so it may use rnd.
+func (rnd *RandomLife) Shuffle(n int, swap func(i int, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, rnd.NextInt(i+1))
+	}
+}
 
-	return i
+// WeightedChoice ...Picks an index of weights, each with a chance proportional to its weight.
+// Weights <= 0 are never picked; -1 is returned when no weight is positive.
+func (rnd *RandomLife) WeightedChoice(weights []float64) int {
+	total := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total == 0 {
+		return -1
+	}
 
+	r := rnd.NextFloat() * total
+	last := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if r < w {
+			return i
+		}
+		r -= w
+		last = i
+	}
+	// rounding left r just above the sum of the weights
+	return last
 }
 
-//GetArrEntryRndInt -
+// GetArrEntryRndInt - Picks an entry of arr
 func (rnd *RandomLife) GetArrEntryRndInt(arr []int) int {
-
-	var mu sync.Mutex
-
-	// lock/unlock when accessing the rand from a goroutine
-	mu.Lock()
-	i := rnd.SeededRand.Intn(len(arr))
-
-	name := arr[i]
-	mu.Unlock()
-
-	return name
+	return arr[rnd.NextInt(len(arr))]
 }
 
 // GenerateRndFloat ...Supply min and max
 func (rnd *RandomLife) GenerateRndFloat(min float32, max float32) float32 {
+	defer rnd.unlock()
+	rnd.lock()
 	return min + rnd.SeededRand.Float32()*(max-min)
 }
 